@@ -0,0 +1,375 @@
+package certinventory
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/util/cert"
+	"k8s.io/klog/v2"
+
+	configversionedclient "github.com/openshift/client-go/config/clientset/versioned"
+	configexternalinformers "github.com/openshift/client-go/config/informers/externalversions"
+	"github.com/openshift/library-go/pkg/operator/certrotation"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/certrotationcontroller"
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/namedcertificatescontroller"
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/operatorclient"
+)
+
+const (
+	// userServingCertName is the default user serving certificate synced to the target namespace. The named user serving
+	// certificates are listed from the bundle secret, the legacy copies of the first ones are skipped.
+	userServingCertName = "user-serving-cert"
+	// certificateTypeUser marks user provided certificates, they are not rotated by the operator.
+	certificateTypeUser certrotation.CertificateType = "user"
+)
+
+// Options holds values to drive the cert-inventory command.
+type Options struct {
+	Kubeconfig string
+	Output     string
+
+	KubeClient    kubernetes.Interface
+	ConfigClient  configversionedclient.Interface
+	DynamicClient dynamic.Interface
+	Out           io.Writer
+}
+
+// Certificate is a single certificate found in a managed secret or config map.
+type Certificate struct {
+	Namespace string                       `json:"namespace"`
+	Name      string                       `json:"name"`
+	Kind      string                       `json:"kind"`
+	Type      certrotation.CertificateType `json:"type"`
+	Subject   string                       `json:"subject,omitempty"`
+	Issuer    string                       `json:"issuer,omitempty"`
+	SANs      []string                     `json:"sans,omitempty"`
+	NotBefore *time.Time                   `json:"notBefore,omitempty"`
+	NotAfter  *time.Time                   `json:"notAfter,omitempty"`
+	// RefreshTime is when the operator rotates the certificate at the latest.
+	RefreshTime *time.Time `json:"refreshTime,omitempty"`
+	Consumer    string     `json:"consumer"`
+	Error       string     `json:"error,omitempty"`
+}
+
+// NewCertInventoryCommand creates the cert-inventory command.
+func NewCertInventoryCommand() *cobra.Command {
+	o := &Options{
+		Output: "table",
+		Out:    os.Stdout,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "cert-inventory",
+		Short: "List the certificates managed by the operator with their validity, refresh time and consumer",
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.Validate(); err != nil {
+				klog.Fatal(err)
+			}
+			if err := o.Complete(); err != nil {
+				klog.Fatal(err)
+			}
+			if err := o.Run(context.Background()); err != nil {
+				klog.Fatal(err)
+			}
+		},
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.Kubeconfig, "kubeconfig", o.Kubeconfig, "Path to the kubeconfig file. Defaults to the in-cluster configuration.")
+	fs.StringVarP(&o.Output, "output", "o", o.Output, "Output format, one of table, json or csv.")
+}
+
+// Validate verifies the inputs.
+func (o *Options) Validate() error {
+	switch o.Output {
+	case "table", "json", "csv":
+		return nil
+	}
+	return fmt.Errorf("unsupported --output %q, must be table, json or csv", o.Output)
+}
+
+// Complete fills in missing values before command execution.
+func (o *Options) Complete() error {
+	if o.KubeClient != nil {
+		return nil
+	}
+	restConfig, err := clientcmd.BuildConfigFromFlags("", o.Kubeconfig)
+	if err != nil {
+		return err
+	}
+	o.KubeClient, err = kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	o.ConfigClient, err = configversionedclient.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	o.DynamicClient, err = dynamic.NewForConfig(restConfig)
+	return err
+}
+
+// Run prints every certificate the cert rotation controller manages, the user serving certificates and the client CA bundle.
+func (o *Options) Run(ctx context.Context) error {
+	inventory, err := managedCertificates(ctx, o.KubeClient, o.ConfigClient, o.DynamicClient)
+	if err != nil {
+		return err
+	}
+	certificates, err := collect(ctx, o.KubeClient, inventory)
+	if err != nil {
+		return err
+	}
+	return printCertificates(o.Out, o.Output, certificates)
+}
+
+// managedCertificates builds the cert rotation controller the way the operator does, without starting it, to read its inventory.
+func managedCertificates(ctx context.Context, kubeClient kubernetes.Interface, configClient configversionedclient.Interface, dynamicClient dynamic.Interface) ([]certrotationcontroller.ManagedCertificate, error) {
+	certRotationScale, err := certrotation.GetCertRotationScale(ctx, kubeClient, operatorclient.GlobalUserSpecifiedConfigNamespace)
+	if err != nil {
+		return nil, err
+	}
+	defaultCertRotationConfig := certrotationcontroller.DefaultRotationConfig(certRotationScale)
+	certRotationConfig, err := certrotationcontroller.GetRotationConfig(ctx, kubeClient, defaultCertRotationConfig)
+	if err != nil {
+		// like the operator, which reports the invalid config
+		klog.Warningf("Using the default certificate rotation periods: %v", err)
+		certRotationConfig = defaultCertRotationConfig
+	}
+	certRotationController, err := certrotationcontroller.NewCertRotationController(
+		kubeClient,
+		nil,
+		configexternalinformers.NewSharedInformerFactory(configClient, 0),
+		dynamicinformer.NewDynamicSharedInformerFactory(dynamicClient, 0).ForResource(certrotationcontroller.DNSResource),
+		v1helpers.NewKubeInformersForNamespaces(
+			kubeClient,
+			operatorclient.GlobalMachineSpecifiedConfigNamespace,
+			operatorclient.OperatorNamespace,
+			operatorclient.TargetNamespace,
+		),
+		events.NewInMemoryRecorder("cert-inventory"),
+		certRotationConfig,
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return certRotationController.Inventory(), nil
+}
+
+// collect reads the certificates of the inventory, the user serving certificates and the client CA bundle from the cluster.
+func collect(ctx context.Context, kubeClient kubernetes.Interface, inventory []certrotationcontroller.ManagedCertificate) ([]Certificate, error) {
+	var certificates []Certificate
+	// the inventory lists signers before the targets they sign
+	signerNotBefore := map[string]time.Time{}
+	for _, managed := range inventory {
+		if managed.Type == certrotation.CertificateTypeCABundle {
+			configMap, err := kubeClient.CoreV1().ConfigMaps(managed.Namespace).Get(ctx, managed.Name, metav1.GetOptions{})
+			if err != nil && !apierrors.IsNotFound(err) {
+				return nil, err
+			}
+			certificates = append(certificates, configMapCertificates(configMap, err, managed)...)
+			continue
+		}
+		secret, err := kubeClient.CoreV1().Secrets(managed.Namespace).Get(ctx, managed.Name, metav1.GetOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		certificate := secretCertificate(secret, err, managed)
+		if certificate.NotBefore != nil {
+			if managed.Type == certrotation.CertificateTypeSigner {
+				signerNotBefore[managed.Namespace+"/"+managed.Name] = *certificate.NotBefore
+			}
+			if refreshTime, ok := managed.RefreshTime(*certificate.NotBefore, *certificate.NotAfter, signerNotBefore[managed.SignerNamespace+"/"+managed.SignerName]); ok {
+				certificate.RefreshTime = &refreshTime
+			}
+		}
+		certificates = append(certificates, certificate)
+	}
+
+	secrets, err := kubeClient.CoreV1().Secrets(operatorclient.TargetNamespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		switch secret.Name {
+		case userServingCertName:
+			certificates = append(certificates, secretCertificate(secret, nil, certrotationcontroller.ManagedCertificate{
+				Namespace: secret.Namespace,
+				Name:      secret.Name,
+				Type:      certificateTypeUser,
+				Consumer:  "kube-apiserver",
+			}))
+		case namedcertificatescontroller.BundleSecretName:
+			certificates = append(certificates, bundledCertificates(secret)...)
+		}
+	}
+
+	clientCA := certrotationcontroller.ManagedCertificate{
+		Namespace: operatorclient.TargetNamespace,
+		Name:      "client-ca",
+		Type:      certrotation.CertificateTypeCABundle,
+		Consumer:  "kube-apiserver",
+	}
+	configMap, err := kubeClient.CoreV1().ConfigMaps(clientCA.Namespace).Get(ctx, clientCA.Name, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+	certificates = append(certificates, configMapCertificates(configMap, err, clientCA)...)
+
+	sort.SliceStable(certificates, func(i, j int) bool {
+		if certificates[i].Namespace != certificates[j].Namespace {
+			return certificates[i].Namespace < certificates[j].Namespace
+		}
+		return certificates[i].Name < certificates[j].Name
+	})
+	return certificates, nil
+}
+
+// secretCertificate returns the leaf certificate of the secret, or a certificate with an error if it cannot be read.
+func secretCertificate(secret *corev1.Secret, getErr error, managed certrotationcontroller.ManagedCertificate) Certificate {
+	certificate := Certificate{
+		Namespace: managed.Namespace,
+		Name:      managed.Name,
+		Kind:      "Secret",
+		Type:      managed.Type,
+		Consumer:  managed.Consumer,
+	}
+	if getErr != nil {
+		certificate.Error = getErr.Error()
+		return certificate
+	}
+	certs, err := cert.ParseCertsPEM(secret.Data[corev1.TLSCertKey])
+	if err != nil {
+		certificate.Error = fmt.Sprintf("failed to parse %s: %v", corev1.TLSCertKey, err)
+		return certificate
+	}
+	describe(&certificate, certs[0])
+	return certificate
+}
+
+// bundledCertificates returns the leaf certificate of every user secret in the named certificates bundle secret.
+func bundledCertificates(secret *corev1.Secret) []Certificate {
+	var certificates []Certificate
+	for key, data := range secret.Data {
+		if !strings.HasSuffix(key, ".crt") {
+			continue
+		}
+		sourceSecretName := strings.TrimSuffix(key, ".crt")
+		certificates = append(certificates, secretCertificate(&corev1.Secret{Data: map[string][]byte{corev1.TLSCertKey: data}}, nil, certrotationcontroller.ManagedCertificate{
+			Namespace: secret.Namespace,
+			Name:      secret.Name + "/" + sourceSecretName,
+			Type:      certificateTypeUser,
+			Consumer:  "kube-apiserver",
+		}))
+	}
+	return certificates
+}
+
+// configMapCertificates returns every certificate of the CA bundle, or a certificate with an error if it cannot be read.
+func configMapCertificates(configMap *corev1.ConfigMap, getErr error, managed certrotationcontroller.ManagedCertificate) []Certificate {
+	certificate := Certificate{
+		Namespace: managed.Namespace,
+		Name:      managed.Name,
+		Kind:      "ConfigMap",
+		Type:      managed.Type,
+		Consumer:  managed.Consumer,
+	}
+	if getErr != nil {
+		certificate.Error = getErr.Error()
+		return []Certificate{certificate}
+	}
+	certs, err := cert.ParseCertsPEM([]byte(configMap.Data["ca-bundle.crt"]))
+	if err != nil {
+		certificate.Error = fmt.Sprintf("failed to parse ca-bundle.crt: %v", err)
+		return []Certificate{certificate}
+	}
+	var certificates []Certificate
+	for _, c := range certs {
+		bundled := certificate
+		describe(&bundled, c)
+		certificates = append(certificates, bundled)
+	}
+	return certificates
+}
+
+// describe fills in the details of the x509 certificate.
+func describe(certificate *Certificate, c *x509.Certificate) {
+	certificate.Subject = c.Subject.String()
+	certificate.Issuer = c.Issuer.String()
+	certificate.SANs = append(certificate.SANs, c.DNSNames...)
+	for _, ip := range c.IPAddresses {
+		certificate.SANs = append(certificate.SANs, ip.String())
+	}
+	notBefore, notAfter := c.NotBefore.UTC(), c.NotAfter.UTC()
+	certificate.NotBefore, certificate.NotAfter = &notBefore, &notAfter
+}
+
+func printCertificates(out io.Writer, output string, certificates []Certificate) error {
+	switch output {
+	case "json":
+		if certificates == nil {
+			certificates = []Certificate{}
+		}
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(certificates)
+	case "csv":
+		w := csv.NewWriter(out)
+		if err := w.Write([]string{"namespace", "name", "kind", "type", "subject", "issuer", "sans", "notBefore", "notAfter", "refreshTime", "consumer", "error"}); err != nil {
+			return err
+		}
+		for _, c := range certificates {
+			if err := w.Write([]string{c.Namespace, c.Name, c.Kind, string(c.Type), c.Subject, c.Issuer, strings.Join(c.SANs, ","), formatTime(c.NotBefore), formatTime(c.NotAfter), formatTime(c.RefreshTime), c.Consumer, c.Error}); err != nil {
+				return err
+			}
+		}
+		w.Flush()
+		return w.Error()
+	}
+
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "NAMESPACE\tNAME\tKIND\tTYPE\tSUBJECT\tISSUER\tSANS\tNOT BEFORE\tNOT AFTER\tREFRESH\tCONSUMER")
+	for _, c := range certificates {
+		subject := c.Subject
+		if len(c.Error) > 0 {
+			subject = "<error: " + c.Error + ">"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", c.Namespace, c.Name, c.Kind, c.Type, subject, c.Issuer, strings.Join(c.SANs, ","), formatTime(c.NotBefore), formatTime(c.NotAfter), formatTime(c.RefreshTime), c.Consumer)
+	}
+	return w.Flush()
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
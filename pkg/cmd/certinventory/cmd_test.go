@@ -0,0 +1,148 @@
+package certinventory
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+
+	configversionedclient "github.com/openshift/client-go/config/clientset/versioned"
+	"github.com/openshift/library-go/pkg/crypto"
+	"github.com/openshift/library-go/pkg/operator/certrotation"
+
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/certrotationcontroller"
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/operatorclient"
+)
+
+func TestCollect(t *testing.T) {
+	caConfig, err := crypto.MakeSelfSignedCAConfig("test-signer", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca := &crypto.CA{Config: caConfig, SerialGenerator: &crypto.RandomSerialGenerator{}}
+	server, err := ca.MakeServerCert(sets.NewString("localhost", "127.0.0.1"), 30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caPEM, _, err := ca.Config.GetPEMBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	serverPEM, _, err := server.GetPEMBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := fake.NewSimpleClientset(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-kube-apiserver-operator", Name: "localhost-serving-signer"},
+			Data:       map[string][]byte{"tls.crt": caPEM},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-kube-apiserver-operator", Name: "localhost-serving-ca"},
+			Data:       map[string]string{"ca-bundle.crt": string(caPEM) + string(caPEM)},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-kube-apiserver", Name: "user-named-serving-certs"},
+			Data:       map[string][]byte{"foo.crt": serverPEM, "foo.key": []byte("key")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-kube-apiserver", Name: "user-serving-cert-000"},
+			Data:       map[string][]byte{"tls.crt": serverPEM},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-kube-apiserver", Name: "unrelated"},
+			Data:       map[string][]byte{"tls.crt": serverPEM},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-kube-apiserver", Name: "client-ca"},
+			Data:       map[string]string{"ca-bundle.crt": string(caPEM)},
+		},
+	)
+	inventory := []certrotationcontroller.ManagedCertificate{
+		{Namespace: "openshift-kube-apiserver-operator", Name: "localhost-serving-signer", Type: certrotation.CertificateTypeSigner, Refresh: 8 * 365 * 24 * time.Hour, Consumer: "kube-apiserver-operator"},
+		{Namespace: "openshift-kube-apiserver-operator", Name: "localhost-serving-ca", Type: certrotation.CertificateTypeCABundle, Consumer: "kube-apiserver clients"},
+		{Namespace: "openshift-kube-apiserver", Name: "localhost-serving-cert-certkey", Type: certrotation.CertificateTypeTarget, Refresh: 24 * time.Hour, Consumer: "kube-apiserver"},
+	}
+
+	certificates, err := collect(context.TODO(), client, inventory)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var actual []string
+	for _, c := range certificates {
+		actual = append(actual, c.Namespace+"/"+c.Name)
+	}
+	expected := []string{
+		"openshift-kube-apiserver-operator/localhost-serving-ca",
+		"openshift-kube-apiserver-operator/localhost-serving-ca",
+		"openshift-kube-apiserver-operator/localhost-serving-signer",
+		"openshift-kube-apiserver/client-ca",
+		"openshift-kube-apiserver/localhost-serving-cert-certkey",
+		"openshift-kube-apiserver/user-named-serving-certs/foo",
+	}
+	if !sets.NewString(actual...).Equal(sets.NewString(expected...)) || len(actual) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, actual)
+	}
+
+	byName := map[string]Certificate{}
+	for _, c := range certificates {
+		byName[c.Name] = c
+	}
+	if missing := byName["localhost-serving-cert-certkey"]; len(missing.Error) == 0 || missing.NotAfter != nil {
+		t.Errorf("expected an error for the missing secret, got %#v", missing)
+	}
+	signer := byName["localhost-serving-signer"]
+	if signer.Subject != "CN=test-signer" || signer.RefreshTime == nil || signer.Consumer != "kube-apiserver-operator" {
+		t.Errorf("unexpected signer %#v", signer)
+	}
+	// the signer is valid for 10 days, it is rotated after 80% of its validity
+	if expected := signer.NotAfter.Add(-signer.NotAfter.Sub(*signer.NotBefore) / 5); !signer.RefreshTime.Equal(expected) {
+		t.Errorf("expected the signer to be refreshed at %v, got %v", expected, signer.RefreshTime)
+	}
+	user := byName["user-named-serving-certs/foo"]
+	if user.Type != certificateTypeUser || user.Issuer != "CN=test-signer" || user.RefreshTime != nil || !sets.NewString(user.SANs...).Equal(sets.NewString("localhost", "127.0.0.1")) {
+		t.Errorf("unexpected user serving certificate %#v", user)
+	}
+	if byName["client-ca"].RefreshTime != nil {
+		t.Errorf("expected no refresh time for CA bundles, got %v", byName["client-ca"].RefreshTime)
+	}
+
+	out := &bytes.Buffer{}
+	if err := printCertificates(out, "csv", certificates); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(out).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != len(certificates)+1 || records[0][0] != "namespace" {
+		t.Errorf("expected a header and a record per certificate, got %v", records)
+	}
+}
+
+func TestManagedCertificatesInvalidRotationConfig(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: certrotationcontroller.RotationConfigConfigMapName},
+		Data:       map[string]string{"servingCertValidity": "a month"},
+	})
+	// the informers of these clients are never started
+	unreachable := &rest.Config{Host: "https://127.0.0.1:1"}
+
+	certificates, err := managedCertificates(context.TODO(), kubeClient, configversionedclient.NewForConfigOrDie(unreachable), dynamic.NewForConfigOrDie(unreachable))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(certificates) == 0 {
+		t.Errorf("expected the certificates managed with the default rotation periods")
+	}
+}
@@ -143,3 +143,22 @@ func (c *CABundleController) sync(ctx context.Context) error {
 
 	return nil
 }
+
+// RunOnce regenerates the client CA bundle a single time without waiting for changes.
+// It is used by offline certificate regeneration where no controller keeps running.
+func (c *CABundleController) RunOnce(ctx context.Context) error {
+	if !cache.WaitForNamedCacheSync("CABundleController", ctx.Done(), c.cachesToSync...) {
+		return fmt.Errorf("caches did not sync")
+	}
+
+	_, changed, err := targetconfigcontroller.ManageClientCABundle(ctx, c.configMapLister, c.configMapGetter, c.eventRecorder)
+	if err != nil {
+		return err
+	}
+
+	if changed {
+		klog.V(2).Info("Refreshed client CA bundle.")
+	}
+
+	return nil
+}
@@ -7,7 +7,10 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
 
 	operatorv1 "github.com/openshift/api/operator/v1"
 	configeversionedclient "github.com/openshift/client-go/config/clientset/versioned"
@@ -82,7 +85,13 @@ func (o *Options) Run(ctx context.Context) error {
 		return fmt.Errorf("failed to create config client: %w", err)
 	}
 
+	dynamicClient, err := dynamic.NewForConfig(o.controllerContext.KubeConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+
 	configInformers := configexternalinformers.NewSharedInformerFactory(configClient, 10*time.Minute)
+	dnsInformers := dynamicinformer.NewDynamicSharedInformerFactory(dynamicClient, 10*time.Minute)
 
 	kubeAPIServerInformersForNamespaces := v1helpers.NewKubeInformersForNamespaces(
 		kubeClient,
@@ -101,14 +110,22 @@ func (o *Options) Run(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	defaultCertRotationConfig := certrotationcontroller.DefaultRotationConfig(certRotationScale)
+	certRotationConfig, err := certrotationcontroller.GetRotationConfig(ctx, kubeClient, defaultCertRotationConfig)
+	if err != nil {
+		// regenerating expired certificates must not depend on a valid config
+		klog.Warningf("Using the default certificate rotation periods: %v", err)
+		certRotationConfig = defaultCertRotationConfig
+	}
 
 	kubeAPIServerCertRotationController, err := certrotationcontroller.NewCertRotationControllerOnlyWhenExpired(
 		kubeClient,
 		operatorClient,
 		configInformers,
+		dnsInformers.ForResource(certrotationcontroller.DNSResource),
 		kubeAPIServerInformersForNamespaces,
 		o.controllerContext.EventRecorder,
-		certRotationScale,
+		certRotationConfig,
 	)
 	if err != nil {
 		return err
@@ -125,6 +142,7 @@ func (o *Options) Run(ctx context.Context) error {
 
 	// We can't start informers until after the resources have been requested. Now is the time.
 	configInformers.Start(ctx.Done())
+	dnsInformers.Start(ctx.Done())
 	kubeAPIServerInformersForNamespaces.Start(ctx.Done())
 	dynamicInformers.Start(ctx.Done())
 
@@ -63,6 +63,7 @@ func NewCheckEndpointsCommand() *cobra.Command {
 			operatorcontrolplaneClient.ControlplaneV1alpha1(),
 			operatorcontrolplaneInformers.Controlplane().V1alpha1().PodNetworkConnectivityChecks(),
 			kubeInformers.Core().V1().Secrets(),
+			kubeClient.CoreV1(),
 			recorder,
 		)
 
@@ -105,5 +106,6 @@ func NewCheckEndpointsCommand() *cobra.Command {
 	cmd := config.NewCommandWithContext(context.Background())
 	cmd.Use = "check-endpoints"
 	cmd.Short = "Checks that a tcp connection can be opened to one or more endpoints."
+	cmd.AddCommand(NewReportCommand())
 	return cmd
 }
@@ -3,6 +3,7 @@ package controller
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"net"
 	"regexp"
@@ -31,13 +32,14 @@ type ConnectionChecker interface {
 type GetCheckFunc func() *operatorcontrolplanev1alpha1.PodNetworkConnectivityCheck
 
 // NewConnectionChecker returns a ConnectionChecker.
-func NewConnectionChecker(name, podName, podNamespace string, getCheck GetCheckFunc, client v1alpha1helpers.PodNetworkConnectivityCheckClient, clientCertGetter CertificatesGetter, recorder Recorder) ConnectionChecker {
+func NewConnectionChecker(name, podName, podNamespace string, getCheck GetCheckFunc, client v1alpha1helpers.PodNetworkConnectivityCheckClient, clientCertGetter CertificatesGetter, caBundleGetter CABundleGetter, recorder Recorder) ConnectionChecker {
 	return &connectionChecker{
 		name:             name,
 		podName:          podName,
 		getCheck:         getCheck,
 		client:           client,
 		clientCertGetter: clientCertGetter,
+		caBundleGetter:   caBundleGetter,
 		recorder:         recorder,
 		updates:          NewUpdatesManager(checkPeriod, checkTimeout, newUpdatesProcessor(client, name)),
 		stop:             make(chan interface{}),
@@ -61,6 +63,7 @@ type connectionChecker struct {
 
 	client           v1alpha1helpers.PodNetworkConnectivityCheckClient
 	clientCertGetter CertificatesGetter
+	caBundleGetter   CABundleGetter
 	recorder         Recorder
 	updates          UpdatesManager
 	stop             chan interface{}
@@ -127,7 +130,7 @@ func (c *connectionChecker) updateStatus(ctx context.Context, flush bool) {
 
 // checkEndpoint performs the check and manages the PodNetworkConnectivityCheck.Status changes that result.
 func (c *connectionChecker) checkEndpoint(ctx context.Context, check *operatorcontrolplanev1alpha1.PodNetworkConnectivityCheck) {
-	latencyInfo, err := c.getTCPConnectLatency(ctx, check.Spec.TargetEndpoint)
+	latencyInfo, err := c.probeEndpoint(ctx, check)
 	statusUpdates, timestamp := manageStatusLogs(check, err, latencyInfo)
 	if len(statusUpdates) > 0 {
 		statusUpdates = append(statusUpdates, manageStatusOutage(c.recorder))
@@ -138,43 +141,72 @@ func (c *connectionChecker) checkEndpoint(ctx context.Context, check *operatorco
 	c.updates.Add(timestamp, statusUpdates...)
 }
 
-// getTCPConnectLatency connects to a tcp endpoint and collects latency info
-func (c *connectionChecker) getTCPConnectLatency(ctx context.Context, address string) (*trace.LatencyInfo, error) {
+// probeEndpoint probes the target endpoint of the check as configured by its annotations and collects latency info
+func (c *connectionChecker) probeEndpoint(ctx context.Context, check *operatorcontrolplanev1alpha1.PodNetworkConnectivityCheck) (*trace.LatencyInfo, error) {
+	address := check.Spec.TargetEndpoint
 	klog.V(4).Infof("Check BEGIN: %v", address)
 	defer klog.V(4).Infof("Check END  : %v", address)
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
 	ctx, latencyInfo := trace.WithLatencyInfoCapture(ctx)
 
+	p, err := probeFor(check)
+	if err != nil {
+		err = &probeError{reason: LogEntryReasonProbeConfigError, start: time.Now(), err: err}
+		c.metrics.Update(address, latencyInfo, err)
+		return latencyInfo, err
+	}
+
+	switch p.probeType {
+	case ProbeTLS:
+		err = c.probeTLS(ctx, latencyInfo, address, p)
+	case ProbeHTTP, ProbeHTTPS:
+		err = c.probeHTTP(ctx, latencyInfo, address, p)
+	case ProbeGRPC, ProbeGRPCTLS:
+		err = c.probeGRPC(ctx, latencyInfo, address, p)
+	default:
+		err = c.getTCPConnectLatency(ctx, latencyInfo, address)
+	}
+
+	c.metrics.Update(address, latencyInfo, err)
+	return latencyInfo, err
+}
+
+// getTCPConnectLatency connects to a tcp endpoint and collects latency info
+func (c *connectionChecker) getTCPConnectLatency(ctx context.Context, latencyInfo *trace.LatencyInfo, address string) error {
 	// tcp connection
 	dialer := &net.Dialer{
 		Timeout: checkTimeout,
 	}
 	tcpConn, err := dialer.DialContext(ctx, "tcp", address)
 	if err != nil {
-		c.metrics.Update(address, latencyInfo, err)
-		return latencyInfo, err
+		return err
 	}
 
 	// perform tls handshake to avoid spamming the logs of tls endpoints
 	host, _, _ := net.SplitHostPort(address)
 	tlsConn := tls.Client(tcpConn, &tls.Config{Certificates: c.clientCertGetter(), ServerName: host, InsecureSkipVerify: true})
-	if err = tlsConn.Handshake(); err != nil {
+	latencyInfo.StartTLSHandshake()
+	err = tlsConn.HandshakeContext(ctx)
+	latencyInfo.DoneTLSHandshake(tlsConn.ConnectionState(), err)
+	if err != nil {
 		// ignore any error. most likely non-tls connection, plus we're not really testing tls
 		klog.V(4).Infof("%s: tls error ignored: %v", address, err)
+		latencyInfo.ResetTLSHandshake()
 		_ = tcpConn.Close()
-		c.metrics.Update(address, latencyInfo, nil)
-		return latencyInfo, nil
+		return nil
 	}
 
 	// gracefully close connection (ignore error)
 	_ = tlsConn.Close()
 
-	c.metrics.Update(address, latencyInfo, err)
-	return latencyInfo, err
+	return nil
 }
 
 // isDNSError returns true if the cause of the net operation error is a DNS error
 func isDNSError(err error) bool {
-	if opErr, ok := err.(*net.OpError); ok {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
 		if _, ok := opErr.Err.(*net.DNSError); ok {
 			return true
 		}
@@ -210,10 +242,16 @@ func manageStatusLogs(check *operatorcontrolplanev1alpha1.PodNetworkConnectivity
 		}))
 		overallStart = latency.DNSStart
 	}
+	var probeErr *probeError
+	if errors.As(checkErr, &probeErr) && latency.ConnectStart.IsZero() {
+		// the probe failed before connecting
+		klog.V(2).Infof("%7s | %-15s | %10s | Failed to probe %s: %v", "Failure", probeErr.reason, probeErr.latency, check.Spec.TargetEndpoint, checkErr)
+		return append(statusUpdates, probeFailureLogEntry(description, check.Spec.TargetEndpoint, probeErr)), probeErr.start
+	}
 	if overallStart.IsZero() {
 		overallStart = latency.ConnectStart
 	}
-	if checkErr != nil {
+	if checkErr != nil && probeErr == nil {
 		klog.V(2).Infof("%7s | %-15s | %10s | Failed to establish a TCP connection to %s: %v", "Failure", "TCPConnectError", latency.Connect, check.Spec.TargetEndpoint, checkErr)
 		return append(statusUpdates, v1alpha1helpers.AddFailureLogEntry(operatorcontrolplanev1alpha1.LogEntry{
 			Start:   metav1.NewTime(latency.ConnectStart),
@@ -224,13 +262,53 @@ func manageStatusLogs(check *operatorcontrolplanev1alpha1.PodNetworkConnectivity
 		})), overallStart
 	}
 	klog.V(2).Infof("%7s | %-15s | %10s | TCP connection to %v succeeded", "Success", "TCPConnect", latency.Connect, check.Spec.TargetEndpoint)
-	return append(statusUpdates, v1alpha1helpers.AddSuccessLogEntry(operatorcontrolplanev1alpha1.LogEntry{
+	statusUpdates = append(statusUpdates, v1alpha1helpers.AddSuccessLogEntry(operatorcontrolplanev1alpha1.LogEntry{
 		Start:   metav1.NewTime(latency.ConnectStart),
 		Success: true,
 		Reason:  operatorcontrolplanev1alpha1.LogEntryReasonTCPConnect,
 		Message: fmt.Sprintf("%s: tcp connection to %s succeeded", description, check.Spec.TargetEndpoint),
 		Latency: metav1.Duration{Duration: latency.Connect},
-	})), overallStart
+	}))
+	if len(latency.TLSVersion) > 0 {
+		peerCertificate := "no peer certificate"
+		if !latency.PeerCertificateNotAfter.IsZero() {
+			peerCertificate = fmt.Sprintf("peer certificate expires %s", latency.PeerCertificateNotAfter.UTC().Format(time.RFC3339))
+		}
+		klog.V(2).Infof("%7s | %-15s | %10s | TLS handshake with %v succeeded: %s, %s, %s", "Success", LogEntryReasonTLSHandshake, latency.TLSHandshake, check.Spec.TargetEndpoint, latency.TLSVersion, latency.TLSCipherSuite, peerCertificate)
+		statusUpdates = append(statusUpdates, v1alpha1helpers.AddSuccessLogEntry(operatorcontrolplanev1alpha1.LogEntry{
+			Start:   metav1.NewTime(latency.TLSHandshakeStart),
+			Success: true,
+			Reason:  LogEntryReasonTLSHandshake,
+			Message: fmt.Sprintf("%s: tls handshake with %s succeeded: %s, %s, %s", description, check.Spec.TargetEndpoint, latency.TLSVersion, latency.TLSCipherSuite, peerCertificate),
+			Latency: metav1.Duration{Duration: latency.TLSHandshake},
+		}))
+	}
+	if probeErr != nil {
+		klog.V(2).Infof("%7s | %-15s | %10s | Failed to probe %s: %v", "Failure", probeErr.reason, probeErr.latency, check.Spec.TargetEndpoint, checkErr)
+		return append(statusUpdates, probeFailureLogEntry(description, check.Spec.TargetEndpoint, probeErr)), overallStart
+	}
+	probeType := probeTypeFor(check)
+	if reason, start, duration, ok := probePhase(probeType, latency); ok {
+		klog.V(2).Infof("%7s | %-15s | %10s | %s probe of %v succeeded, time to first byte %s", "Success", reason, duration, probeType, check.Spec.TargetEndpoint, latency.TimeToFirstByte)
+		statusUpdates = append(statusUpdates, v1alpha1helpers.AddSuccessLogEntry(operatorcontrolplanev1alpha1.LogEntry{
+			Start:   metav1.NewTime(start),
+			Success: true,
+			Reason:  reason,
+			Message: fmt.Sprintf("%s: %s probe of %s succeeded, time to first byte %s", description, probeType, check.Spec.TargetEndpoint, latency.TimeToFirstByte),
+			Latency: metav1.Duration{Duration: duration},
+		}))
+	}
+	return statusUpdates, overallStart
+}
+
+func probeFailureLogEntry(description, targetEndpoint string, probeErr *probeError) v1alpha1helpers.UpdateStatusFunc {
+	return v1alpha1helpers.AddFailureLogEntry(operatorcontrolplanev1alpha1.LogEntry{
+		Start:   metav1.NewTime(probeErr.start),
+		Success: false,
+		Reason:  probeErr.reason,
+		Message: fmt.Sprintf("%s: failed to probe %s: %v", description, targetEndpoint, probeErr),
+		Latency: metav1.Duration{Duration: probeErr.latency},
+	})
 }
 
 // manageStatusOutage returns a status update function that manages the
@@ -309,7 +387,7 @@ func manageStatusConditions(status *operatorcontrolplanev1alpha1.PodNetworkConne
 			latestSuccessLogEntry = status.Successes[0]
 		}
 		reachableCondition.Status = metav1.ConditionTrue
-		reachableCondition.Reason = "TCPConnectSuccess"
+		reachableCondition.Reason = latestSuccessLogEntry.Reason
 		reachableCondition.Message = latestSuccessLogEntry.Message
 	} else {
 		var latestFailureLogEntry operatorcontrolplanev1alpha1.LogEntry
@@ -20,8 +20,12 @@ func TestManageStatusLogs(t *testing.T) {
 	testOpErr := &net.OpError{Op: "connect", Net: "tcp", Err: errors.New("test error")}
 	testDNSErr := &net.OpError{Op: "connect", Net: "tcp", Err: &net.DNSError{Err: "test error", Name: "host"}}
 
+	testProbeErr := &probeError{reason: LogEntryReasonTLSHandshakeError, start: testTime(1), latency: 1 * time.Millisecond, err: errors.New("test error")}
+	testProbeConfigErr := &probeError{reason: LogEntryReasonProbeConfigError, start: testTime(0), err: errors.New("test error")}
+
 	testCases := []struct {
 		name              string
+		probe             ProbeType
 		err               error
 		trace             *trace.LatencyInfo
 		initial           *v1alpha1.PodNetworkConnectivityCheckStatus
@@ -119,13 +123,106 @@ func TestManageStatusLogs(t *testing.T) {
 			),
 			expectedTimestamp: testTime(3),
 		},
+		{
+			name:  "TLSHandshake",
+			probe: ProbeTLS,
+			trace: &trace.LatencyInfo{
+				ConnectStart:            testTime(0),
+				Connect:                 1 * time.Millisecond,
+				TLSHandshakeStart:       testTime(1),
+				TLSHandshake:            1 * time.Millisecond,
+				TLSVersion:              "TLS 1.3",
+				TLSCipherSuite:          "TLS_AES_128_GCM_SHA256",
+				PeerCertificateNotAfter: testTime(60),
+			},
+			initial: podNetworkConnectivityCheckStatus(),
+			expected: podNetworkConnectivityCheckStatus(
+				withSuccessEntry(tlsHandshakeEntry(1)),
+				withSuccessEntry(tcpConnectEntry(0)),
+			),
+			expectedTimestamp: testTime(0),
+		},
+		{
+			name: "TCPConnectWithTLSHandshake",
+			trace: &trace.LatencyInfo{
+				ConnectStart:            testTime(0),
+				Connect:                 1 * time.Millisecond,
+				TLSHandshakeStart:       testTime(1),
+				TLSHandshake:            1 * time.Millisecond,
+				TLSVersion:              "TLS 1.3",
+				TLSCipherSuite:          "TLS_AES_128_GCM_SHA256",
+				PeerCertificateNotAfter: testTime(60),
+			},
+			initial: podNetworkConnectivityCheckStatus(),
+			expected: podNetworkConnectivityCheckStatus(
+				withSuccessEntry(tlsHandshakeEntry(1)),
+				withSuccessEntry(tcpConnectEntry(0)),
+			),
+			expectedTimestamp: testTime(0),
+		},
+		{
+			name:  "HTTPSRequest",
+			probe: ProbeHTTPS,
+			trace: &trace.LatencyInfo{
+				ConnectStart:            testTime(0),
+				Connect:                 1 * time.Millisecond,
+				TLSHandshakeStart:       testTime(1),
+				TLSHandshake:            1 * time.Millisecond,
+				TLSVersion:              "TLS 1.3",
+				TLSCipherSuite:          "TLS_AES_128_GCM_SHA256",
+				PeerCertificateNotAfter: testTime(60),
+				RequestStart:            testTime(2),
+				Request:                 1 * time.Millisecond,
+				TimeToFirstByte:         3 * time.Millisecond,
+			},
+			initial: podNetworkConnectivityCheckStatus(),
+			expected: podNetworkConnectivityCheckStatus(
+				withSuccessEntry(logEntry(true, 2, LogEntryReasonHTTPRequest, "target-endpoint: https probe of host:port succeeded, time to first byte 3ms")),
+				withSuccessEntry(tlsHandshakeEntry(1)),
+				withSuccessEntry(tcpConnectEntry(0)),
+			),
+			expectedTimestamp: testTime(0),
+		},
+		{
+			name:  "TLSHandshakeError",
+			probe: ProbeTLS,
+			err:   testProbeErr,
+			trace: &trace.LatencyInfo{
+				ConnectStart:      testTime(0),
+				Connect:           1 * time.Millisecond,
+				TLSHandshakeStart: testTime(1),
+				TLSHandshake:      1 * time.Millisecond,
+			},
+			initial: podNetworkConnectivityCheckStatus(),
+			expected: podNetworkConnectivityCheckStatus(
+				withSuccessEntry(tcpConnectEntry(0)),
+				withFailureEntry(logEntry(false, 1, LogEntryReasonTLSHandshakeError, "target-endpoint: failed to probe host:port: test error")),
+			),
+			expectedTimestamp: testTime(0),
+		},
+		{
+			name:    "ProbeConfigError",
+			probe:   "unknown",
+			err:     testProbeConfigErr,
+			trace:   &trace.LatencyInfo{},
+			initial: podNetworkConnectivityCheckStatus(),
+			expected: podNetworkConnectivityCheckStatus(
+				withFailureEntry(logEntry(false, 0, LogEntryReasonProbeConfigError, "target-endpoint: failed to probe host:port: test error", withLatency(0))),
+			),
+			expectedTimestamp: testTime(0),
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			status := tc.initial
+			var annotations map[string]string
+			if len(tc.probe) > 0 {
+				annotations = map[string]string{ProbeAnnotation: string(tc.probe)}
+			}
 			updateStatusFuncs, timestamp := manageStatusLogs(&v1alpha1.PodNetworkConnectivityCheck{
 				ObjectMeta: metav1.ObjectMeta{
-					Name: "test-to-target-endpoint",
+					Name:        "test-to-target-endpoint",
+					Annotations: annotations,
 				},
 				Spec: v1alpha1.PodNetworkConnectivityCheckSpec{
 					TargetEndpoint: "host:port",
@@ -433,6 +530,65 @@ func testTime(sec int) time.Time {
 	return time.Date(2000, 1, 1, 0, 0, sec, 0, time.UTC)
 }
 
+func TestManageStatusConditions(t *testing.T) {
+	testCases := []struct {
+		name           string
+		initial        *v1alpha1.PodNetworkConnectivityCheckStatus
+		expectedStatus metav1.ConditionStatus
+		expectedReason string
+	}{
+		{
+			name: "TCPConnectSuccess",
+			initial: podNetworkConnectivityCheckStatus(
+				withSuccessEntry(tcpConnectEntry(1)),
+			),
+			expectedStatus: metav1.ConditionTrue,
+			expectedReason: v1alpha1.LogEntryReasonTCPConnect,
+		},
+		{
+			name: "TLSHandshakeSuccess",
+			initial: podNetworkConnectivityCheckStatus(
+				withSuccessEntry(tlsHandshakeEntry(2)),
+				withSuccessEntry(tcpConnectEntry(1)),
+			),
+			expectedStatus: metav1.ConditionTrue,
+			expectedReason: LogEntryReasonTLSHandshake,
+		},
+		{
+			name: "OutageEnded",
+			initial: podNetworkConnectivityCheckStatus(
+				withSuccessEntry(tcpConnectEntry(2)),
+				withFailureEntry(tcpConnectErrorEntry(1)),
+				withOutageEntry(1, withEnd(2)),
+			),
+			expectedStatus: metav1.ConditionTrue,
+			expectedReason: v1alpha1.LogEntryReasonTCPConnect,
+		},
+		{
+			name: "OutageOngoing",
+			initial: podNetworkConnectivityCheckStatus(
+				withFailureEntry(tcpConnectErrorEntry(1)),
+				withOutageEntry(1),
+			),
+			expectedStatus: metav1.ConditionFalse,
+			expectedReason: v1alpha1.LogEntryReasonTCPConnectError,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			status := tc.initial.DeepCopy()
+			manageStatusConditions(status)
+			if len(status.Conditions) != 1 {
+				t.Fatalf("expected one condition, got %v", status.Conditions)
+			}
+			condition := status.Conditions[0]
+			if condition.Type != v1alpha1.Reachable || condition.Status != tc.expectedStatus || condition.Reason != tc.expectedReason {
+				t.Errorf("expected %s=%s with reason %s, got %s=%s with reason %s", v1alpha1.Reachable, tc.expectedStatus, tc.expectedReason, condition.Type, condition.Status, condition.Reason)
+			}
+		})
+	}
+}
+
 func podNetworkConnectivityCheckStatus(options ...func(status *v1alpha1.PodNetworkConnectivityCheckStatus)) *v1alpha1.PodNetworkConnectivityCheckStatus {
 	result := &v1alpha1.PodNetworkConnectivityCheckStatus{}
 	for _, f := range options {
@@ -469,6 +625,11 @@ func tcpConnectEntry(start int, options ...func(entry *v1alpha1.LogEntry)) v1alp
 	return logEntry(true, start, v1alpha1.LogEntryReasonTCPConnect, "target-endpoint: tcp connection to host:port succeeded", options...)
 }
 
+func tlsHandshakeEntry(start int) v1alpha1.LogEntry {
+	message := fmt.Sprintf("target-endpoint: tls handshake with host:port succeeded: TLS 1.3, TLS_AES_128_GCM_SHA256, peer certificate expires %s", testTime(60).UTC().Format(time.RFC3339))
+	return logEntry(true, start, LogEntryReasonTLSHandshake, message)
+}
+
 func withLatency(latency time.Duration) func(*v1alpha1.LogEntry) {
 	return func(entry *v1alpha1.LogEntry) {
 		entry.Latency = metav1.Duration{Duration: latency}
@@ -1,8 +1,11 @@
 package controller
 
 import (
+	"errors"
+	"reflect"
 	"sync"
 
+	operatorcontrolplanev1alpha1 "github.com/openshift/api/operatorcontrolplane/v1alpha1"
 	"github.com/openshift/cluster-kube-apiserver-operator/pkg/cmd/checkendpoints/trace"
 	"k8s.io/component-base/metrics"
 	"k8s.io/component-base/metrics/legacyregistry"
@@ -11,11 +14,19 @@ import (
 var (
 	registerMetrics sync.Once
 
-	endpointCheckCounter   *metrics.CounterVec
-	tcpConnectLatencyGauge *metrics.GaugeVec
-	dnsResolveLatencyGauge *metrics.GaugeVec
+	endpointCheckCounter           *metrics.CounterVec
+	endpointCheckFailureCounter    *metrics.CounterVec
+	tcpConnectLatencyHistogram     *metrics.HistogramVec
+	dnsResolveLatencyHistogram     *metrics.HistogramVec
+	tlsHandshakeLatencyHistogram   *metrics.HistogramVec
+	timeToFirstByteHistogram       *metrics.HistogramVec
+	tlsInfoGauge                   *metrics.GaugeVec
+	peerCertificateExpirationGauge *metrics.GaugeVec
 )
 
+// latencyBuckets range from 1ms to ~8s.
+var latencyBuckets = metrics.ExponentialBuckets(0.001, 2, 14)
+
 // RegisterMetrics in the global registry
 func RegisterMetrics() {
 	registerMetrics.Do(func() {
@@ -24,18 +35,52 @@ func RegisterMetrics() {
 			Help: "Report status of pod network connectivity checks over time.",
 		}, []string{"component", "checkName", "targetEndpoint", "tcpConnect", "dnsResolve"})
 
-		tcpConnectLatencyGauge = metrics.NewGaugeVec(&metrics.GaugeOpts{
-			Name: "pod_network_connectivity_check_tcp_connect_latency_gauge",
-			Help: "Report latency of TCP connect to target endpoint over time.",
+		endpointCheckFailureCounter = metrics.NewCounterVec(&metrics.CounterOpts{
+			Name: "pod_network_connectivity_check_failures_total",
+			Help: "Report failed pod network connectivity checks by the reason of the failure log entry, e.g. DNSError, TCPConnectError or TLSHandshakeError.",
+		}, []string{"component", "checkName", "targetEndpoint", "reason"})
+
+		tcpConnectLatencyHistogram = metrics.NewHistogramVec(&metrics.HistogramOpts{
+			Name:    "pod_network_connectivity_check_tcp_connect_latency_seconds",
+			Help:    "Report latency of TCP connect to target endpoint.",
+			Buckets: latencyBuckets,
+		}, []string{"component", "checkName", "targetEndpoint"})
+
+		dnsResolveLatencyHistogram = metrics.NewHistogramVec(&metrics.HistogramOpts{
+			Name:    "pod_network_connectivity_check_dns_resolve_latency_seconds",
+			Help:    "Report latency of DNS resolve of target endpoint.",
+			Buckets: latencyBuckets,
+		}, []string{"component", "checkName", "targetEndpoint"})
+
+		tlsHandshakeLatencyHistogram = metrics.NewHistogramVec(&metrics.HistogramOpts{
+			Name:    "pod_network_connectivity_check_tls_handshake_latency_seconds",
+			Help:    "Report latency of TLS handshakes with target endpoint.",
+			Buckets: latencyBuckets,
 		}, []string{"component", "checkName", "targetEndpoint"})
 
-		dnsResolveLatencyGauge = metrics.NewGaugeVec(&metrics.GaugeOpts{
-			Name: "pod_network_connectivity_check_dns_resolve_latency_gauge",
-			Help: "Report latency of DNS resolve of target endpoint over time.",
+		timeToFirstByteHistogram = metrics.NewHistogramVec(&metrics.HistogramOpts{
+			Name:    "pod_network_connectivity_check_time_to_first_byte_seconds",
+			Help:    "Report time from the start of a check until the first response byte from target endpoint was received.",
+			Buckets: latencyBuckets,
+		}, []string{"component", "checkName", "targetEndpoint"})
+
+		tlsInfoGauge = metrics.NewGaugeVec(&metrics.GaugeOpts{
+			Name: "pod_network_connectivity_check_tls_info",
+			Help: "Report TLS version and cipher suite last negotiated with target endpoint. The value is always 1.",
+		}, []string{"component", "checkName", "targetEndpoint", "version", "cipherSuite"})
+
+		peerCertificateExpirationGauge = metrics.NewGaugeVec(&metrics.GaugeOpts{
+			Name: "pod_network_connectivity_check_peer_certificate_expiration_timestamp_seconds",
+			Help: "Report expiration time of the serving certificate last presented by target endpoint.",
 		}, []string{"component", "checkName", "targetEndpoint"})
 		legacyregistry.MustRegister(endpointCheckCounter)
-		legacyregistry.MustRegister(tcpConnectLatencyGauge)
-		legacyregistry.MustRegister(dnsResolveLatencyGauge)
+		legacyregistry.MustRegister(endpointCheckFailureCounter)
+		legacyregistry.MustRegister(tcpConnectLatencyHistogram)
+		legacyregistry.MustRegister(dnsResolveLatencyHistogram)
+		legacyregistry.MustRegister(tlsHandshakeLatencyHistogram)
+		legacyregistry.MustRegister(timeToFirstByteHistogram)
+		legacyregistry.MustRegister(tlsInfoGauge)
+		legacyregistry.MustRegister(peerCertificateExpirationGauge)
 	})
 }
 
@@ -47,6 +92,8 @@ type MetricsContext interface {
 type metricsContext struct {
 	componentName string
 	checkName     string
+	// tlsInfoLabels are the labels of the last reported tls info, removed when the negotiated parameters change
+	tlsInfoLabels map[string]string
 }
 
 func NewMetricsContext(componentName, checkName string) *metricsContext {
@@ -61,12 +108,38 @@ func NewMetricsContext(componentName, checkName string) *metricsContext {
 // Update the pod network connectivity check metrics for the given check results.
 func (m *metricsContext) Update(targetEndpoint string, latency *trace.LatencyInfo, checkErr error) {
 	endpointCheckCounter.With(m.getCounterMetricLabels(targetEndpoint, latency, checkErr)).Inc()
+	if checkErr != nil {
+		labels := m.getMetricLabels(targetEndpoint)
+		labels["reason"] = failureReason(checkErr)
+		endpointCheckFailureCounter.With(labels).Inc()
+	}
 	if latency.Connect > 0 {
-		tcpConnectLatencyGauge.With(m.getMetricLabels(targetEndpoint)).Set(float64(latency.Connect.Nanoseconds()))
+		tcpConnectLatencyHistogram.With(m.getMetricLabels(targetEndpoint)).Observe(latency.Connect.Seconds())
 	}
 	if latency.DNS > 0 {
-		dnsResolveLatencyGauge.With(m.getMetricLabels(targetEndpoint)).Set(float64(latency.DNS.Nanoseconds()))
+		dnsResolveLatencyHistogram.With(m.getMetricLabels(targetEndpoint)).Observe(latency.DNS.Seconds())
+	}
+	if len(latency.TLSVersion) > 0 {
+		tlsHandshakeLatencyHistogram.With(m.getMetricLabels(targetEndpoint)).Observe(latency.TLSHandshake.Seconds())
+		m.updateTLSInfo(targetEndpoint, latency)
+		if !latency.PeerCertificateNotAfter.IsZero() {
+			peerCertificateExpirationGauge.With(m.getMetricLabels(targetEndpoint)).Set(float64(latency.PeerCertificateNotAfter.Unix()))
+		}
 	}
+	if latency.TimeToFirstByte > 0 {
+		timeToFirstByteHistogram.With(m.getMetricLabels(targetEndpoint)).Observe(latency.TimeToFirstByte.Seconds())
+	}
+}
+
+func (m *metricsContext) updateTLSInfo(targetEndpoint string, latency *trace.LatencyInfo) {
+	labels := m.getMetricLabels(targetEndpoint)
+	labels["version"] = latency.TLSVersion
+	labels["cipherSuite"] = latency.TLSCipherSuite
+	if m.tlsInfoLabels != nil && !reflect.DeepEqual(m.tlsInfoLabels, labels) {
+		tlsInfoGauge.Delete(m.tlsInfoLabels)
+	}
+	m.tlsInfoLabels = labels
+	tlsInfoGauge.With(labels).Set(1)
 }
 
 func (m *metricsContext) getCounterMetricLabels(targetEndpoint string, latency *trace.LatencyInfo, checkErr error) map[string]string {
@@ -80,6 +153,14 @@ func (m *metricsContext) getCounterMetricLabels(targetEndpoint string, latency *
 	if latency.DNS != 0 {
 		labels["dnsResolve"] = "success"
 	}
+	var probeErr *probeError
+	if errors.As(checkErr, &probeErr) {
+		// the probe failed after the tcp connection was established, if any
+		if !latency.ConnectStart.IsZero() {
+			labels["tcpConnect"] = "success"
+		}
+		return labels
+	}
 	if checkErr != nil {
 		labels["tcpConnect"] = "failure"
 		return labels
@@ -88,6 +169,18 @@ func (m *metricsContext) getCounterMetricLabels(targetEndpoint string, latency *
 	return labels
 }
 
+// failureReason returns the reason of the failure log entry recorded for the check error.
+func failureReason(checkErr error) string {
+	if isDNSError(checkErr) {
+		return operatorcontrolplanev1alpha1.LogEntryReasonDNSError
+	}
+	var probeErr *probeError
+	if errors.As(checkErr, &probeErr) {
+		return probeErr.reason
+	}
+	return operatorcontrolplanev1alpha1.LogEntryReasonTCPConnectError
+}
+
 func (m *metricsContext) getMetricLabels(targetEndpoint string) map[string]string {
 	return map[string]string{
 		"component":      m.componentName,
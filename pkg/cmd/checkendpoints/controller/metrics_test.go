@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"k8s.io/component-base/metrics/testutil"
+
+	"github.com/openshift/api/operatorcontrolplane/v1alpha1"
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/cmd/checkendpoints/trace"
+)
+
+func TestMetricsContextUpdate(t *testing.T) {
+	testCases := []struct {
+		name            string
+		err             error
+		trace           *trace.LatencyInfo
+		expectedReason  string
+		expectedDNS     uint64
+		expectedConnect uint64
+	}{
+		{
+			name:            "Success",
+			trace:           &trace.LatencyInfo{DNS: time.Millisecond, Connect: time.Millisecond},
+			expectedDNS:     1,
+			expectedConnect: 1,
+		},
+		{
+			name:           "DNSError",
+			err:            &net.OpError{Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "no such host", Name: "host"}},
+			trace:          &trace.LatencyInfo{DNS: time.Millisecond},
+			expectedReason: v1alpha1.LogEntryReasonDNSError,
+			expectedDNS:    1,
+		},
+		{
+			name:            "TCPConnectError",
+			err:             errors.New("connection refused"),
+			trace:           &trace.LatencyInfo{Connect: time.Millisecond},
+			expectedReason:  v1alpha1.LogEntryReasonTCPConnectError,
+			expectedConnect: 1,
+		},
+		{
+			name:            "TLSHandshakeError",
+			err:             &probeError{reason: LogEntryReasonTLSHandshakeError, err: errors.New("bad certificate")},
+			trace:           &trace.LatencyInfo{Connect: time.Millisecond},
+			expectedReason:  LogEntryReasonTLSHandshakeError,
+			expectedConnect: 1,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := NewMetricsContext("test-metrics", tc.name)
+			m.Update("host:port", tc.trace, tc.err)
+
+			labels := m.getMetricLabels("host:port")
+			if count, err := testutil.GetHistogramMetricCount(dnsResolveLatencyHistogram.With(labels)); err != nil || count != tc.expectedDNS {
+				t.Errorf("expected %d dns resolve latency samples, got %d: %v", tc.expectedDNS, count, err)
+			}
+			if count, err := testutil.GetHistogramMetricCount(tcpConnectLatencyHistogram.With(labels)); err != nil || count != tc.expectedConnect {
+				t.Errorf("expected %d tcp connect latency samples, got %d: %v", tc.expectedConnect, count, err)
+			}
+			for _, reason := range []string{v1alpha1.LogEntryReasonDNSError, v1alpha1.LogEntryReasonTCPConnectError, LogEntryReasonTLSHandshakeError} {
+				labels := m.getMetricLabels("host:port")
+				labels["reason"] = reason
+				expected := 0.0
+				if reason == tc.expectedReason {
+					expected = 1
+				}
+				if value, err := testutil.GetCounterMetricValue(endpointCheckFailureCounter.With(labels)); err != nil || value != expected {
+					t.Errorf("expected %v %s failures, got %v: %v", expected, reason, value, err)
+				}
+			}
+		})
+	}
+}
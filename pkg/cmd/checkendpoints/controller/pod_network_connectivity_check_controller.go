@@ -3,6 +3,9 @@ package controller
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"sync"
 	"time"
 
 	operatorcontrolplanev1alpha1 "github.com/openshift/api/operatorcontrolplane/v1alpha1"
@@ -11,9 +14,11 @@ import (
 	"github.com/openshift/client-go/operatorcontrolplane/listers/operatorcontrolplane/v1alpha1"
 	"github.com/openshift/library-go/pkg/controller/factory"
 	"github.com/openshift/library-go/pkg/operator/events"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	coreinformersv1 "k8s.io/client-go/informers/core/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 	corelistersv1 "k8s.io/client-go/listers/core/v1"
 	"k8s.io/klog/v2"
 )
@@ -36,7 +41,11 @@ type controller struct {
 	checksGetter operatorcontrolplaneclientv1alpha1.PodNetworkConnectivityCheckInterface
 	checkLister  v1alpha1.PodNetworkConnectivityCheckNamespaceLister
 	secretLister corelistersv1.SecretLister
+	configMaps   corev1client.ConfigMapInterface
 	recorder     Recorder
+	// CA bundles are only read when a probe references one, not every sidecar may list and watch configmaps
+	caBundlesLock sync.Mutex
+	caBundles     map[string]cachedCABundle
 	// each PodNetworkConnectivityCheck gets its own ConnectionChecker
 	updaters map[string]ConnectionChecker
 }
@@ -46,15 +55,18 @@ type controller struct {
 func NewPodNetworkConnectivityCheckController(podName, podNamespace string,
 	checksGetter operatorcontrolplaneclientv1alpha1.PodNetworkConnectivityChecksGetter,
 	checkInformer alpha1.PodNetworkConnectivityCheckInformer,
-	secretInformer coreinformersv1.SecretInformer, recorder events.Recorder) PodNetworkConnectivityCheckController {
+	secretInformer coreinformersv1.SecretInformer,
+	configMapsGetter corev1client.ConfigMapsGetter, recorder events.Recorder) PodNetworkConnectivityCheckController {
 	c := &controller{
 		podName:      podName,
 		podNamespace: podNamespace,
 		checksGetter: checksGetter.PodNetworkConnectivityChecks(podNamespace),
 		checkLister:  checkInformer.Lister().PodNetworkConnectivityChecks(podNamespace),
 		secretLister: secretInformer.Lister(),
+		configMaps:   configMapsGetter.ConfigMaps(podNamespace),
 		recorder:     NewBackoffEventRecorder(recorder),
 		updaters:     map[string]ConnectionChecker{},
+		caBundles:    map[string]cachedCABundle{},
 	}
 	c.Controller = factory.New().
 		WithSync(c.Sync).
@@ -83,7 +95,7 @@ func (c *controller) Sync(ctx context.Context, syncContext factory.SyncContext)
 	// create & start status updaters if needed
 	for _, check := range checks {
 		if updater := c.updaters[check.Name]; updater == nil {
-			c.updaters[check.Name] = NewConnectionChecker(check.Name, c.podName, c.podNamespace, c.newCheckFunc(check.Name), c, c.getClientCerts(check), c.recorder)
+			c.updaters[check.Name] = NewConnectionChecker(check.Name, c.podName, c.podNamespace, c.newCheckFunc(check.Name), c, c.getClientCerts(check), c.getCABundle, c.recorder)
 			go c.updaters[check.Name].Run(ctx)
 		}
 	}
@@ -134,6 +146,47 @@ func (c *controller) getClientCerts(check *operatorcontrolplanev1alpha1.PodNetwo
 	}
 }
 
+// caBundleTTL is how long a CA bundle read for a probe is reused before it is read again.
+const caBundleTTL = 1 * time.Minute
+
+type cachedCABundle struct {
+	pool    *x509.CertPool
+	err     error
+	expires time.Time
+}
+
+// getCABundle implements CABundleGetter for configmaps in the namespace of the checks. The configmap
+// is read on demand and cached for caBundleTTL, errors included, so probes do not hit the apiserver
+// every checkPeriod.
+func (c *controller) getCABundle(name string) (*x509.CertPool, error) {
+	c.caBundlesLock.Lock()
+	defer c.caBundlesLock.Unlock()
+	if cached, ok := c.caBundles[name]; ok && time.Now().Before(cached.expires) {
+		return cached.pool, cached.err
+	}
+	pool, err := c.readCABundle(name)
+	c.caBundles[name] = cachedCABundle{pool: pool, err: err, expires: time.Now().Add(caBundleTTL)}
+	return pool, err
+}
+
+func (c *controller) readCABundle(name string) (*x509.CertPool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), checkTimeout)
+	defer cancel()
+	configMap, err := c.configMaps.Get(ctx, name, metav1.GetOptions{})
+	if errors.IsForbidden(err) {
+		klog.V(2).Infof("configmap/%s: %v", name, err)
+		return nil, fmt.Errorf("configmap/%s: not allowed to read the CA bundle, grant get on configmaps in %s", name, c.podNamespace)
+	}
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM([]byte(configMap.Data["ca-bundle.crt"])) {
+		return nil, fmt.Errorf("configmap/%s: no certificates found in ca-bundle.crt", name)
+	}
+	return pool, nil
+}
+
 // Get implements PodNetworkConnectivityCheckClient
 func (c *controller) Get(name string) (*operatorcontrolplanev1alpha1.PodNetworkConnectivityCheck, error) {
 	return c.checkLister.Get(name)
@@ -0,0 +1,60 @@
+package controller
+
+import (
+	"encoding/pem"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestGetCABundle(t *testing.T) {
+	server := httptest.NewTLSServer(nil)
+	server.Close()
+	caBundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	client := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "service-ca"},
+		Data:       map[string]string{"ca-bundle.crt": string(caBundle)},
+	})
+	client.PrependReactor("get", "configmaps", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		if action.(clienttesting.GetAction).GetName() == "forbidden" {
+			return true, nil, errors.NewForbidden(schema.GroupResource{Resource: "configmaps"}, "forbidden", nil)
+		}
+		return false, nil, nil
+	})
+	c := &controller{podNamespace: "test", configMaps: client.CoreV1().ConfigMaps("test"), caBundles: map[string]cachedCABundle{}}
+
+	for i := 0; i < 2; i++ {
+		pool, err := c.getCABundle("service-ca")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pool == nil {
+			t.Fatal("expected a CA bundle")
+		}
+	}
+	if len(client.Actions()) != 1 {
+		t.Errorf("expected the CA bundle to be read once, got %d reads", len(client.Actions()))
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.getCABundle("forbidden"); err == nil || !strings.Contains(err.Error(), "not allowed to read the CA bundle") {
+			t.Errorf("expected a forbidden error, got %v", err)
+		}
+	}
+	if len(client.Actions()) != 2 {
+		t.Errorf("expected the forbidden error to be cached, got %d reads", len(client.Actions()))
+	}
+
+	if _, err := c.getCABundle("missing"); !errors.IsNotFound(err) {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+}
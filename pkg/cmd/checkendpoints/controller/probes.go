@@ -0,0 +1,338 @@
+package controller
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	operatorcontrolplanev1alpha1 "github.com/openshift/api/operatorcontrolplane/v1alpha1"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/cmd/checkendpoints/trace"
+)
+
+const (
+	// ProbeAnnotation selects how the target endpoint of a PodNetworkConnectivityCheck is probed,
+	// one of the ProbeType values. It defaults to ProbeTCP.
+	ProbeAnnotation = "controlplane.operator.openshift.io/probe"
+	// ProbeCABundleAnnotation names a configmap in the namespace of the check whose ca-bundle.crt key
+	// is used to verify the serving certificate of the target. The system trust store is used if unset.
+	ProbeCABundleAnnotation = "controlplane.operator.openshift.io/probe-ca-bundle"
+	// ProbeServerNameAnnotation overrides the server name used for SNI and certificate verification.
+	// It defaults to the host of the target endpoint.
+	ProbeServerNameAnnotation = "controlplane.operator.openshift.io/probe-server-name"
+	// ProbeHTTPPathAnnotation is the path requested by HTTP probes. It defaults to "/".
+	ProbeHTTPPathAnnotation = "controlplane.operator.openshift.io/probe-http-path"
+	// ProbeHTTPExpectedStatusAnnotation is the status code HTTP probes expect. It defaults to 200.
+	ProbeHTTPExpectedStatusAnnotation = "controlplane.operator.openshift.io/probe-http-expected-status"
+	// ProbeGRPCServiceAnnotation is the service name sent in gRPC health checks. It defaults to the
+	// empty string, which reports the overall health of the server.
+	ProbeGRPCServiceAnnotation = "controlplane.operator.openshift.io/probe-grpc-service"
+)
+
+// ProbeType is the kind of probe performed against the target endpoint of a check.
+type ProbeType string
+
+const (
+	// ProbeTCP opens a TCP connection. A TLS handshake is attempted to avoid spamming the logs of TLS
+	// endpoints, but its result is ignored.
+	ProbeTCP ProbeType = "tcp"
+	// ProbeTLS opens a TCP connection and performs a TLS handshake verifying the serving certificate.
+	ProbeTLS ProbeType = "tls"
+	// ProbeHTTP performs an HTTP GET and checks the status code.
+	ProbeHTTP ProbeType = "http"
+	// ProbeHTTPS performs an HTTP GET over a verified TLS connection and checks the status code.
+	ProbeHTTPS ProbeType = "https"
+	// ProbeGRPC performs a gRPC health check over a plaintext connection.
+	ProbeGRPC ProbeType = "grpc"
+	// ProbeGRPCTLS performs a gRPC health check over a verified TLS connection.
+	ProbeGRPCTLS ProbeType = "grpc-tls"
+)
+
+// Reasons of the log entries of the probe phases following the TCP connect.
+const (
+	LogEntryReasonProbeConfigError     = "ProbeConfigError"
+	LogEntryReasonTLSHandshake         = "TLSHandshake"
+	LogEntryReasonTLSHandshakeError    = "TLSHandshakeError"
+	LogEntryReasonHTTPRequest          = "HTTPRequest"
+	LogEntryReasonHTTPRequestError     = "HTTPRequestError"
+	LogEntryReasonGRPCHealthCheck      = "GRPCHealthCheck"
+	LogEntryReasonGRPCHealthCheckError = "GRPCHealthCheckError"
+)
+
+// CABundleGetter returns the certificates in the ca-bundle.crt key of the named configmap.
+type CABundleGetter func(name string) (*x509.CertPool, error)
+
+// probe describes how to probe the target endpoint of a check.
+type probe struct {
+	probeType      ProbeType
+	caBundle       string
+	serverName     string
+	httpPath       string
+	expectedStatus int
+	grpcService    string
+}
+
+// probeTypeFor returns the probe type configured for the check without validating it.
+func probeTypeFor(check *operatorcontrolplanev1alpha1.PodNetworkConnectivityCheck) ProbeType {
+	if probeType := check.Annotations[ProbeAnnotation]; len(probeType) > 0 {
+		return ProbeType(probeType)
+	}
+	return ProbeTCP
+}
+
+// probeFor returns the probe configured by the annotations of the check.
+func probeFor(check *operatorcontrolplanev1alpha1.PodNetworkConnectivityCheck) (*probe, error) {
+	p := &probe{
+		probeType:      probeTypeFor(check),
+		caBundle:       check.Annotations[ProbeCABundleAnnotation],
+		serverName:     check.Annotations[ProbeServerNameAnnotation],
+		httpPath:       "/",
+		expectedStatus: http.StatusOK,
+		grpcService:    check.Annotations[ProbeGRPCServiceAnnotation],
+	}
+	switch p.probeType {
+	case ProbeTCP, ProbeTLS, ProbeHTTP, ProbeHTTPS, ProbeGRPC, ProbeGRPCTLS:
+	default:
+		return nil, fmt.Errorf("unknown %s %q", ProbeAnnotation, p.probeType)
+	}
+	if path, ok := check.Annotations[ProbeHTTPPathAnnotation]; ok {
+		if !strings.HasPrefix(path, "/") {
+			return nil, fmt.Errorf("%s %q must start with /", ProbeHTTPPathAnnotation, path)
+		}
+		p.httpPath = path
+	}
+	if status, ok := check.Annotations[ProbeHTTPExpectedStatusAnnotation]; ok {
+		code, err := strconv.Atoi(status)
+		if err != nil || code < 100 || code > 599 {
+			return nil, fmt.Errorf("%s %q is not an HTTP status code", ProbeHTTPExpectedStatusAnnotation, status)
+		}
+		p.expectedStatus = code
+	}
+	return p, nil
+}
+
+// ValidateProbeAnnotations returns an error if the probe annotations are invalid.
+func ValidateProbeAnnotations(annotations map[string]string) error {
+	_, err := probeFor(&operatorcontrolplanev1alpha1.PodNetworkConnectivityCheck{ObjectMeta: metav1.ObjectMeta{Annotations: annotations}})
+	return err
+}
+
+// probeError is returned when a probe failed after the TCP connection was established, or could not
+// be performed at all. It carries the log entry reason and latency of the phase that failed.
+type probeError struct {
+	reason  string
+	start   time.Time
+	latency time.Duration
+	err     error
+}
+
+func (e *probeError) Error() string {
+	return e.err.Error()
+}
+
+func (e *probeError) Unwrap() error {
+	return e.err
+}
+
+// probePhase returns the log entry reason, start and latency of the request phase that is logged for
+// a successful probe of the given type, or false if the probe does not send a request.
+func probePhase(probeType ProbeType, latency *trace.LatencyInfo) (string, time.Time, time.Duration, bool) {
+	switch probeType {
+	case ProbeHTTP, ProbeHTTPS:
+		return LogEntryReasonHTTPRequest, latency.RequestStart, latency.Request, true
+	case ProbeGRPC, ProbeGRPCTLS:
+		return LogEntryReasonGRPCHealthCheck, latency.RequestStart, latency.Request, true
+	}
+	return "", time.Time{}, 0, false
+}
+
+// tlsConfig returns the client TLS configuration used to verify the target endpoint.
+func (c *connectionChecker) tlsConfig(p *probe, address string) (*tls.Config, error) {
+	serverName := p.serverName
+	if len(serverName) == 0 {
+		serverName, _, _ = net.SplitHostPort(address)
+	}
+	config := &tls.Config{
+		Certificates: c.clientCertGetter(),
+		ServerName:   serverName,
+	}
+	if len(p.caBundle) > 0 {
+		pool, err := c.caBundleGetter(p.caBundle)
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = pool
+	}
+	return config, nil
+}
+
+// dialTLS opens a TCP connection and performs a verified TLS handshake.
+func dialTLS(ctx context.Context, latencyInfo *trace.LatencyInfo, address string, config *tls.Config) (*tls.Conn, error) {
+	dialer := &net.Dialer{
+		Timeout: checkTimeout,
+	}
+	tcpConn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConn := tls.Client(tcpConn, config)
+	latencyInfo.StartTLSHandshake()
+	err = tlsConn.HandshakeContext(ctx)
+	latencyInfo.DoneTLSHandshake(tlsConn.ConnectionState(), err)
+	if err != nil {
+		_ = tcpConn.Close()
+		return nil, &probeError{reason: LogEntryReasonTLSHandshakeError, start: latencyInfo.TLSHandshakeStart, latency: latencyInfo.TLSHandshake, err: err}
+	}
+	return tlsConn, nil
+}
+
+// probeTLS performs a verified TLS handshake with the target endpoint.
+func (c *connectionChecker) probeTLS(ctx context.Context, latencyInfo *trace.LatencyInfo, address string, p *probe) error {
+	config, err := c.tlsConfig(p, address)
+	if err != nil {
+		return &probeError{reason: LogEntryReasonProbeConfigError, start: time.Now(), err: err}
+	}
+	tlsConn, err := dialTLS(ctx, latencyInfo, address, config)
+	if err != nil {
+		return err
+	}
+	// gracefully close connection (ignore error)
+	_ = tlsConn.Close()
+	return nil
+}
+
+// probeHTTP performs an HTTP GET against the target endpoint and checks the response status.
+func (c *connectionChecker) probeHTTP(ctx context.Context, latencyInfo *trace.LatencyInfo, address string, p *probe) error {
+	transport := &http.Transport{
+		DialContext:         (&net.Dialer{Timeout: checkTimeout}).DialContext,
+		TLSHandshakeTimeout: checkTimeout,
+		DisableKeepAlives:   true,
+	}
+	scheme := "http"
+	if p.probeType == ProbeHTTPS {
+		config, err := c.tlsConfig(p, address)
+		if err != nil {
+			return &probeError{reason: LogEntryReasonProbeConfigError, start: time.Now(), err: err}
+		}
+		transport.TLSClientConfig = config
+		scheme = "https"
+	}
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   checkTimeout,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	url := fmt.Sprintf("%s://%s%s", scheme, address, p.httpPath)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return &probeError{reason: LogEntryReasonProbeConfigError, start: time.Now(), err: err}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		switch {
+		case !latencyInfo.RequestStart.IsZero():
+			return &probeError{reason: LogEntryReasonHTTPRequestError, start: latencyInfo.RequestStart, latency: time.Since(latencyInfo.RequestStart), err: err}
+		case !latencyInfo.TLSHandshakeStart.IsZero():
+			return &probeError{reason: LogEntryReasonTLSHandshakeError, start: latencyInfo.TLSHandshakeStart, latency: latencyInfo.TLSHandshake, err: err}
+		}
+		// dns or tcp connect error
+		return err
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != p.expectedStatus {
+		return &probeError{
+			reason:  LogEntryReasonHTTPRequestError,
+			start:   latencyInfo.RequestStart,
+			latency: latencyInfo.Request,
+			err:     fmt.Errorf("GET %s returned status %d, expected %d", url, resp.StatusCode, p.expectedStatus),
+		}
+	}
+	return nil
+}
+
+// probeGRPC performs a gRPC health check against the target endpoint.
+func (c *connectionChecker) probeGRPC(ctx context.Context, latencyInfo *trace.LatencyInfo, address string, p *probe) error {
+	var conn net.Conn
+	if p.probeType == ProbeGRPCTLS {
+		config, err := c.tlsConfig(p, address)
+		if err != nil {
+			return &probeError{reason: LogEntryReasonProbeConfigError, start: time.Now(), err: err}
+		}
+		config.NextProtos = []string{"h2"}
+		tlsConn, err := dialTLS(ctx, latencyInfo, address, config)
+		if err != nil {
+			return err
+		}
+		conn = tlsConn
+	} else {
+		dialer := &net.Dialer{
+			Timeout: checkTimeout,
+		}
+		tcpConn, err := dialer.DialContext(ctx, "tcp", address)
+		if err != nil {
+			return err
+		}
+		conn = tcpConn
+	}
+
+	latencyInfo.StartRequest()
+	err := grpcHealthCheck(ctx, conn, address, p.grpcService)
+	latencyInfo.DoneRequest()
+	if err != nil {
+		return &probeError{reason: LogEntryReasonGRPCHealthCheckError, start: latencyInfo.RequestStart, latency: latencyInfo.Request, err: err}
+	}
+	return nil
+}
+
+// errConnectionUsed is returned when gRPC tries to reconnect, probes use exactly one connection.
+var errConnectionUsed = errors.New("probe connection already used")
+
+// grpcHealthCheck runs the gRPC health check protocol over the given, already established connection.
+func grpcHealthCheck(ctx context.Context, conn net.Conn, address, service string) error {
+	var once sync.Once
+	dialer := func(context.Context, string) (net.Conn, error) {
+		var dialed net.Conn
+		once.Do(func() { dialed = conn })
+		if dialed == nil {
+			return nil, errConnectionUsed
+		}
+		return dialed, nil
+	}
+	// the connection is already secured by TLS if required, so gRPC must not add its own transport security
+	clientConn, err := grpc.DialContext(ctx, address,
+		grpc.WithContextDialer(dialer),
+		grpc.WithInsecure(),
+		grpc.WithBlock(),
+		grpc.FailOnNonTempDialError(true),
+	)
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+	defer clientConn.Close()
+
+	resp, err := healthpb.NewHealthClient(clientConn).Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+	if err != nil {
+		return err
+	}
+	if resp.Status != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("health check of service %q returned %s", service, resp.Status)
+	}
+	return nil
+}
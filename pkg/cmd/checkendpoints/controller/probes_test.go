@@ -0,0 +1,231 @@
+package controller
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/api/operatorcontrolplane/v1alpha1"
+)
+
+func TestProbeFor(t *testing.T) {
+	testCases := []struct {
+		name        string
+		annotations map[string]string
+		expected    *probe
+		expectedErr string
+	}{
+		{
+			name:     "Defaults",
+			expected: &probe{probeType: ProbeTCP, httpPath: "/", expectedStatus: http.StatusOK},
+		},
+		{
+			name: "HTTPS",
+			annotations: map[string]string{
+				ProbeAnnotation:                   "https",
+				ProbeCABundleAnnotation:           "service-ca",
+				ProbeServerNameAnnotation:         "api.example.com",
+				ProbeHTTPPathAnnotation:           "/healthz",
+				ProbeHTTPExpectedStatusAnnotation: "204",
+			},
+			expected: &probe{probeType: ProbeHTTPS, caBundle: "service-ca", serverName: "api.example.com", httpPath: "/healthz", expectedStatus: http.StatusNoContent},
+		},
+		{
+			name:        "UnknownType",
+			annotations: map[string]string{ProbeAnnotation: "udp"},
+			expectedErr: "unknown",
+		},
+		{
+			name:        "RelativePath",
+			annotations: map[string]string{ProbeAnnotation: "http", ProbeHTTPPathAnnotation: "healthz"},
+			expectedErr: "must start with /",
+		},
+		{
+			name:        "InvalidStatus",
+			annotations: map[string]string{ProbeAnnotation: "http", ProbeHTTPExpectedStatusAnnotation: "ok"},
+			expectedErr: "is not an HTTP status code",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := probeFor(&v1alpha1.PodNetworkConnectivityCheck{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}})
+			if len(tc.expectedErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedErr) {
+					t.Fatalf("expected error containing %q, got %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *actual != *tc.expected {
+				t.Errorf("expected %#v, got %#v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestProbeEndpoint(t *testing.T) {
+	tlsServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/healthz" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer tlsServer.Close()
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer httpServer.Close()
+	grpcAddress := startHealthServer(t, healthpb.HealthCheckResponse_SERVING)
+
+	serverCA := x509.NewCertPool()
+	serverCA.AddCert(tlsServer.Certificate())
+	checker := &connectionChecker{
+		clientCertGetter: func() []tls.Certificate { return nil },
+		caBundleGetter: func(name string) (*x509.CertPool, error) {
+			if name != "server-ca" {
+				return nil, errors.New("not found")
+			}
+			return serverCA, nil
+		},
+		metrics: NewMetricsContext("test", "test"),
+	}
+
+	testCases := []struct {
+		name           string
+		targetEndpoint string
+		annotations    map[string]string
+		expectTLS      bool
+		expectedReason string
+	}{
+		{
+			name:           "TCP",
+			targetEndpoint: tlsServer.Listener.Addr().String(),
+			expectTLS:      true,
+		},
+		{
+			name:           "TLS",
+			targetEndpoint: tlsServer.Listener.Addr().String(),
+			annotations:    map[string]string{ProbeAnnotation: "tls", ProbeCABundleAnnotation: "server-ca", ProbeServerNameAnnotation: "example.com"},
+			expectTLS:      true,
+		},
+		{
+			name:           "TLSUnknownAuthority",
+			targetEndpoint: tlsServer.Listener.Addr().String(),
+			annotations:    map[string]string{ProbeAnnotation: "tls", ProbeServerNameAnnotation: "example.com"},
+			expectedReason: LogEntryReasonTLSHandshakeError,
+		},
+		{
+			name:           "TLSMissingCABundle",
+			targetEndpoint: tlsServer.Listener.Addr().String(),
+			annotations:    map[string]string{ProbeAnnotation: "tls", ProbeCABundleAnnotation: "missing"},
+			expectedReason: LogEntryReasonProbeConfigError,
+		},
+		{
+			name:           "HTTP",
+			targetEndpoint: httpServer.Listener.Addr().String(),
+			annotations:    map[string]string{ProbeAnnotation: "http"},
+		},
+		{
+			name:           "HTTPS",
+			targetEndpoint: tlsServer.Listener.Addr().String(),
+			annotations: map[string]string{
+				ProbeAnnotation:                   "https",
+				ProbeCABundleAnnotation:           "server-ca",
+				ProbeServerNameAnnotation:         "example.com",
+				ProbeHTTPPathAnnotation:           "/healthz",
+				ProbeHTTPExpectedStatusAnnotation: "204",
+			},
+			expectTLS: true,
+		},
+		{
+			name:           "HTTPSUnexpectedStatus",
+			targetEndpoint: tlsServer.Listener.Addr().String(),
+			annotations:    map[string]string{ProbeAnnotation: "https", ProbeCABundleAnnotation: "server-ca", ProbeServerNameAnnotation: "example.com"},
+			expectedReason: LogEntryReasonHTTPRequestError,
+		},
+		{
+			name:           "GRPC",
+			targetEndpoint: grpcAddress,
+			annotations:    map[string]string{ProbeAnnotation: "grpc"},
+		},
+		{
+			name:           "GRPCUnknownService",
+			targetEndpoint: grpcAddress,
+			annotations:    map[string]string{ProbeAnnotation: "grpc", ProbeGRPCServiceAnnotation: "unknown"},
+			expectedReason: LogEntryReasonGRPCHealthCheckError,
+		},
+		{
+			name:           "InvalidProbe",
+			targetEndpoint: tlsServer.Listener.Addr().String(),
+			annotations:    map[string]string{ProbeAnnotation: "udp"},
+			expectedReason: LogEntryReasonProbeConfigError,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			check := &v1alpha1.PodNetworkConnectivityCheck{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-to-target-endpoint", Annotations: tc.annotations},
+				Spec:       v1alpha1.PodNetworkConnectivityCheckSpec{TargetEndpoint: tc.targetEndpoint},
+			}
+			latencyInfo, err := checker.probeEndpoint(context.TODO(), check)
+			if len(tc.expectedReason) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if latencyInfo.ConnectStart.IsZero() {
+					t.Errorf("expected the tcp connect to be traced")
+				}
+				if tc.expectTLS && (len(latencyInfo.TLSVersion) == 0 || !latencyInfo.PeerCertificateNotAfter.Equal(tlsServer.Certificate().NotAfter)) {
+					t.Errorf("expected the tls handshake to be traced, got %#v", latencyInfo)
+				}
+				if _, _, _, ok := probePhase(probeTypeFor(check), latencyInfo); ok && latencyInfo.TimeToFirstByte <= 0 {
+					t.Errorf("expected the time to first byte to be traced")
+				}
+				return
+			}
+			var probeErr *probeError
+			if !errors.As(err, &probeErr) {
+				t.Fatalf("expected a probe error, got %v", err)
+			}
+			if probeErr.reason != tc.expectedReason {
+				t.Errorf("expected reason %s, got %s: %v", tc.expectedReason, probeErr.reason, probeErr)
+			}
+		})
+	}
+}
+
+// healthServer reports the given status for the overall server and SERVICE_UNKNOWN for any other service.
+type healthServer struct {
+	healthpb.UnimplementedHealthServer
+	status healthpb.HealthCheckResponse_ServingStatus
+}
+
+func (s *healthServer) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	if len(req.Service) > 0 {
+		return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVICE_UNKNOWN}, nil
+	}
+	return &healthpb.HealthCheckResponse{Status: s.status}, nil
+}
+
+func startHealthServer(t *testing.T, status healthpb.HealthCheckResponse_ServingStatus) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	healthpb.RegisterHealthServer(server, &healthServer{status: status})
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+	return listener.Addr().String()
+}
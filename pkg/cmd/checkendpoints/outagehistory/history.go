@@ -0,0 +1,236 @@
+package outagehistory
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	operatorcontrolplanev1alpha1 "github.com/openshift/api/operatorcontrolplane/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+const (
+	// HistoryLabel marks the configmaps holding the outage history of a PodNetworkConnectivityCheck.
+	HistoryLabel = "controlplane.operator.openshift.io/outage-history"
+	// HistoryKey is the configmap key holding the JSON encoded History.
+	HistoryKey = "history.json"
+	// MaxOutages is the number of outages kept per check, the oldest outages are discarded first.
+	MaxOutages = 500
+
+	namePrefix = "outage-history-"
+)
+
+// History is the compacted outage history of a PodNetworkConnectivityCheck. Unlike the check status,
+// which only keeps the latest 20 outages with their log entries, it keeps up to MaxOutages outages.
+type History struct {
+	CheckName      string `json:"checkName"`
+	SourcePod      string `json:"sourcePod"`
+	TargetEndpoint string `json:"targetEndpoint"`
+	// Since is when the history starts being complete: the start of the oldest outage in the check status
+	// when recording began, or of the oldest outage kept once older outages were discarded.
+	Since metav1.Time `json:"since"`
+	// Outages are sorted newest first, like PodNetworkConnectivityCheck.Status.Outages.
+	Outages []Outage `json:"outages,omitempty"`
+}
+
+// Outage is a compacted OutageEntry.
+type Outage struct {
+	Start metav1.Time `json:"start"`
+	// End is zero while the outage is ongoing.
+	End metav1.Time `json:"end"`
+	// Reason is the reason of the failure that started the outage.
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+func (o Outage) equal(other Outage) bool {
+	return o.Start.Equal(&other.Start) && o.End.Equal(&other.End) && o.Reason == other.Reason && o.Message == other.Message
+}
+
+// Name returns the name of the configmap holding the outage history of the named check.
+func Name(checkName string) string {
+	name := namePrefix + checkName
+	if len(name) <= 253 {
+		return name
+	}
+	return fmt.Sprintf("%s-%x", name[:236], sha256.Sum256([]byte(checkName)))[:253]
+}
+
+// Compact returns the compacted outage entries.
+func Compact(entries []operatorcontrolplanev1alpha1.OutageEntry) []Outage {
+	var outages []Outage
+	for _, entry := range entries {
+		// times are truncated to the precision of their serialization, to match the recorded outages
+		outage := Outage{Start: entry.Start.Rfc3339Copy(), Message: entry.Message}
+		if !entry.End.IsZero() {
+			outage.End = entry.End.Rfc3339Copy()
+		}
+		if len(entry.StartLogs) > 0 {
+			// start logs are sorted newest first
+			outage.Reason = entry.StartLogs[len(entry.StartLogs)-1].Reason
+		}
+		outages = append(outages, outage)
+	}
+	return outages
+}
+
+// Merge adds the outages to the history, replacing the outages that started at the same time, and
+// returns true if the history was modified. Since is moved back to the oldest outage, and forward to
+// the oldest outage kept when older outages are discarded.
+func Merge(history *History, outages []Outage) bool {
+	modified := false
+	for _, outage := range outages {
+		i := sort.Search(len(history.Outages), func(i int) bool {
+			return !history.Outages[i].Start.After(outage.Start.Time)
+		})
+		switch {
+		case i < len(history.Outages) && history.Outages[i].Start.Equal(&outage.Start):
+			if !history.Outages[i].equal(outage) {
+				history.Outages[i] = outage
+				modified = true
+			}
+		default:
+			history.Outages = append(history.Outages, Outage{})
+			copy(history.Outages[i+1:], history.Outages[i:])
+			history.Outages[i] = outage
+			modified = true
+		}
+	}
+	if len(history.Outages) > MaxOutages {
+		history.Outages = history.Outages[:MaxOutages]
+		history.Since = history.Outages[MaxOutages-1].Start
+		modified = true
+	}
+	if len(history.Outages) > 0 {
+		if oldest := history.Outages[len(history.Outages)-1].Start; oldest.Before(&history.Since) {
+			history.Since = oldest
+			modified = true
+		}
+	}
+	return modified
+}
+
+// Decode returns the history held by the configmap.
+func Decode(configMap *corev1.ConfigMap) (*History, error) {
+	history := &History{}
+	if err := json.Unmarshal([]byte(configMap.Data[HistoryKey]), history); err != nil {
+		return nil, fmt.Errorf("configmap/%s: unable to decode %s: %w", configMap.Name, HistoryKey, err)
+	}
+	return history, nil
+}
+
+// Recorder records the outages of PodNetworkConnectivityChecks in configmaps in the namespace of the checks.
+type Recorder struct {
+	client corev1client.ConfigMapsGetter
+
+	lock sync.Mutex
+	// recorded are the compacted status outages last recorded for each check, to avoid reading
+	// the configmap on every status update.
+	recorded map[string][]Outage
+}
+
+// NewRecorder returns a Recorder.
+func NewRecorder(client corev1client.ConfigMapsGetter) *Recorder {
+	return &Recorder{
+		client:   client,
+		recorded: map[string][]Outage{},
+	}
+}
+
+// Record merges the outages in the status of the check into its outage history.
+func (r *Recorder) Record(ctx context.Context, check *operatorcontrolplanev1alpha1.PodNetworkConnectivityCheck) error {
+	outages := Compact(check.Status.Outages)
+	r.lock.Lock()
+	recorded, ok := r.recorded[check.Name]
+	r.lock.Unlock()
+	if ok && equalOutages(recorded, outages) {
+		return nil
+	}
+
+	configMaps := r.client.ConfigMaps(check.Namespace)
+	configMap, err := configMaps.Get(ctx, Name(check.Name), metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		history := &History{Since: metav1.Now()}
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: check.Namespace,
+				Name:      Name(check.Name),
+				Labels:    map[string]string{HistoryLabel: "true"},
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: operatorcontrolplanev1alpha1.GroupVersion.String(),
+					Kind:       "PodNetworkConnectivityCheck",
+					Name:       check.Name,
+					UID:        check.UID,
+				}},
+			},
+		}
+		Merge(history, outages)
+		if err := encode(configMap, check, history); err != nil {
+			return err
+		}
+		if _, err := configMaps.Create(ctx, configMap, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+	case err != nil:
+		return err
+	default:
+		history, err := Decode(configMap)
+		if err != nil {
+			// start over rather than failing forever
+			history = &History{Since: metav1.Now()}
+		}
+		modified := Merge(history, outages)
+		if modified || history.TargetEndpoint != check.Spec.TargetEndpoint || history.SourcePod != check.Spec.SourcePod {
+			configMap = configMap.DeepCopy()
+			if err := encode(configMap, check, history); err != nil {
+				return err
+			}
+			if _, err := configMaps.Update(ctx, configMap, metav1.UpdateOptions{}); err != nil {
+				return err
+			}
+		}
+	}
+
+	r.lock.Lock()
+	r.recorded[check.Name] = outages
+	r.lock.Unlock()
+	return nil
+}
+
+// Forget drops the cached state of the named check.
+func (r *Recorder) Forget(checkName string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	delete(r.recorded, checkName)
+}
+
+func equalOutages(a, b []Outage) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func encode(configMap *corev1.ConfigMap, check *operatorcontrolplanev1alpha1.PodNetworkConnectivityCheck, history *History) error {
+	history.CheckName = check.Name
+	history.SourcePod = check.Spec.SourcePod
+	history.TargetEndpoint = check.Spec.TargetEndpoint
+	data, err := json.Marshal(history)
+	if err != nil {
+		return err
+	}
+	configMap.Data = map[string]string{HistoryKey: string(data)}
+	return nil
+}
@@ -0,0 +1,142 @@
+package outagehistory
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	operatorcontrolplanev1alpha1 "github.com/openshift/api/operatorcontrolplane/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+var baseTime = time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+
+func testTime(minutes int) metav1.Time {
+	return metav1.NewTime(baseTime.Add(time.Duration(minutes) * time.Minute))
+}
+
+func outage(start, end int) Outage {
+	o := Outage{Start: testTime(start), Reason: "TCPConnectError"}
+	if end > 0 {
+		o.End = testTime(end)
+	}
+	return o
+}
+
+func TestMerge(t *testing.T) {
+	history := &History{Since: testTime(0), Outages: []Outage{outage(10, 0), outage(1, 2)}}
+
+	if !Merge(history, []Outage{outage(20, 0), outage(10, 12)}) {
+		t.Fatal("expected the history to be modified")
+	}
+	expected := []Outage{outage(20, 0), outage(10, 12), outage(1, 2)}
+	if !reflect.DeepEqual(history.Outages, expected) {
+		t.Errorf("expected %v, got %v", expected, history.Outages)
+	}
+
+	if Merge(history, []Outage{outage(20, 0), outage(10, 12)}) {
+		t.Error("expected merging known outages not to modify the history")
+	}
+
+	// outages older than the start of the history move the start back
+	history = &History{Since: testTime(30)}
+	Merge(history, []Outage{outage(40, 0), outage(20, 25)})
+	if !history.Since.Equal(&history.Outages[1].Start) {
+		t.Errorf("expected the history to start with the oldest outage, got %v", history.Since)
+	}
+
+	history = &History{}
+	for i := 0; i < MaxOutages+10; i++ {
+		Merge(history, []Outage{outage(2*i+1, 2*i+2)})
+	}
+	if len(history.Outages) != MaxOutages {
+		t.Fatalf("expected %d outages, got %d", MaxOutages, len(history.Outages))
+	}
+	if newest := outage(2*(MaxOutages+9)+1, 2*(MaxOutages+9)+2); !reflect.DeepEqual(history.Outages[0], newest) {
+		t.Errorf("expected the oldest outages to be discarded, got %v first", history.Outages[0])
+	}
+	if oldest := history.Outages[MaxOutages-1].Start; !history.Since.Equal(&oldest) {
+		t.Errorf("expected the history to start with the oldest outage kept, got %v", history.Since)
+	}
+}
+
+func TestCompact(t *testing.T) {
+	entries := []operatorcontrolplanev1alpha1.OutageEntry{{
+		Start:   testTime(1),
+		End:     testTime(3),
+		Message: "Connectivity restored after 2m0s",
+		StartLogs: []operatorcontrolplanev1alpha1.LogEntry{
+			{Start: testTime(2), Reason: "TCPConnectError"},
+			{Start: testTime(1), Reason: "DNSError"},
+		},
+	}}
+	expected := []Outage{{Start: testTime(1), End: testTime(3), Reason: "DNSError", Message: "Connectivity restored after 2m0s"}}
+	if actual := Compact(entries); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestName(t *testing.T) {
+	if name := Name("apiserver-to-etcd"); name != "outage-history-apiserver-to-etcd" {
+		t.Errorf("unexpected name %s", name)
+	}
+	long := strings.Repeat("a", 253)
+	if name := Name(long); len(name) != 253 || name == Name(long[:252]+"b") {
+		t.Errorf("expected a unique name of at most 253 characters, got %s", name)
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	check := &operatorcontrolplanev1alpha1.PodNetworkConnectivityCheck{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "source-to-target", UID: "uid"},
+		Spec:       operatorcontrolplanev1alpha1.PodNetworkConnectivityCheckSpec{SourcePod: "source", TargetEndpoint: "host:port"},
+		Status: operatorcontrolplanev1alpha1.PodNetworkConnectivityCheckStatus{
+			Outages: []operatorcontrolplanev1alpha1.OutageEntry{{Start: testTime(1)}},
+		},
+	}
+	client := fake.NewSimpleClientset()
+	recorder := NewRecorder(client.CoreV1())
+
+	if err := recorder.Record(context.TODO(), check); err != nil {
+		t.Fatal(err)
+	}
+	// unchanged outages are not recorded again
+	if err := recorder.Record(context.TODO(), check); err != nil {
+		t.Fatal(err)
+	}
+	if actions := client.Actions(); len(actions) != 2 {
+		t.Errorf("expected a get and a create, got %v", actions)
+	}
+
+	// outages dropped from the status remain in the history
+	check.Status.Outages = []operatorcontrolplanev1alpha1.OutageEntry{{Start: testTime(5)}}
+	if err := recorder.Record(context.TODO(), check); err != nil {
+		t.Fatal(err)
+	}
+
+	configMap, err := client.CoreV1().ConfigMaps("test").Get(context.TODO(), "outage-history-source-to-target", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if configMap.Labels[HistoryLabel] != "true" || len(configMap.OwnerReferences) != 1 || configMap.OwnerReferences[0].UID != "uid" {
+		t.Errorf("unexpected configmap metadata %#v", configMap.ObjectMeta)
+	}
+	history, err := Decode(configMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if history.CheckName != "source-to-target" || history.SourcePod != "source" || history.TargetEndpoint != "host:port" {
+		t.Errorf("unexpected history %#v", history)
+	}
+	// the history starts with the outages already in the status when recording began
+	if since := testTime(1); !history.Since.Equal(&since) {
+		t.Errorf("expected the history to start at %v, got %v", since, history.Since)
+	}
+	expected := []Outage{{Start: testTime(5)}, {Start: testTime(1)}}
+	if !equalOutages(history.Outages, expected) {
+		t.Errorf("expected %v, got %v", expected, history.Outages)
+	}
+}
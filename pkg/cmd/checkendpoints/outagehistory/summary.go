@@ -0,0 +1,78 @@
+package outagehistory
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Summary describes the availability of the target endpoint of a check over a period of time.
+type Summary struct {
+	CheckName      string `json:"checkName"`
+	SourcePod      string `json:"sourcePod"`
+	TargetEndpoint string `json:"targetEndpoint"`
+	// Since is the start of the summarized period, which is the later of the requested start and the
+	// start of the history.
+	Since metav1.Time `json:"since"`
+	// Availability is the percentage of the period during which the target was reachable.
+	Availability float64 `json:"availability"`
+	// Outages is the number of outages overlapping the period, including an ongoing outage.
+	Outages int `json:"outages"`
+	// LongestOutage is the duration of the longest outage, an ongoing outage lasts until now.
+	LongestOutage metav1.Duration `json:"longestOutage"`
+	// MTTR is the mean time to restore connectivity of the outages that ended.
+	MTTR metav1.Duration `json:"mttr"`
+}
+
+// Summarize returns the summary of the history between since and now.
+func Summarize(history *History, since, now time.Time) Summary {
+	if history.Since.After(since) {
+		since = history.Since.Time
+	}
+	summary := Summary{
+		CheckName:      history.CheckName,
+		SourcePod:      history.SourcePod,
+		TargetEndpoint: history.TargetEndpoint,
+		Since:          metav1.NewTime(since),
+		Availability:   100,
+	}
+
+	var downtime, restoreTime time.Duration
+	var restored int
+	for _, outage := range history.Outages {
+		end := outage.End.Time
+		if outage.End.IsZero() {
+			end = now
+		}
+		if !end.After(since) || !outage.Start.Time.Before(now) {
+			continue
+		}
+		summary.Outages++
+
+		duration := end.Sub(outage.Start.Time)
+		if duration > summary.LongestOutage.Duration {
+			summary.LongestOutage.Duration = duration
+		}
+		if !outage.End.IsZero() {
+			restoreTime += duration
+			restored++
+		}
+
+		start := outage.Start.Time
+		if start.Before(since) {
+			start = since
+		}
+		if end.After(now) {
+			end = now
+		}
+		downtime += end.Sub(start)
+	}
+
+	if restored > 0 {
+		summary.MTTR.Duration = restoreTime / time.Duration(restored)
+	}
+	if period := now.Sub(since); period > 0 {
+		summary.Availability = 100 * float64(period-downtime) / float64(period)
+	}
+	return summary
+}
@@ -0,0 +1,66 @@
+package outagehistory
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSummarize(t *testing.T) {
+	testCases := []struct {
+		name     string
+		history  *History
+		since    int
+		expected Summary
+	}{
+		{
+			name:     "NoOutages",
+			history:  &History{Since: testTime(0)},
+			since:    0,
+			expected: Summary{Since: testTime(0), Availability: 100},
+		},
+		{
+			name:    "HistoryStartedAfterSince",
+			history: &History{Since: testTime(50), Outages: []Outage{outage(60, 70)}},
+			since:   0,
+			expected: Summary{
+				Since:         testTime(50),
+				Availability:  80,
+				Outages:       1,
+				LongestOutage: metav1.Duration{Duration: 10 * time.Minute},
+				MTTR:          metav1.Duration{Duration: 10 * time.Minute},
+			},
+		},
+		{
+			name:    "OngoingAndPartialOutages",
+			history: &History{Since: testTime(0), Outages: []Outage{outage(90, 0), outage(40, 60), outage(20, 30), outage(0, 10)}},
+			since:   5,
+			expected: Summary{
+				Since:         testTime(5),
+				Availability:  100 * float64(95-45) / 95,
+				Outages:       4,
+				LongestOutage: metav1.Duration{Duration: 20 * time.Minute},
+				MTTR:          metav1.Duration{Duration: 40 * time.Minute / 3},
+			},
+		},
+		{
+			name:    "OutageBeforeSince",
+			history: &History{Since: testTime(0), Outages: []Outage{outage(0, 10)}},
+			since:   50,
+			expected: Summary{
+				Since:        testTime(50),
+				Availability: 100,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := Summarize(tc.history, testTime(tc.since).Time, testTime(100).Time)
+			if !reflect.DeepEqual(actual, tc.expected) {
+				t.Errorf("expected %#v, got %#v", tc.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package checkendpoints
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/cmd/checkendpoints/outagehistory"
+)
+
+// reportOptions holds the values of the check-endpoints report command.
+type reportOptions struct {
+	Kubeconfig string
+	Namespace  string
+	Since      time.Duration
+	Output     string
+
+	KubeClient kubernetes.Interface
+	Out        io.Writer
+}
+
+// NewReportCommand creates the check-endpoints report command.
+func NewReportCommand() *cobra.Command {
+	o := &reportOptions{
+		Namespace: "openshift-kube-apiserver",
+		Since:     24 * time.Hour,
+		Output:    "table",
+		Out:       os.Stdout,
+	}
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Summarizes the availability of the connectivity check targets from the recorded outage history",
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.Validate(); err != nil {
+				klog.Fatal(err)
+			}
+			if err := o.Complete(); err != nil {
+				klog.Fatal(err)
+			}
+			if err := o.Run(context.Background()); err != nil {
+				klog.Fatal(err)
+			}
+		},
+	}
+	o.AddFlags(cmd.Flags())
+	return cmd
+}
+
+func (o *reportOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.Kubeconfig, "kubeconfig", o.Kubeconfig, "Path to the kubeconfig file. Defaults to the in-cluster configuration.")
+	fs.StringVarP(&o.Namespace, "namespace", "n", o.Namespace, "Namespace of the PodNetworkConnectivityChecks.")
+	fs.DurationVar(&o.Since, "since", o.Since, "Summarize the outages of this period up to now.")
+	fs.StringVarP(&o.Output, "output", "o", o.Output, "Output format, one of table or json.")
+}
+
+// Validate verifies the inputs.
+func (o *reportOptions) Validate() error {
+	if len(o.Namespace) == 0 {
+		return errors.New("missing required flag: --namespace")
+	}
+	if o.Since <= 0 {
+		return errors.New("--since must be positive")
+	}
+	if o.Output != "table" && o.Output != "json" {
+		return fmt.Errorf("unsupported --output %q, must be table or json", o.Output)
+	}
+	return nil
+}
+
+// Complete fills in missing values before command execution.
+func (o *reportOptions) Complete() error {
+	if o.KubeClient != nil {
+		return nil
+	}
+	restConfig, err := clientcmd.BuildConfigFromFlags("", o.Kubeconfig)
+	if err != nil {
+		return err
+	}
+	o.KubeClient, err = kubernetes.NewForConfig(restConfig)
+	return err
+}
+
+// Run prints the availability summary of every check with an outage history.
+func (o *reportOptions) Run(ctx context.Context) error {
+	configMaps, err := o.KubeClient.CoreV1().ConfigMaps(o.Namespace).List(ctx, metav1.ListOptions{LabelSelector: outagehistory.HistoryLabel})
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var summaries []outagehistory.Summary
+	for i := range configMaps.Items {
+		history, err := outagehistory.Decode(&configMaps.Items[i])
+		if err != nil {
+			klog.Warning(err)
+			continue
+		}
+		summaries = append(summaries, outagehistory.Summarize(history, now.Add(-o.Since), now))
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].CheckName < summaries[j].CheckName
+	})
+
+	if o.Output == "json" {
+		if summaries == nil {
+			summaries = []outagehistory.Summary{}
+		}
+		encoder := json.NewEncoder(o.Out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(summaries)
+	}
+
+	w := tabwriter.NewWriter(o.Out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "CHECK\tSOURCE\tTARGET\tSINCE\tAVAILABILITY\tOUTAGES\tLONGEST OUTAGE\tMTTR")
+	for _, s := range summaries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%.3f%%\t%d\t%s\t%s\n", s.CheckName, s.SourcePod, s.TargetEndpoint, s.Since.UTC().Format(time.RFC3339), s.Availability, s.Outages, s.LongestOutage.Round(time.Second), s.MTTR.Round(time.Second))
+	}
+	return w.Flush()
+}
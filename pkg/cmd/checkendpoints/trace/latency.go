@@ -2,6 +2,8 @@ package trace
 
 import (
 	"context"
+	"crypto/tls"
+	"fmt"
 	"net/http/httptrace"
 	"time"
 
@@ -11,8 +13,28 @@ import (
 type LatencyInfo struct {
 	DNS          time.Duration
 	Connect      time.Duration
-	DNSStart     time.Time
-	ConnectStart time.Time
+	TLSHandshake time.Duration
+	Request      time.Duration
+	// TimeToFirstByte is the time from the start of the check until the first response byte was received.
+	TimeToFirstByte time.Duration
+
+	DNSStart          time.Time
+	ConnectStart      time.Time
+	TLSHandshakeStart time.Time
+	RequestStart      time.Time
+
+	// TLSVersion, TLSCipherSuite and PeerCertificateNotAfter describe the connection negotiated by a
+	// successful TLS handshake. They are empty if no handshake completed.
+	TLSVersion              string
+	TLSCipherSuite          string
+	PeerCertificateNotAfter time.Time
+}
+
+var tlsVersionNames = map[uint16]string{
+	tls.VersionTLS10: "TLS 1.0",
+	tls.VersionTLS11: "TLS 1.1",
+	tls.VersionTLS12: "TLS 1.2",
+	tls.VersionTLS13: "TLS 1.3",
 }
 
 func (r *LatencyInfo) dnsStart() {
@@ -33,6 +55,59 @@ func (r *LatencyInfo) connectDone(addr string) {
 	r.Connect = time.Now().Sub(r.ConnectStart)
 }
 
+// StartTLSHandshake records the start of a TLS handshake not performed by an http.Client.
+func (r *LatencyInfo) StartTLSHandshake() {
+	r.TLSHandshakeStart = time.Now()
+}
+
+// DoneTLSHandshake records the end of a TLS handshake not performed by an http.Client, and the
+// negotiated connection parameters if the handshake succeeded.
+func (r *LatencyInfo) DoneTLSHandshake(state tls.ConnectionState, err error) {
+	r.TLSHandshake = time.Now().Sub(r.TLSHandshakeStart)
+	if err != nil {
+		return
+	}
+	r.TLSVersion = tlsVersionNames[state.Version]
+	if len(r.TLSVersion) == 0 {
+		r.TLSVersion = fmt.Sprintf("0x%04x", state.Version)
+	}
+	r.TLSCipherSuite = tls.CipherSuiteName(state.CipherSuite)
+	if len(state.PeerCertificates) > 0 {
+		r.PeerCertificateNotAfter = state.PeerCertificates[0].NotAfter
+	}
+}
+
+// ResetTLSHandshake discards a TLS handshake whose result is ignored.
+func (r *LatencyInfo) ResetTLSHandshake() {
+	r.TLSHandshake = 0
+	r.TLSHandshakeStart = time.Time{}
+	r.TLSVersion = ""
+	r.TLSCipherSuite = ""
+	r.PeerCertificateNotAfter = time.Time{}
+}
+
+// StartRequest records that a request is sent over an established connection.
+func (r *LatencyInfo) StartRequest() {
+	r.RequestStart = time.Now()
+}
+
+// DoneRequest records that the response to a request has been received.
+func (r *LatencyInfo) DoneRequest() {
+	now := time.Now()
+	r.Request = now.Sub(r.RequestStart)
+	r.TimeToFirstByte = now.Sub(r.start())
+}
+
+// start returns when the first traced phase of the check started.
+func (r *LatencyInfo) start() time.Time {
+	for _, start := range []time.Time{r.DNSStart, r.ConnectStart, r.RequestStart} {
+		if !start.IsZero() {
+			return start
+		}
+	}
+	return time.Now()
+}
+
 func WithLatencyInfoCapture(ctx context.Context) (context.Context, *LatencyInfo) {
 	trace := &LatencyInfo{}
 	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
@@ -52,5 +127,21 @@ func WithLatencyInfoCapture(ctx context.Context) (context.Context, *LatencyInfo)
 			trace.connectDone(addr)
 			klog.V(5).Infof("ConnectDone: %s,%s,%v\n", network, addr, err)
 		},
+		TLSHandshakeStart: func() {
+			trace.StartTLSHandshake()
+			klog.V(5).Infof("TLSHandshakeStart\n")
+		},
+		TLSHandshakeDone: func(state tls.ConnectionState, err error) {
+			trace.DoneTLSHandshake(state, err)
+			klog.V(5).Infof("TLSHandshakeDone: %v\n", err)
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			trace.StartRequest()
+			klog.V(5).Infof("GotConn: %s\n", info.Conn.RemoteAddr())
+		},
+		GotFirstResponseByte: func() {
+			trace.DoneRequest()
+			klog.V(5).Infof("GotFirstResponseByte\n")
+		},
 	}), trace
 }
@@ -0,0 +1,213 @@
+package recoveryapiserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/recovery"
+)
+
+// Options holds values shared by all recovery-apiserver subcommands.
+type Options struct {
+	PodManifestDir        string
+	StaticPodResourcesDir string
+	Timeout               time.Duration
+
+	Out io.Writer
+}
+
+// NewRecoveryApiserverCommand creates the recovery-apiserver command and its subcommands.
+func NewRecoveryApiserverCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "recovery-apiserver",
+		Short: "Manage a localhost recovery kube-apiserver for broken control planes",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+			os.Exit(1)
+		},
+	}
+
+	cmd.AddCommand(NewCreateCommand())
+	cmd.AddCommand(NewDestroyCommand())
+	cmd.AddCommand(NewStatusCommand())
+	cmd.AddCommand(NewKubeConfigCommand())
+
+	return cmd
+}
+
+func newOptions() *Options {
+	return &Options{
+		PodManifestDir:        "/etc/kubernetes/manifests",
+		StaticPodResourcesDir: "/etc/kubernetes/static-pod-resources",
+		Timeout:               5 * time.Minute,
+		Out:                   os.Stdout,
+	}
+}
+
+func newSubCommand(use, short string, run func(o *Options, ctx context.Context) error) *cobra.Command {
+	o := newOptions()
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.Validate(); err != nil {
+				klog.Fatal(err)
+			}
+			if err := o.Complete(); err != nil {
+				klog.Fatal(err)
+			}
+			if err := run(o, context.Background()); err != nil {
+				klog.Fatal(err)
+			}
+		},
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.PodManifestDir, "pod-manifest-dir", o.PodManifestDir, "Directory holding the kube-apiserver static pod manifest and where the recovery pod manifest is written.")
+	fs.StringVar(&o.StaticPodResourcesDir, "resource-dir", o.StaticPodResourcesDir, "Directory holding the static pod resources where the recovery resources are written.")
+	fs.DurationVar(&o.Timeout, "timeout", o.Timeout, "How long to wait for the recovery apiserver to become healthy.")
+}
+
+// Validate verifies the inputs.
+func (o *Options) Validate() error {
+	if len(o.PodManifestDir) == 0 {
+		return errors.New("missing required flag: --pod-manifest-dir")
+	}
+	if len(o.StaticPodResourcesDir) == 0 {
+		return errors.New("missing required flag: --resource-dir")
+	}
+	if o.Timeout <= 0 {
+		return errors.New("--timeout must be positive")
+	}
+
+	return nil
+}
+
+// Complete fills in missing values before command execution.
+func (o *Options) Complete() error {
+	return nil
+}
+
+func (o *Options) apiserver() *recovery.Apiserver {
+	return &recovery.Apiserver{
+		PodManifestDir:        o.PodManifestDir,
+		StaticPodResourcesDir: o.StaticPodResourcesDir,
+	}
+}
+
+// NewCreateCommand creates the recovery-apiserver create command.
+func NewCreateCommand() *cobra.Command {
+	return newSubCommand("create", "Create the recovery apiserver and wait for it to become healthy", (*Options).RunCreate)
+}
+
+// RunCreate writes the recovery apiserver manifest and resources, waits until the pod reports healthy
+// and prints the path of the admin kubeconfig. The manifest and resources are removed if the pod does
+// not become healthy in time.
+func (o *Options) RunCreate(ctx context.Context) error {
+	apiserver := o.apiserver()
+
+	if err := apiserver.Create(); err != nil {
+		return fmt.Errorf("failed to create recovery apiserver: %v", err)
+	}
+	klog.Infof("Recovery apiserver manifest written to %q, waiting up to %v for it to become healthy", apiserver.RecoveryPodManifestPath(), o.Timeout)
+
+	waitCtx, cancel := context.WithTimeout(ctx, o.Timeout)
+	defer cancel()
+	if err := apiserver.WaitForHealthz(waitCtx); err != nil {
+		if destroyErr := apiserver.Destroy(); destroyErr != nil {
+			klog.Errorf("Failed to destroy recovery apiserver: %v", destroyErr)
+		}
+		return err
+	}
+
+	klog.Infof("Recovery apiserver is healthy. Use `recovery-apiserver destroy` to remove it when done.")
+	fmt.Fprintf(o.Out, "export KUBECONFIG=%s\n", apiserver.AdminKubeconfigPath())
+
+	return nil
+}
+
+// NewDestroyCommand creates the recovery-apiserver destroy command.
+func NewDestroyCommand() *cobra.Command {
+	return newSubCommand("destroy", "Destroy the recovery apiserver and remove its resources", (*Options).RunDestroy)
+}
+
+// RunDestroy removes the recovery apiserver manifest and its resource dir.
+func (o *Options) RunDestroy(ctx context.Context) error {
+	apiserver := o.apiserver()
+
+	exists, err := apiserver.Exists()
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("recovery apiserver manifest %q not found", apiserver.RecoveryPodManifestPath())
+	}
+
+	return apiserver.Destroy()
+}
+
+// NewStatusCommand creates the recovery-apiserver status command.
+func NewStatusCommand() *cobra.Command {
+	return newSubCommand("status", "Report whether the recovery apiserver exists and is healthy", (*Options).RunStatus)
+}
+
+// RunStatus reports whether the recovery apiserver is present and healthy. It fails when it is not.
+func (o *Options) RunStatus(ctx context.Context) error {
+	apiserver := o.apiserver()
+
+	exists, err := apiserver.Exists()
+	if err != nil {
+		return err
+	}
+	if !exists {
+		fmt.Fprintf(o.Out, "Recovery apiserver: not present (no manifest at %q)\n", apiserver.RecoveryPodManifestPath())
+		return errors.New("recovery apiserver is not present")
+	}
+	fmt.Fprintf(o.Out, "Recovery apiserver: present (manifest %q)\n", apiserver.RecoveryPodManifestPath())
+
+	if err := apiserver.LoadKubeConfig(); err != nil {
+		return err
+	}
+
+	healthCtx, cancel := context.WithTimeout(ctx, o.Timeout)
+	defer cancel()
+	if err := apiserver.Healthz(healthCtx); err != nil {
+		fmt.Fprintf(o.Out, "Health: unhealthy (%v)\n", err)
+		return err
+	}
+	fmt.Fprintf(o.Out, "Health: ok\n")
+	fmt.Fprintf(o.Out, "Kubeconfig: %s\n", apiserver.AdminKubeconfigPath())
+
+	return nil
+}
+
+// NewKubeConfigCommand creates the recovery-apiserver kubeconfig command.
+func NewKubeConfigCommand() *cobra.Command {
+	return newSubCommand("kubeconfig", "Print the path of the recovery apiserver admin kubeconfig", (*Options).RunKubeConfig)
+}
+
+// RunKubeConfig prints the path of the admin kubeconfig of an existing recovery apiserver.
+func (o *Options) RunKubeConfig(ctx context.Context) error {
+	apiserver := o.apiserver()
+
+	if err := apiserver.LoadKubeConfig(); err != nil {
+		return err
+	}
+	fmt.Fprintln(o.Out, apiserver.AdminKubeconfigPath())
+
+	return nil
+}
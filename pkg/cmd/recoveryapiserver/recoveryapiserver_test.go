@@ -0,0 +1,95 @@
+package recoveryapiserver
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/recovery"
+)
+
+// newTestOptions returns the options using a kube-apiserver static pod fixture in a temporary dir.
+func newTestOptions(t *testing.T) (*Options, *bytes.Buffer) {
+	dir, err := ioutil.TempDir("", "recovery-apiserver")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	apiserver, err := recovery.WriteStaticPodFixture(dir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := &bytes.Buffer{}
+	return &Options{
+		PodManifestDir:        apiserver.PodManifestDir,
+		StaticPodResourcesDir: apiserver.StaticPodResourcesDir,
+		// nothing serves the recovery apiserver in the tests
+		Timeout: time.Second,
+		Out:     out,
+	}, out
+}
+
+func TestRunCreateCleansUpWhenUnhealthy(t *testing.T) {
+	o, _ := newTestOptions(t)
+	apiserver := o.apiserver()
+
+	err := o.RunCreate(context.TODO())
+	if err == nil || !strings.Contains(err.Error(), "timed out waiting for recovery apiserver to become healthy") {
+		t.Fatalf("expected a healthz timeout, got %v", err)
+	}
+	if _, err := os.Stat(apiserver.RecoveryPodManifestPath()); !os.IsNotExist(err) {
+		t.Errorf("expected the recovery pod manifest to be removed, got %v", err)
+	}
+	if _, err := os.Stat(apiserver.GetRecoveryResourcesDir()); !os.IsNotExist(err) {
+		t.Errorf("expected the recovery resources to be removed, got %v", err)
+	}
+}
+
+func TestRunStatus(t *testing.T) {
+	o, out := newTestOptions(t)
+
+	if err := o.RunStatus(context.TODO()); err == nil {
+		t.Fatalf("expected an error without a recovery apiserver")
+	}
+	if !strings.Contains(out.String(), "Recovery apiserver: not present") {
+		t.Errorf("unexpected output %q", out.String())
+	}
+
+	out.Reset()
+	if err := o.apiserver().Create(); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.RunStatus(context.TODO()); err == nil {
+		t.Fatalf("expected an error for an unhealthy recovery apiserver")
+	}
+	if !strings.Contains(out.String(), "Recovery apiserver: present") || !strings.Contains(out.String(), "Health: unhealthy") {
+		t.Errorf("unexpected output %q", out.String())
+	}
+}
+
+func TestRunDestroy(t *testing.T) {
+	o, _ := newTestOptions(t)
+	apiserver := o.apiserver()
+
+	if err := o.RunDestroy(context.TODO()); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("expected a not found error without a recovery apiserver, got %v", err)
+	}
+
+	if err := apiserver.Create(); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.RunDestroy(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(apiserver.RecoveryPodManifestPath()); !os.IsNotExist(err) {
+		t.Errorf("expected the recovery pod manifest to be removed, got %v", err)
+	}
+	if _, err := os.Stat(apiserver.GetRecoveryResourcesDir()); !os.IsNotExist(err) {
+		t.Errorf("expected the recovery resources to be removed, got %v", err)
+	}
+}
@@ -0,0 +1,314 @@
+package regeneratecerts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/klog/v2"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	configeversionedclient "github.com/openshift/client-go/config/clientset/versioned"
+	configexternalinformers "github.com/openshift/client-go/config/informers/externalversions"
+	"github.com/openshift/library-go/pkg/operator/certrotation"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/genericoperatorclient"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+	"github.com/openshift/library-go/pkg/operator/staticpod/controller/installer"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/cmd/certregenerationcontroller"
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator"
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/certrotationcontroller"
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/recovery"
+)
+
+// CertsDirName is the unrevisioned static pod resource dir the cert-syncer keeps up to date.
+const CertsDirName = "kube-apiserver-certs"
+
+// Options holds values to drive the regenerate-certificates command.
+type Options struct {
+	PodManifestDir        string
+	StaticPodResourcesDir string
+	Timeout               time.Duration
+	KeepRecoveryApiserver bool
+}
+
+// NewRegenerateCertsCommand creates the regenerate-certificates command.
+func NewRegenerateCertsCommand() *cobra.Command {
+	o := &Options{
+		PodManifestDir:        "/etc/kubernetes/manifests",
+		StaticPodResourcesDir: "/etc/kubernetes/static-pod-resources",
+		Timeout:               5 * time.Minute,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "regenerate-certificates",
+		Short: "Regenerate expired certificates using a recovery apiserver and write them to the static pod resources",
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.Validate(); err != nil {
+				klog.Fatal(err)
+			}
+			if err := o.Complete(); err != nil {
+				klog.Fatal(err)
+			}
+			if err := o.Run(context.Background()); err != nil {
+				klog.Fatal(err)
+			}
+		},
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.PodManifestDir, "pod-manifest-dir", o.PodManifestDir, "Directory holding the kube-apiserver static pod manifest and where the recovery pod manifest is written.")
+	fs.StringVar(&o.StaticPodResourcesDir, "resource-dir", o.StaticPodResourcesDir, "Directory holding the static pod resources. Regenerated certificates are written to its kube-apiserver-certs subdirectory.")
+	fs.DurationVar(&o.Timeout, "timeout", o.Timeout, "How long to wait for the recovery apiserver to become healthy and for the regeneration to finish.")
+	fs.BoolVar(&o.KeepRecoveryApiserver, "keep-recovery-apiserver", o.KeepRecoveryApiserver, "Do not destroy the recovery apiserver after the certificates were regenerated.")
+}
+
+// Validate verifies the inputs.
+func (o *Options) Validate() error {
+	if len(o.PodManifestDir) == 0 {
+		return errors.New("missing required flag: --pod-manifest-dir")
+	}
+	if len(o.StaticPodResourcesDir) == 0 {
+		return errors.New("missing required flag: --resource-dir")
+	}
+	if o.Timeout <= 0 {
+		return errors.New("--timeout must be positive")
+	}
+
+	return nil
+}
+
+// Complete fills in missing values before command execution.
+func (o *Options) Complete() error {
+	return nil
+}
+
+// Run starts a recovery apiserver, refreshes the expired certificates once through it and writes the
+// unrevisioned certificates back to disk so the kube-apiserver can start again. Revisioned resources are
+// left to the operator which rolls out a new revision once the control plane is back.
+func (o *Options) Run(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, o.Timeout)
+	defer cancel()
+
+	apiserver := &recovery.Apiserver{
+		PodManifestDir:        o.PodManifestDir,
+		StaticPodResourcesDir: o.StaticPodResourcesDir,
+	}
+	if err := apiserver.Create(); err != nil {
+		return fmt.Errorf("failed to create recovery apiserver: %v", err)
+	}
+	if !o.KeepRecoveryApiserver {
+		defer func() {
+			if err := apiserver.Destroy(); err != nil {
+				klog.Errorf("Failed to destroy recovery apiserver: %v", err)
+			}
+		}()
+	}
+
+	if err := apiserver.WaitForHealthz(ctx); err != nil {
+		return err
+	}
+
+	restConfig, err := apiserver.RestConfig()
+	if err != nil {
+		return err
+	}
+	kubeClient, err := apiserver.GetKubeClientset()
+	if err != nil {
+		return err
+	}
+	configClient, err := configeversionedclient.NewForConfig(restConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create config client: %w", err)
+	}
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+	operatorClient, dynamicInformers, err := genericoperatorclient.NewStaticPodOperatorClient(restConfig, operatorv1.GroupVersion.WithResource("kubeapiservers"))
+	if err != nil {
+		return err
+	}
+
+	eventRecorder := events.NewLoggingEventRecorder("cert-regeneration")
+
+	if err := regenerateCertificates(ctx, kubeClient, configClient, dynamicClient, operatorClient, dynamicInformers, eventRecorder); err != nil {
+		return err
+	}
+
+	if err := refreshClientCABundle(ctx, kubeClient, eventRecorder); err != nil {
+		return err
+	}
+
+	destinationDir := filepath.Join(o.StaticPodResourcesDir, CertsDirName)
+	if err := WriteCertsToDisk(ctx, kubeClient.CoreV1(), destinationDir, operatorclient.TargetNamespace, operator.CertConfigMaps, operator.CertSecrets); err != nil {
+		return err
+	}
+	klog.Infof("Regenerated certificates written to %q", destinationDir)
+
+	return nil
+}
+
+type dynamicInformersStarter interface {
+	Start(stopCh <-chan struct{})
+}
+
+func regenerateCertificates(
+	ctx context.Context,
+	kubeClient kubernetes.Interface,
+	configClient configeversionedclient.Interface,
+	dynamicClient dynamic.Interface,
+	operatorClient v1helpers.StaticPodOperatorClient,
+	dynamicInformers dynamicInformersStarter,
+	eventRecorder events.Recorder,
+) error {
+	configInformers := configexternalinformers.NewSharedInformerFactory(configClient, 10*time.Minute)
+	dnsInformers := dynamicinformer.NewDynamicSharedInformerFactory(dynamicClient, 10*time.Minute)
+	kubeInformersForNamespaces := newKubeInformersForNamespaces(kubeClient)
+
+	certRotationScale, err := certrotation.GetCertRotationScale(ctx, kubeClient, operatorclient.GlobalUserSpecifiedConfigNamespace)
+	if err != nil {
+		return err
+	}
+	defaultCertRotationConfig := certrotationcontroller.DefaultRotationConfig(certRotationScale)
+	certRotationConfig, err := certrotationcontroller.GetRotationConfig(ctx, kubeClient, defaultCertRotationConfig)
+	if err != nil {
+		// like the operator, which reports the invalid config
+		klog.Warningf("Using the default certificate rotation periods: %v", err)
+		certRotationConfig = defaultCertRotationConfig
+	}
+
+	certRotationController, err := certrotationcontroller.NewCertRotationControllerOnlyWhenExpired(
+		kubeClient,
+		operatorClient,
+		configInformers,
+		dnsInformers.ForResource(certrotationcontroller.DNSResource),
+		kubeInformersForNamespaces,
+		eventRecorder,
+		certRotationConfig,
+	)
+	if err != nil {
+		return err
+	}
+
+	configInformers.Start(ctx.Done())
+	dnsInformers.Start(ctx.Done())
+	kubeInformersForNamespaces.Start(ctx.Done())
+	dynamicInformers.Start(ctx.Done())
+
+	certRotationController.WaitForReady(ctx.Done())
+	if err := certRotationController.RunOnce(); err != nil {
+		return fmt.Errorf("failed to regenerate certificates: %w", err)
+	}
+
+	return nil
+}
+
+// refreshClientCABundle runs the CA bundle controller once. It uses fresh informers so that it observes the
+// signers the cert rotation has just written. Because the resource sync controller is not running we also
+// copy the aggregator client CA bundle into the target namespace ourselves.
+func refreshClientCABundle(ctx context.Context, kubeClient kubernetes.Interface, eventRecorder events.Recorder) error {
+	kubeInformersForNamespaces := newKubeInformersForNamespaces(kubeClient)
+
+	caBundleController, err := certregenerationcontroller.NewCABundleController(
+		kubeClient.CoreV1(),
+		kubeInformersForNamespaces,
+		eventRecorder,
+	)
+	if err != nil {
+		return err
+	}
+
+	kubeInformersForNamespaces.Start(ctx.Done())
+
+	if err := caBundleController.RunOnce(ctx); err != nil {
+		return fmt.Errorf("failed to refresh client CA bundle: %w", err)
+	}
+
+	_, _, err = resourceapply.SyncConfigMap(ctx, kubeClient.CoreV1(), eventRecorder,
+		operatorclient.GlobalMachineSpecifiedConfigNamespace, "kube-apiserver-aggregator-client-ca",
+		operatorclient.TargetNamespace, "aggregator-client-ca",
+		[]metav1.OwnerReference{},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to sync aggregator client CA bundle: %w", err)
+	}
+
+	return nil
+}
+
+func newKubeInformersForNamespaces(kubeClient kubernetes.Interface) v1helpers.KubeInformersForNamespaces {
+	return v1helpers.NewKubeInformersForNamespaces(
+		kubeClient,
+		operatorclient.GlobalMachineSpecifiedConfigNamespace,
+		operatorclient.GlobalUserSpecifiedConfigNamespace,
+		operatorclient.OperatorNamespace,
+		operatorclient.TargetNamespace,
+	)
+}
+
+// WriteCertsToDisk writes the given configmaps and secrets from namespace into destinationDir using the
+// same layout as the cert-syncer: <dir>/configmaps/<name>/<key> and <dir>/secrets/<name>/<key>.
+// Existing files with different content are backed up before being replaced.
+func WriteCertsToDisk(ctx context.Context, client corev1client.CoreV1Interface, destinationDir, namespace string, configMaps, secrets []installer.UnrevisionedResource) error {
+	for _, cm := range configMaps {
+		configMap, err := client.ConfigMaps(namespace).Get(ctx, cm.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) && cm.Optional {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get configmap %s/%s: %w", namespace, cm.Name, err)
+		}
+
+		contentDir := filepath.Join(destinationDir, "configmaps", cm.Name)
+		if err := recovery.EnsureDir(contentDir); err != nil {
+			return err
+		}
+		for filename, content := range configMap.Data {
+			if err := recovery.EnsureFileContent(filepath.Join(contentDir, filename), []byte(content)); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, s := range secrets {
+		secret, err := client.Secrets(namespace).Get(ctx, s.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) && s.Optional {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get secret %s/%s: %w", namespace, s.Name, err)
+		}
+
+		contentDir := filepath.Join(destinationDir, "secrets", s.Name)
+		if err := recovery.EnsureDir(contentDir); err != nil {
+			return err
+		}
+		for filename, content := range secret.Data {
+			if err := recovery.EnsureFileContent(filepath.Join(contentDir, filename), content); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
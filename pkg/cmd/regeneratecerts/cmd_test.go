@@ -0,0 +1,75 @@
+package regeneratecerts
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openshift/library-go/pkg/operator/staticpod/controller/installer"
+)
+
+func TestWriteCertsToDisk(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-kube-apiserver", Name: "client-ca"},
+			Data:       map[string]string{"ca-bundle.crt": "new-ca"},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-kube-apiserver", Name: "kubelet-client"},
+			Data:       map[string][]byte{"tls.crt": []byte("new-crt"), "tls.key": []byte("new-key")},
+		},
+	)
+
+	dir, err := ioutil.TempDir("", "regenerate-certs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// an existing file with stale content must be replaced
+	staleDir := filepath.Join(dir, "secrets", "kubelet-client")
+	if err := os.MkdirAll(staleDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(staleDir, "tls.crt"), []byte("expired-crt"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	err = WriteCertsToDisk(context.TODO(), client.CoreV1(), dir, "openshift-kube-apiserver",
+		[]installer.UnrevisionedResource{{Name: "client-ca"}, {Name: "trusted-ca-bundle", Optional: true}},
+		[]installer.UnrevisionedResource{{Name: "kubelet-client"}, {Name: "user-serving-cert", Optional: true}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for path, expected := range map[string]string{
+		"configmaps/client-ca/ca-bundle.crt": "new-ca",
+		"secrets/kubelet-client/tls.crt":     "new-crt",
+		"secrets/kubelet-client/tls.key":     "new-key",
+	} {
+		actual, err := ioutil.ReadFile(filepath.Join(dir, path))
+		if err != nil {
+			t.Errorf("failed to read %q: %v", path, err)
+			continue
+		}
+		if string(actual) != expected {
+			t.Errorf("expected %q to contain %q, got %q", path, expected, string(actual))
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "configmaps", "trusted-ca-bundle")); !os.IsNotExist(err) {
+		t.Errorf("expected optional missing configmap not to be written, got %v", err)
+	}
+
+	err = WriteCertsToDisk(context.TODO(), client.CoreV1(), dir, "openshift-kube-apiserver", nil, []installer.UnrevisionedResource{{Name: "missing"}})
+	if err == nil {
+		t.Errorf("expected an error for a missing required secret")
+	}
+}
@@ -0,0 +1,128 @@
+package render
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+
+	"github.com/openshift/library-go/pkg/assets"
+	genericrenderoptions "github.com/openshift/library-go/pkg/operator/render/options"
+)
+
+// errRenderedFilesDiffer is returned by a dry run when the rendered files do not match the existing ones.
+var errRenderedFilesDiffer = errors.New("rendered files differ from the existing files")
+
+// renderedFile is a file rendered into memory together with the on-disk file it is compared against.
+type renderedFile struct {
+	// Name is the path relative to the compared directory, used in the diff headers.
+	Name string
+	// Path is the existing file the rendered content is compared against.
+	Path string
+	Data []byte
+}
+
+// renderFiles renders the same manifests and bootstrap config that genericrender.WriteFiles would write,
+// but keeps them in memory. The files are compared against compareDir, except for the bootstrap config which
+// is compared against configPath.
+func renderFiles(opt *genericrenderoptions.GenericOptions, fileConfig *genericrenderoptions.FileConfig, templateData interface{}, compareDir, configPath string) ([]renderedFile, error) {
+	var files []renderedFile
+	for _, manifestDir := range []string{"bootstrap-manifests", "manifests"} {
+		manifests, err := assets.New(filepath.Join(opt.TemplatesDir, manifestDir), templateData, assets.OnlyYaml)
+		if err != nil {
+			return nil, fmt.Errorf("failed rendering assets: %v", err)
+		}
+		for _, manifest := range manifests {
+			name := filepath.Join(manifestDir, manifest.Name)
+			files = append(files, renderedFile{Name: name, Path: filepath.Join(compareDir, name), Data: manifest.Data})
+		}
+	}
+
+	configName, err := filepath.Rel(compareDir, configPath)
+	if err != nil || strings.HasPrefix(configName, "..") {
+		configName = filepath.Base(configPath)
+	}
+	files = append(files, renderedFile{Name: configName, Path: configPath, Data: fileConfig.BootstrapConfig})
+
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+
+	return files, nil
+}
+
+// diffRenderedFiles writes a unified diff between the existing and the rendered files to out. Existing
+// manifests that would not be rendered anymore are reported as removed. It returns true if anything differs.
+func diffRenderedFiles(out io.Writer, compareDir string, files []renderedFile) (bool, error) {
+	differ := false
+	rendered := map[string]bool{}
+
+	for _, file := range files {
+		rendered[file.Path] = true
+
+		existing, err := ioutil.ReadFile(file.Path)
+		if err != nil && !os.IsNotExist(err) {
+			return false, fmt.Errorf("failed to read %q: %v", file.Path, err)
+		}
+		fileDiffer, err := writeUnifiedDiff(out, file.Name, existing, file.Data)
+		if err != nil {
+			return false, err
+		}
+		differ = differ || fileDiffer
+	}
+
+	for _, manifestDir := range []string{"bootstrap-manifests", "manifests"} {
+		existingFiles, err := assets.LoadFilesRecursively(filepath.Join(compareDir, manifestDir), assets.OnlyYaml)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return false, fmt.Errorf("failed to read %q: %v", filepath.Join(compareDir, manifestDir), err)
+		}
+
+		names := make([]string, 0, len(existingFiles))
+		for name := range existingFiles {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			if rendered[filepath.Join(compareDir, manifestDir, name)] {
+				continue
+			}
+			if _, err := writeUnifiedDiff(out, filepath.Join(manifestDir, name), existingFiles[name], nil); err != nil {
+				return false, err
+			}
+			differ = true
+		}
+	}
+
+	return differ, nil
+}
+
+func writeUnifiedDiff(out io.Writer, name string, existing, rendered []byte) (bool, error) {
+	if bytes.Equal(existing, rendered) {
+		return false, nil
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(existing)),
+		B:        difflib.SplitLines(string(rendered)),
+		FromFile: filepath.Join("existing", name),
+		ToFile:   filepath.Join("rendered", name),
+		Context:  3,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to diff %q: %v", name, err)
+	}
+	if _, err := fmt.Fprint(out, diff); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -2,17 +2,15 @@ package render
 
 import (
 	"bytes"
-	"crypto/rand"
-	"crypto/rsa"
-	"crypto/x509"
 	"encoding/json"
-	"encoding/pem"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/ghodss/yaml"
 	"github.com/spf13/cobra"
@@ -23,10 +21,12 @@ import (
 	kyaml "k8s.io/apimachinery/pkg/util/yaml"
 	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
 	"k8s.io/klog/v2"
+	utilnet "k8s.io/utils/net"
 
 	configv1 "github.com/openshift/api/config/v1"
 	kubecontrolplanev1 "github.com/openshift/api/kubecontrolplane/v1"
 	"github.com/openshift/cluster-kube-apiserver-operator/bindata"
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/boundsatokensignercontroller"
 	libgoaudit "github.com/openshift/library-go/pkg/operator/apiserver/audit"
 	genericrender "github.com/openshift/library-go/pkg/operator/render"
 	genericrenderoptions "github.com/openshift/library-go/pkg/operator/render/options"
@@ -43,18 +43,32 @@ type renderOpts struct {
 	clusterConfigFile string
 	clusterAuthFile   string
 	infraConfigFile   string
+
+	boundSAKeyAlgorithm string
+
+	dryRun      bool
+	diffAgainst string
+	out         io.Writer
 }
 
-// NewRenderCommand creates a render command.
-func NewRenderCommand() *cobra.Command {
-	renderOpts := renderOpts{
+func newRenderOpts() *renderOpts {
+	return &renderOpts{
 		generic:  *genericrenderoptions.NewGenericOptions(),
 		manifest: *genericrenderoptions.NewManifestOptions("kube-apiserver", "openshift/origin-hyperkube:latest"),
 
 		lockHostPath:   "/var/run/kubernetes/lock",
 		etcdServerURLs: []string{"https://127.0.0.1:2379"},
 		etcdServingCA:  "root-ca.crt",
+
+		boundSAKeyAlgorithm: string(boundsatokensignercontroller.KeyAlgorithmRSA4096),
+
+		out: os.Stdout,
 	}
+}
+
+// NewRenderCommand creates a render command.
+func NewRenderCommand() *cobra.Command {
+	renderOpts := newRenderOpts()
 	cmd := &cobra.Command{
 		Use:   "render",
 		Short: "Render kubernetes API server bootstrap manifests, secrets and configMaps",
@@ -66,6 +80,10 @@ func NewRenderCommand() *cobra.Command {
 				klog.Fatal(err)
 			}
 			if err := renderOpts.Run(); err != nil {
+				if errors.Is(err, errRenderedFilesDiffer) {
+					klog.Error(err)
+					os.Exit(1)
+				}
 				klog.Fatal(err)
 			}
 		},
@@ -86,6 +104,9 @@ func (r *renderOpts) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&r.clusterConfigFile, "cluster-config-file", r.clusterConfigFile, "Openshift Cluster API Config file.")
 	fs.StringVar(&r.clusterAuthFile, "cluster-auth-file", r.clusterAuthFile, "Openshift Cluster Authentication API Config file.")
 	fs.StringVar(&r.infraConfigFile, "infra-config-file", "", "File containing infrastructure.config.openshift.io manifest.")
+	fs.StringVar(&r.boundSAKeyAlgorithm, "bound-sa-signing-key-algorithm", r.boundSAKeyAlgorithm, fmt.Sprintf("The algorithm of the generated bound service account token signing key, one of %v. Ignored when the keypair is supplied in --asset-input-dir.", boundsatokensignercontroller.SupportedKeyAlgorithms))
+	fs.BoolVar(&r.dryRun, "dry-run", r.dryRun, "Render into memory without writing any file and print a unified diff against the existing files in --asset-output-dir and --config-output-file. Exits non-zero when they differ.")
+	fs.StringVar(&r.diffAgainst, "diff-against", r.diffAgainst, "Like --dry-run, but print a unified diff against an existing asset directory instead of --asset-output-dir.")
 }
 
 // Validate verifies the inputs.
@@ -111,6 +132,9 @@ func (r *renderOpts) Validate() error {
 		return errors.New("missing etcd serving CA: --manifest-etcd-serving-ca")
 	}
 
+	if _, err := boundsatokensignercontroller.ParseKeyAlgorithm(r.boundSAKeyAlgorithm); err != nil {
+		return fmt.Errorf("invalid --bound-sa-signing-key-algorithm: %v", err)
+	}
 	if err := validateBoundSATokensSigningKeys(r.generic.AssetInputDir); err != nil {
 		return err
 	}
@@ -126,6 +150,9 @@ func (r *renderOpts) Complete() error {
 	if err := r.generic.Complete(); err != nil {
 		return err
 	}
+	if len(r.diffAgainst) > 0 {
+		r.dryRun = true
+	}
 	return nil
 }
 
@@ -151,7 +178,7 @@ type TemplateData struct {
 	// BindAddress is the IP address and port to bind to
 	BindAddress string
 
-	// BindNetwork is the network (tcp4 or tcp6) to bind to
+	// BindNetwork is the network (tcp4, tcp6 or tcp for dual-stack) to bind to
 	BindNetwork string
 
 	// TerminationGracePeriodSeconds is set in pod manifest
@@ -197,6 +224,8 @@ func (r *renderOpts) Run() error {
 
 	boundSAPublicPath := filepath.Join(r.generic.AssetInputDir, "bound-service-account-signing-key.pub")
 	boundSAPrivatePath := filepath.Join(r.generic.AssetInputDir, "bound-service-account-signing-key.key")
+	// generatedAssets holds keys generated during a dry run which must not be written into the asset input dir
+	generatedAssets := map[string][]byte{}
 	_, privStatErr := os.Stat(boundSAPrivatePath)
 	if privStatErr != nil {
 		if !os.IsNotExist(privStatErr) {
@@ -204,39 +233,36 @@ func (r *renderOpts) Run() error {
 		}
 
 		// the private key is missing => generate the keypair
-		pubPEM, privPEM, err := generateKeyPairPEM()
+		keyAlgorithm, err := boundsatokensignercontroller.ParseKeyAlgorithm(r.boundSAKeyAlgorithm)
 		if err != nil {
-			return fmt.Errorf("failed to generate an RSA keypair for bound SA token signing: %v", err)
-		}
-
-		if err := ioutil.WriteFile(boundSAPrivatePath, privPEM, os.FileMode(0600)); err != nil {
-			return fmt.Errorf("failed to write private key for bound SA token signing: %v", err)
+			return err
 		}
-
-		if err := ioutil.WriteFile(boundSAPublicPath, pubPEM, os.FileMode(0644)); err != nil {
-			return fmt.Errorf("failed to write public key for bound SA token verification: %v", err)
+		pubPEM, privPEM, err := boundsatokensignercontroller.GenerateKeyPairPEM(keyAlgorithm)
+		if err != nil {
+			return fmt.Errorf("failed to generate an %s keypair for bound SA token signing: %v", keyAlgorithm, err)
 		}
-	}
 
-	if len(renderConfig.ClusterCIDR) > 0 {
-		anyIPv4 := false
-		for _, cidr := range renderConfig.ClusterCIDR {
-			cidrBaseIP, _, err := net.ParseCIDR(cidr)
-			if err != nil {
-				return fmt.Errorf("invalid cluster CIDR %q: %v", cidr, err)
+		if r.dryRun {
+			generatedAssets[filepath.Base(boundSAPrivatePath)] = privPEM
+			generatedAssets[filepath.Base(boundSAPublicPath)] = pubPEM
+		} else {
+			if err := ioutil.WriteFile(boundSAPrivatePath, privPEM, os.FileMode(0600)); err != nil {
+				return fmt.Errorf("failed to write private key for bound SA token signing: %v", err)
 			}
-			if cidrBaseIP.To4() != nil {
-				anyIPv4 = true
-				break
+
+			if err := ioutil.WriteFile(boundSAPublicPath, pubPEM, os.FileMode(0644)); err != nil {
+				return fmt.Errorf("failed to write public key for bound SA token verification: %v", err)
 			}
 		}
-		if !anyIPv4 {
-			// Single-stack IPv6 cluster, so listen on IPv6 not IPv4.
-			renderConfig.BindAddress = "[::]:6443"
-			renderConfig.BindNetwork = "tcp6"
-		}
 	}
 
+	bindAddress, bindNetwork, err := bindAddressAndNetwork(renderConfig.ServiceCIDR, renderConfig.ClusterCIDR)
+	if err != nil {
+		return err
+	}
+	renderConfig.BindAddress = bindAddress
+	renderConfig.BindNetwork = bindNetwork
+
 	if len(r.infraConfigFile) > 0 {
 		infra, err := getInfrastructure(r.infraConfigFile)
 		if err != nil {
@@ -269,7 +295,36 @@ func (r *renderOpts) Run() error {
 		return err
 	}
 
-	return genericrender.WriteFiles(&r.generic, &renderConfig.FileConfig, renderConfig)
+	if !r.dryRun {
+		return genericrender.WriteFiles(&r.generic, &renderConfig.FileConfig, renderConfig)
+	}
+
+	for name, content := range generatedAssets {
+		renderConfig.Assets[name] = content
+	}
+
+	compareDir, configPath := r.generic.AssetOutputDir, r.generic.ConfigOutputFile
+	if len(r.diffAgainst) > 0 {
+		compareDir = r.diffAgainst
+		configPath = filepath.Join(r.diffAgainst, filepath.Base(r.generic.ConfigOutputFile))
+		if rel, err := filepath.Rel(r.generic.AssetOutputDir, r.generic.ConfigOutputFile); err == nil && !strings.HasPrefix(rel, "..") {
+			configPath = filepath.Join(r.diffAgainst, rel)
+		}
+	}
+
+	files, err := renderFiles(&r.generic, &renderConfig.FileConfig, renderConfig, compareDir, configPath)
+	if err != nil {
+		return err
+	}
+	differ, err := diffRenderedFiles(r.out, compareDir, files)
+	if err != nil {
+		return err
+	}
+	if differ {
+		return errRenderedFilesDiffer
+	}
+
+	return nil
 }
 
 func bootstrapDefaultConfig() ([]byte, error) {
@@ -461,6 +516,46 @@ func discoverCIDRsFromClusterAPI(clusterConfigFileData []byte, renderConfig *Tem
 	return nil
 }
 
+// bindAddressAndNetwork picks the address and network the bootstrap kube-apiserver listens on.
+// Single-stack clusters bind to their only family. Dual-stack clusters bind to both families
+// using "tcp" and the unspecified address of the primary family, which is the family of the
+// first service network (or of the first cluster network when no service network is known).
+func bindAddressAndNetwork(serviceCIDRs, clusterCIDRs []string) (string, string, error) {
+	hasIPv4, hasIPv6 := false, false
+	for _, cidr := range append(append([]string{}, serviceCIDRs...), clusterCIDRs...) {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return "", "", fmt.Errorf("invalid CIDR %q: %v", cidr, err)
+		}
+		if utilnet.IsIPv6CIDRString(cidr) {
+			hasIPv6 = true
+		} else {
+			hasIPv4 = true
+		}
+	}
+
+	primaryIPv6 := false
+	switch {
+	case len(serviceCIDRs) > 0:
+		primaryIPv6 = utilnet.IsIPv6CIDRString(serviceCIDRs[0])
+	case len(clusterCIDRs) > 0:
+		primaryIPv6 = utilnet.IsIPv6CIDRString(clusterCIDRs[0])
+	}
+
+	bindAddress := "0.0.0.0:6443"
+	if primaryIPv6 {
+		bindAddress = "[::]:6443"
+	}
+
+	switch {
+	case hasIPv4 && hasIPv6:
+		return bindAddress, "tcp", nil
+	case hasIPv6:
+		return "[::]:6443", "tcp6", nil
+	default:
+		return "0.0.0.0:6443", "tcp4", nil
+	}
+}
+
 func validateBoundSATokensSigningKeys(assetsDir string) error {
 	boundSAPublicPath := filepath.Join(assetsDir, "bound-service-account-signing-key.pub")
 	boundSAPrivatePath := filepath.Join(assetsDir, "bound-service-account-signing-key.key")
@@ -481,38 +576,22 @@ func validateBoundSATokensSigningKeys(assetsDir string) error {
 		} else if pubStatErr == nil {
 			return fmt.Errorf("%s was supplied, but the matching private key is missing", boundSAPublicPath)
 		}
+		return nil
 	}
 
-	return nil
-}
-
-func generateKeyPairPEM() (pubKeyPEM []byte, privKeyPEM []byte, err error) {
-	privKey, err := rsa.GenerateKey(rand.Reader, 4096)
+	privPEM, err := ioutil.ReadFile(boundSAPrivatePath)
 	if err != nil {
-		return nil, nil, err
+		return err
 	}
-	// convert the keys to PEM format
-	pubKeyBytes, err := x509.MarshalPKIXPublicKey(&privKey.PublicKey)
+	pubPEM, err := ioutil.ReadFile(boundSAPublicPath)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to encode pub key: %v", err)
+		return err
+	}
+	if err := boundsatokensignercontroller.ValidateKeyPairPEM(privPEM, pubPEM); err != nil {
+		return fmt.Errorf("invalid bound SA token signing keypair %s and %s: %v", boundSAPrivatePath, boundSAPublicPath, err)
 	}
 
-	pubKeyPEM = pem.EncodeToMemory(
-		&pem.Block{
-			Type:  "RSA PUBLIC KEY",
-			Bytes: pubKeyBytes,
-		},
-	)
-
-	privKeyBytes := x509.MarshalPKCS1PrivateKey(privKey)
-	privKeyPEM = pem.EncodeToMemory(
-		&pem.Block{
-			Type:  "RSA PRIVATE KEY",
-			Bytes: privKeyBytes,
-		},
-	)
-
-	return pubKeyPEM, privKeyPEM, nil
+	return nil
 }
 
 func getInfrastructure(file string) (*configv1.Infrastructure, error) {
@@ -11,6 +11,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/spf13/pflag"
 	"github.com/stretchr/testify/require"
 
 	corev1 "k8s.io/api/core/v1"
@@ -20,6 +21,7 @@ import (
 
 	configv1 "github.com/openshift/api/config/v1"
 	kubecontrolplanev1 "github.com/openshift/api/kubecontrolplane/v1"
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/boundsatokensignercontroller"
 	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/configobservation/configobservercontroller"
 	libgoaudit "github.com/openshift/library-go/pkg/operator/apiserver/audit"
 	genericrenderoptions "github.com/openshift/library-go/pkg/operator/render/options"
@@ -95,6 +97,24 @@ spec:
     - fd02::/112
     - 172.30.0.0/16
 status: {}
+`
+	networkConfigDualIPv4Primary = `
+apiVersion: config.openshift.io/v1
+kind: Network
+metadata:
+  creationTimestamp: null
+  name: cluster
+spec:
+  clusterNetwork:
+    - cidr: 10.128.0.0/14
+      hostPrefix: 23
+    - cidr: fd01::/48
+      hostPrefix: 64
+  networkType: OVNKubernetes
+  serviceNetwork:
+    - 172.30.0.0/16
+    - fd02::/112
+status: {}
 `
 
 	infrastructureHA = `
@@ -223,6 +243,82 @@ func TestDiscoverCIDRs(t *testing.T) {
 	}
 }
 
+func TestBindAddressAndNetwork(t *testing.T) {
+	tests := []struct {
+		name            string
+		serviceCIDRs    []string
+		clusterCIDRs    []string
+		expectedAddress string
+		expectedNetwork string
+		expectErr       bool
+	}{
+		{
+			name:            "no networks",
+			expectedAddress: "0.0.0.0:6443",
+			expectedNetwork: "tcp4",
+		},
+		{
+			name:            "single-stack IPv4",
+			serviceCIDRs:    []string{"172.30.0.0/16"},
+			clusterCIDRs:    []string{"10.128.0.0/14"},
+			expectedAddress: "0.0.0.0:6443",
+			expectedNetwork: "tcp4",
+		},
+		{
+			name:            "single-stack IPv6",
+			serviceCIDRs:    []string{"fd02::/112"},
+			clusterCIDRs:    []string{"fd01::/48"},
+			expectedAddress: "[::]:6443",
+			expectedNetwork: "tcp6",
+		},
+		{
+			name:            "dual-stack IPv4 primary",
+			serviceCIDRs:    []string{"172.30.0.0/16", "fd02::/112"},
+			clusterCIDRs:    []string{"10.128.0.0/14", "fd01::/48"},
+			expectedAddress: "0.0.0.0:6443",
+			expectedNetwork: "tcp",
+		},
+		{
+			name:            "dual-stack IPv6 primary",
+			serviceCIDRs:    []string{"fd02::/112", "172.30.0.0/16"},
+			clusterCIDRs:    []string{"fd01::/48", "10.128.0.0/14"},
+			expectedAddress: "[::]:6443",
+			expectedNetwork: "tcp",
+		},
+		{
+			name:            "dual-stack primary from cluster network without service network",
+			clusterCIDRs:    []string{"fd01::/48", "10.128.0.0/14"},
+			expectedAddress: "[::]:6443",
+			expectedNetwork: "tcp",
+		},
+		{
+			name:         "invalid CIDR",
+			serviceCIDRs: []string{"172.30.0.0"},
+			expectErr:    true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			address, network, err := bindAddressAndNetwork(tc.serviceCIDRs, tc.clusterCIDRs)
+			if tc.expectErr {
+				if err == nil {
+					t.Fatalf("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if address != tc.expectedAddress {
+				t.Errorf("expected address %q, got %q", tc.expectedAddress, address)
+			}
+			if network != tc.expectedNetwork {
+				t.Errorf("expected network %q, got %q", tc.expectedNetwork, network)
+			}
+		})
+	}
+}
+
 func TestRenderCommand(t *testing.T) {
 	assetsInputDir, err := ioutil.TempDir("", "testdata")
 	if err != nil {
@@ -327,10 +423,10 @@ func TestRenderCommand(t *testing.T) {
 				return ioutil.WriteFile(filepath.Join(assetsInputDir, "config-dual.yaml"), []byte(networkConfigDual), 0644)
 			},
 			testFunction: func(cfg *kubecontrolplanev1.KubeAPIServerConfig) error {
-				if cfg.ServingInfo.BindAddress != "0.0.0.0:6443" {
+				if cfg.ServingInfo.BindAddress != "[::]:6443" {
 					return fmt.Errorf("incorrect dual-stack BindAddress: %s", cfg.ServingInfo.BindAddress)
 				}
-				if cfg.ServingInfo.BindNetwork != "tcp4" {
+				if cfg.ServingInfo.BindNetwork != "tcp" {
 					return fmt.Errorf("incorrect dual-stack BindNetwork: %s", cfg.ServingInfo.BindNetwork)
 				}
 				if cfg.ServicesSubnet != "fd02::/112,172.30.0.0/16" {
@@ -339,6 +435,31 @@ func TestRenderCommand(t *testing.T) {
 				return nil
 			},
 		},
+		{
+			name: "checks BindAddress and ServicesSubnet under dual-stack with IPv4 primary",
+			args: []string{
+				"--asset-input-dir=" + assetsInputDir,
+				"--templates-input-dir=" + templateDir,
+				"--cluster-config-file=" + filepath.Join(assetsInputDir, "config-dual-v4-primary.yaml"),
+				"--asset-output-dir=",
+				"--config-output-file=",
+			},
+			setupFunction: func() error {
+				return ioutil.WriteFile(filepath.Join(assetsInputDir, "config-dual-v4-primary.yaml"), []byte(networkConfigDualIPv4Primary), 0644)
+			},
+			testFunction: func(cfg *kubecontrolplanev1.KubeAPIServerConfig) error {
+				if cfg.ServingInfo.BindAddress != "0.0.0.0:6443" {
+					return fmt.Errorf("incorrect dual-stack BindAddress: %s", cfg.ServingInfo.BindAddress)
+				}
+				if cfg.ServingInfo.BindNetwork != "tcp" {
+					return fmt.Errorf("incorrect dual-stack BindNetwork: %s", cfg.ServingInfo.BindNetwork)
+				}
+				if cfg.ServicesSubnet != "172.30.0.0/16,fd02::/112" {
+					return fmt.Errorf("incorrect dual-stack ServicesSubnet: %s", cfg.ServicesSubnet)
+				}
+				return nil
+			},
+		},
 		{
 			name: "checks service account issuer when authentication no exists",
 			args: []string{
@@ -451,17 +572,11 @@ spec:
 				"--config-output-file=",
 			},
 			setupFunction: func() error {
-				data := `DUMMY DATA`
-				if err := os.Mkdir(filepath.Join(assetsInputDir, "2"), 0700); err != nil {
-					return err
-				}
-				if err := ioutil.WriteFile(filepath.Join(assetsInputDir, "2", "bound-service-account-signing-key.key"), []byte(data), 0644); err != nil {
+				pub, priv, err := boundsatokensignercontroller.GenerateKeyPairPEM(boundsatokensignercontroller.KeyAlgorithmECDSAP256)
+				if err != nil {
 					return err
 				}
-				if err := ioutil.WriteFile(filepath.Join(assetsInputDir, "2", "bound-service-account-signing-key.pub"), []byte(data), 0644); err != nil {
-					return err
-				}
-				return nil
+				return writeBoundSAKeys(filepath.Join(assetsInputDir, "2"), pub, priv)
 			},
 			testFunction: func(cfg *kubecontrolplanev1.KubeAPIServerConfig) error {
 				if len(cfg.APIServerArguments["service-account-signing-key-file"]) == 0 {
@@ -695,6 +810,16 @@ func runRender(args ...string) error {
 	return c.Execute()
 }
 
+func writeBoundSAKeys(dir string, pub, priv []byte) error {
+	if err := os.Mkdir(dir, 0700); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "bound-service-account-signing-key.pub"), pub, 0644); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, "bound-service-account-signing-key.key"), priv, 0600)
+}
+
 func Test_renderOpts_Validate(t *testing.T) {
 	assetsInputDir, err := ioutil.TempDir("", "testdata")
 	if err != nil {
@@ -737,15 +862,37 @@ func Test_renderOpts_Validate(t *testing.T) {
 			name:          "user provided bound-sa-signing-key - both keys exist",
 			assetInputDir: filepath.Join(assetsInputDir, "2"),
 			setupFunction: func() error {
-				data := `DUMMY DATA`
-				if err := os.Mkdir(filepath.Join(assetsInputDir, "2"), 0700); err != nil {
+				pub, priv, err := boundsatokensignercontroller.GenerateKeyPairPEM(boundsatokensignercontroller.KeyAlgorithmECDSAP256)
+				if err != nil {
 					return err
 				}
-				if err := ioutil.WriteFile(filepath.Join(assetsInputDir, "2", "bound-service-account-signing-key.pub"), []byte(data), 0644); err != nil {
+				return writeBoundSAKeys(filepath.Join(assetsInputDir, "2"), pub, priv)
+			},
+		},
+		{
+			name:          "user provided bound-sa-signing-key - both keys exist but are not keys",
+			assetInputDir: filepath.Join(assetsInputDir, "4"),
+			setupFunction: func() error {
+				data := []byte(`DUMMY DATA`)
+				return writeBoundSAKeys(filepath.Join(assetsInputDir, "4"), data, data)
+			},
+			wantErr: true,
+		},
+		{
+			name:          "user provided bound-sa-signing-key - keys do not match",
+			assetInputDir: filepath.Join(assetsInputDir, "5"),
+			setupFunction: func() error {
+				pub, _, err := boundsatokensignercontroller.GenerateKeyPairPEM(boundsatokensignercontroller.KeyAlgorithmRSA2048)
+				if err != nil {
+					return err
+				}
+				_, priv, err := boundsatokensignercontroller.GenerateKeyPairPEM(boundsatokensignercontroller.KeyAlgorithmRSA2048)
+				if err != nil {
 					return err
 				}
-				return ioutil.WriteFile(filepath.Join(assetsInputDir, "2", "bound-service-account-signing-key.key"), []byte(data), 0600)
+				return writeBoundSAKeys(filepath.Join(assetsInputDir, "5"), pub, priv)
 			},
+			wantErr: true,
 		},
 		{
 			name:          "user provided bound-sa-signing-key - neither key exists",
@@ -788,3 +935,92 @@ func Test_renderOpts_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderDryRun(t *testing.T) {
+	assetsInputDir, err := ioutil.TempDir("", "testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(assetsInputDir)
+	templateDir := filepath.Join("..", "..", "..", "bindata", "bootkube")
+
+	teardown, outputDir, err := setupAssetOutputDir("dry_run")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardown()
+
+	args := setOutputFlags([]string{
+		"--asset-input-dir=" + assetsInputDir,
+		"--templates-input-dir=" + templateDir,
+		"--asset-output-dir=",
+		"--config-output-file=",
+	}, outputDir)
+	if err := runRender(args...); err != nil {
+		t.Fatal(err)
+	}
+
+	runDryRun := func(extraArgs ...string) (string, error) {
+		opts := newRenderOpts()
+		out := &bytes.Buffer{}
+		opts.out = out
+		fs := pflag.NewFlagSet("render", pflag.ContinueOnError)
+		opts.AddFlags(fs)
+		if err := fs.Parse(append(append([]string{}, args...), extraArgs...)); err != nil {
+			return "", err
+		}
+		if err := opts.Validate(); err != nil {
+			return "", err
+		}
+		if err := opts.Complete(); err != nil {
+			return "", err
+		}
+		err := opts.Run()
+		return out.String(), err
+	}
+
+	out, err := runDryRun("--dry-run")
+	if err != nil {
+		t.Fatalf("expected no difference after a fresh render, got %v:\n%s", err, out)
+	}
+	if len(out) != 0 {
+		t.Errorf("expected an empty diff, got:\n%s", out)
+	}
+
+	podManifest := filepath.Join(outputDir, "manifests", "bootstrap-manifests", "kube-apiserver-pod.yaml")
+	original, err := ioutil.ReadFile(podManifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(podManifest, append(original, []byte("# changed\n")...), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(outputDir, "manifests", "manifests", "stale.yaml"), []byte("kind: Stale\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	diffDir := filepath.Join(outputDir, "manifests")
+	out, err = runDryRun("--diff-against=" + diffDir)
+	if err != errRenderedFilesDiffer {
+		t.Fatalf("expected %v, got %v", errRenderedFilesDiffer, err)
+	}
+	for _, expected := range []string{
+		"--- existing/bootstrap-manifests/kube-apiserver-pod.yaml",
+		"+++ rendered/bootstrap-manifests/kube-apiserver-pod.yaml",
+		"-# changed",
+		"--- existing/manifests/stale.yaml",
+		"-kind: Stale",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected diff to contain %q, got:\n%s", expected, out)
+		}
+	}
+
+	rendered, err := ioutil.ReadFile(podManifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(rendered, []byte("# changed")) {
+		t.Errorf("expected dry run not to modify %q", podManifest)
+	}
+}
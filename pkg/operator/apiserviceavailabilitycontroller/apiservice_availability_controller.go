@@ -0,0 +1,215 @@
+package apiserviceavailabilitycontroller
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/management"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
+	apiregistrationinformers "k8s.io/kube-aggregator/pkg/client/informers/externalversions"
+	apiregistrationlistersv1 "k8s.io/kube-aggregator/pkg/client/listers/apiregistration/v1"
+)
+
+type apiServiceAvailabilityController struct {
+	factory.Controller
+	operatorClient   v1helpers.StaticPodOperatorClient
+	apiServiceLister apiregistrationlistersv1.APIServiceLister
+	serviceLister    corev1listers.ServiceLister
+
+	// dial opens a tls connection, it is replaced in tests
+	dial func(ctx context.Context, address string, config *tls.Config) error
+
+	// reported are the APIServices the availability metric is reported for
+	reported     sets.String
+	reportedLock sync.Mutex
+}
+
+// NewAPIServiceAvailabilityController sets a Degraded=True condition when an aggregated APIService is not
+// available, its service cannot be found, or a tls connection to its service cannot be established. The
+// availability of each APIService is also exposed as a metric.
+func NewAPIServiceAvailabilityController(
+	operatorClient v1helpers.StaticPodOperatorClient,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	apiregistrationInformers apiregistrationinformers.SharedInformerFactory,
+	recorder events.Recorder,
+) *apiServiceAvailabilityController {
+	kubeInformersForAllNamespaces := kubeInformersForNamespaces.InformersFor("")
+	c := &apiServiceAvailabilityController{
+		operatorClient:   operatorClient,
+		apiServiceLister: apiregistrationInformers.Apiregistration().V1().APIServices().Lister(),
+		serviceLister:    kubeInformersForAllNamespaces.Core().V1().Services().Lister(),
+		dial:             dialTLS,
+		reported:         sets.NewString(),
+	}
+	c.Controller = factory.New().
+		WithInformers(
+			apiregistrationInformers.Apiregistration().V1().APIServices().Informer(),
+			kubeInformersForAllNamespaces.Core().V1().Services().Informer(),
+		).
+		// tls reachability is not reflected in any watched resource
+		ResyncEvery(time.Minute).
+		WithSync(c.sync).
+		ToController("APIServiceAvailabilityController", recorder)
+	return c
+}
+
+func (c *apiServiceAvailabilityController) sync(ctx context.Context, controllerContext factory.SyncContext) error {
+	operatorSpec, _, _, err := c.operatorClient.GetOperatorState()
+	if err != nil {
+		return err
+	}
+	if !management.IsOperatorManaged(operatorSpec.ManagementState) {
+		return nil
+	}
+
+	_, _, err = v1helpers.UpdateStatus(ctx, c.operatorClient, c.updateAPIServiceAvailabilityDegraded(ctx))
+	return err
+}
+
+// apiServiceError is a problem with an APIService and the reason reported for it.
+type apiServiceError struct {
+	reason string
+	err    error
+}
+
+func (c *apiServiceAvailabilityController) updateAPIServiceAvailabilityDegraded(ctx context.Context) v1helpers.UpdateStatusFunc {
+	condition := operatorv1.OperatorCondition{
+		Type:   APIServiceAvailabilityDegradedType,
+		Status: operatorv1.ConditionUnknown,
+	}
+	apiServices, err := c.apiServiceLister.List(labels.Everything())
+	if err != nil {
+		condition.Message = err.Error()
+		return v1helpers.UpdateConditionFn(condition)
+	}
+
+	var msgs []string
+	reasons := sets.NewString()
+	available := map[string]bool{}
+	// APIServices of the same group usually share a service, connect to it once
+	connectErrs := map[string]error{}
+	for _, apiService := range apiServices {
+		// local APIServices are served by kube-apiserver itself
+		if apiService.Spec.Service == nil {
+			continue
+		}
+		apiServiceErr := c.assertAPIService(ctx, apiService, connectErrs)
+		available[apiService.Name] = apiServiceErr == nil
+		if apiServiceErr != nil {
+			msgs = append(msgs, fmt.Sprintf("%s: %s", apiService.Name, apiServiceErr.err))
+			reasons.Insert(apiServiceErr.reason)
+		}
+	}
+	c.reportAvailability(available)
+
+	switch reasons.Len() {
+	case 0:
+		condition.Status = operatorv1.ConditionFalse
+	case 1:
+		condition.Reason = reasons.List()[0]
+		condition.Status = operatorv1.ConditionTrue
+	default:
+		condition.Reason = APIServicesNotReadyReason
+		condition.Status = operatorv1.ConditionTrue
+	}
+	sort.Strings(msgs)
+	condition.Message = strings.Join(msgs, "\n")
+
+	return v1helpers.UpdateConditionFn(condition)
+}
+
+// assertAPIService checks the Available condition of the APIService, that its service exists and accepts
+// tls connections.
+func (c *apiServiceAvailabilityController) assertAPIService(ctx context.Context, apiService *apiregistrationv1.APIService, connectErrs map[string]error) *apiServiceError {
+	if err := assertAvailable(apiService); err != nil {
+		return &apiServiceError{reason: APIServiceNotAvailableReason, err: err}
+	}
+
+	reference := apiService.Spec.Service
+	if _, err := c.serviceLister.Services(reference.Namespace).Get(reference.Name); err != nil {
+		return &apiServiceError{reason: APIServiceServiceNotFoundReason, err: fmt.Errorf("unable to find service %s.%s: %v", reference.Name, reference.Namespace, err)}
+	}
+
+	host := reference.Name + "." + reference.Namespace + ".svc"
+	port := 443
+	if reference.Port != nil {
+		port = int(*reference.Port)
+	}
+	address := net.JoinHostPort(host, strconv.Itoa(port))
+	config := &tls.Config{ServerName: host, InsecureSkipVerify: apiService.Spec.InsecureSkipTLSVerify}
+	if len(apiService.Spec.CABundle) > 0 {
+		config.RootCAs = x509.NewCertPool()
+		config.RootCAs.AppendCertsFromPEM(apiService.Spec.CABundle)
+	}
+	key := fmt.Sprintf("%s/%t/%s", address, config.InsecureSkipVerify, apiService.Spec.CABundle)
+	err, ok := connectErrs[key]
+	if !ok {
+		err = c.dial(ctx, address, config)
+		connectErrs[key] = err
+	}
+	if err != nil {
+		return &apiServiceError{reason: APIServiceConnectionErrorReason, err: err}
+	}
+	return nil
+}
+
+func assertAvailable(apiService *apiregistrationv1.APIService) error {
+	for _, condition := range apiService.Status.Conditions {
+		if condition.Type != apiregistrationv1.Available {
+			continue
+		}
+		if condition.Status == apiregistrationv1.ConditionTrue {
+			return nil
+		}
+		return fmt.Errorf("not available: %s: %s", condition.Reason, condition.Message)
+	}
+	return fmt.Errorf("no Available condition reported")
+}
+
+// reportAvailability sets the availability metric of the given APIServices and removes it for the others.
+func (c *apiServiceAvailabilityController) reportAvailability(available map[string]bool) {
+	c.reportedLock.Lock()
+	defer c.reportedLock.Unlock()
+	for name := range c.reported {
+		if _, ok := available[name]; !ok {
+			apiServiceAvailableGauge.DeleteLabelValues(name)
+			c.reported.Delete(name)
+		}
+	}
+	for name, ok := range available {
+		value := 0.0
+		if ok {
+			value = 1
+		}
+		apiServiceAvailableGauge.WithLabelValues(name).Set(value)
+		c.reported.Insert(name)
+	}
+}
+
+// dialTLS opens a tcp connection and performs a tls handshake.
+func dialTLS(ctx context.Context, address string, config *tls.Config) error {
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: 5 * time.Second},
+		Config:    config,
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
@@ -0,0 +1,189 @@
+package apiserviceavailabilitycontroller
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"testing"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/component-base/metrics/testutil"
+	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
+	apiregistrationlistersv1 "k8s.io/kube-aggregator/pkg/client/listers/apiregistration/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestUpdateAPIServiceAvailabilityDegraded(t *testing.T) {
+	RegisterMetrics()
+
+	testCases := []struct {
+		name              string
+		apiServices       []*apiregistrationv1.APIService
+		services          []*corev1.Service
+		unreachable       sets.String
+		expected          operatorv1.OperatorCondition
+		expectedAvailable map[string]float64
+		expectedDials     int
+	}{
+		{
+			name:        "Local",
+			apiServices: []*apiregistrationv1.APIService{{ObjectMeta: metav1.ObjectMeta{Name: "v1.apps"}}},
+			expected: operatorv1.OperatorCondition{
+				Type:   APIServiceAvailabilityDegradedType,
+				Status: operatorv1.ConditionFalse,
+			},
+		},
+		{
+			name: "HappyPath",
+			apiServices: []*apiregistrationv1.APIService{
+				apiService("v1.apps.openshift.io", "openshift-apiserver", "api", apiregistrationv1.ConditionTrue),
+				apiService("v1.build.openshift.io", "openshift-apiserver", "api", apiregistrationv1.ConditionTrue),
+			},
+			services: []*corev1.Service{service("openshift-apiserver", "api")},
+			expected: operatorv1.OperatorCondition{
+				Type:   APIServiceAvailabilityDegradedType,
+				Status: operatorv1.ConditionFalse,
+			},
+			expectedAvailable: map[string]float64{"v1.apps.openshift.io": 1, "v1.build.openshift.io": 1},
+			// both APIServices share the service
+			expectedDials: 1,
+		},
+		{
+			name: "NotAvailable",
+			apiServices: []*apiregistrationv1.APIService{
+				apiService("v1beta1.metrics.k8s.io", "openshift-monitoring", "prometheus-adapter", apiregistrationv1.ConditionFalse),
+			},
+			services: []*corev1.Service{service("openshift-monitoring", "prometheus-adapter")},
+			expected: operatorv1.OperatorCondition{
+				Type:    APIServiceAvailabilityDegradedType,
+				Status:  operatorv1.ConditionTrue,
+				Reason:  APIServiceNotAvailableReason,
+				Message: "v1beta1.metrics.k8s.io: not available: FailedDiscoveryCheck: failing",
+			},
+			expectedAvailable: map[string]float64{"v1beta1.metrics.k8s.io": 0},
+		},
+		{
+			name: "ConnectionError",
+			apiServices: []*apiregistrationv1.APIService{
+				apiService("v1.custom.example.com", "custom", "api", apiregistrationv1.ConditionTrue),
+			},
+			services:    []*corev1.Service{service("custom", "api")},
+			unreachable: sets.NewString("api.custom.svc:8443"),
+			expected: operatorv1.OperatorCondition{
+				Type:    APIServiceAvailabilityDegradedType,
+				Status:  operatorv1.ConditionTrue,
+				Reason:  APIServiceConnectionErrorReason,
+				Message: "v1.custom.example.com: dial tcp api.custom.svc:8443: connection refused",
+			},
+			expectedAvailable: map[string]float64{"v1.custom.example.com": 0},
+			expectedDials:     1,
+		},
+		{
+			name: "MultipleProblems",
+			apiServices: []*apiregistrationv1.APIService{
+				apiService("v1.custom.example.com", "custom", "api", apiregistrationv1.ConditionTrue),
+				apiService("v1.packages.operators.coreos.com", "openshift-operator-lifecycle-manager", "packageserver-service", apiregistrationv1.ConditionTrue),
+				apiService("v1beta1.metrics.k8s.io", "openshift-monitoring", "prometheus-adapter", apiregistrationv1.ConditionTrue),
+			},
+			services:    []*corev1.Service{service("custom", "api"), service("openshift-monitoring", "prometheus-adapter")},
+			unreachable: sets.NewString("api.custom.svc:8443"),
+			expected: operatorv1.OperatorCondition{
+				Type:   APIServiceAvailabilityDegradedType,
+				Status: operatorv1.ConditionTrue,
+				Reason: APIServicesNotReadyReason,
+				Message: "v1.custom.example.com: dial tcp api.custom.svc:8443: connection refused\n" +
+					`v1.packages.operators.coreos.com: unable to find service packageserver-service.openshift-operator-lifecycle-manager: service "packageserver-service" not found`,
+			},
+			expectedAvailable: map[string]float64{"v1.custom.example.com": 0, "v1.packages.operators.coreos.com": 0, "v1beta1.metrics.k8s.io": 1},
+			expectedDials:     2,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			apiServiceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			for _, o := range tc.apiServices {
+				if err := apiServiceIndexer.Add(o); err != nil {
+					t.Fatal(err)
+				}
+			}
+			serviceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			for _, o := range tc.services {
+				if err := serviceIndexer.Add(o); err != nil {
+					t.Fatal(err)
+				}
+			}
+			dials := 0
+			c := &apiServiceAvailabilityController{
+				apiServiceLister: apiregistrationlistersv1.NewAPIServiceLister(apiServiceIndexer),
+				serviceLister:    corev1listers.NewServiceLister(serviceIndexer),
+				dial: func(ctx context.Context, address string, config *tls.Config) error {
+					dials++
+					if tc.unreachable.Has(address) {
+						return fmt.Errorf("dial tcp %s: connection refused", address)
+					}
+					return nil
+				},
+				reported: sets.NewString("removed.example.com"),
+			}
+			apiServiceAvailableGauge.WithLabelValues("removed.example.com").Set(1)
+
+			status := &operatorv1.OperatorStatus{}
+			if err := c.updateAPIServiceAvailabilityDegraded(context.TODO())(status); err != nil {
+				t.Fatal(err)
+			}
+			if len(status.Conditions) != 1 {
+				t.Fatalf("expected exactly one condition, got %v", status.Conditions)
+			}
+			actual := status.Conditions[0]
+			actual.LastTransitionTime = metav1.Time{}
+			if actual != tc.expected {
+				t.Errorf("expected condition %#v, got %#v", tc.expected, actual)
+			}
+			if dials != tc.expectedDials {
+				t.Errorf("expected %d dials, got %d", tc.expectedDials, dials)
+			}
+
+			if !c.reported.Equal(sets.StringKeySet(tc.expectedAvailable)) {
+				t.Errorf("expected the metric to be reported for %v, got %v", sets.StringKeySet(tc.expectedAvailable).List(), c.reported.List())
+			}
+			for name, expected := range tc.expectedAvailable {
+				value, err := testutil.GetGaugeMetricValue(apiServiceAvailableGauge.WithLabelValues(name))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if value != expected {
+					t.Errorf("expected %s availability %v, got %v", name, expected, value)
+				}
+			}
+		})
+	}
+}
+
+func apiService(name, namespace, serviceName string, available apiregistrationv1.ConditionStatus) *apiregistrationv1.APIService {
+	apiService := &apiregistrationv1.APIService{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: apiregistrationv1.APIServiceSpec{
+			Service: &apiregistrationv1.ServiceReference{Namespace: namespace, Name: serviceName},
+		},
+		Status: apiregistrationv1.APIServiceStatus{
+			Conditions: []apiregistrationv1.APIServiceCondition{{Type: apiregistrationv1.Available, Status: available}},
+		},
+	}
+	if namespace == "custom" {
+		apiService.Spec.Service.Port = pointer.Int32(8443)
+	}
+	if available != apiregistrationv1.ConditionTrue {
+		apiService.Status.Conditions[0].Reason = "FailedDiscoveryCheck"
+		apiService.Status.Conditions[0].Message = "failing"
+	}
+	return apiService
+}
+
+func service(namespace, name string) *corev1.Service {
+	return &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+}
@@ -0,0 +1,21 @@
+package apiserviceavailabilitycontroller
+
+const (
+	// APIServiceAvailabilityDegradedType is true when there is a problem with an aggregated APIService.
+	APIServiceAvailabilityDegradedType = "AggregatedAPIServiceAvailabilityError"
+)
+
+const (
+	// APIServiceNotAvailableReason indicates that the Available condition of an APIService is not true.
+	APIServiceNotAvailableReason = "APIServiceNotAvailable"
+
+	// APIServiceServiceNotFoundReason indicates that the service of an APIService could not be resolved.
+	APIServiceServiceNotFoundReason = "APIServiceServiceNotFound"
+
+	// APIServiceConnectionErrorReason indicates that a tls connection to the service of an APIService
+	// could not be established.
+	APIServiceConnectionErrorReason = "APIServiceConnectionError"
+
+	// APIServicesNotReadyReason indicates that APIServices are having a variety of problems.
+	APIServicesNotReadyReason = "APIServicesNotReady"
+)
@@ -0,0 +1,24 @@
+package apiserviceavailabilitycontroller
+
+import (
+	"sync"
+
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+var (
+	registerMetrics sync.Once
+
+	apiServiceAvailableGauge = metrics.NewGaugeVec(&metrics.GaugeOpts{
+		Name: "openshift_kube_apiserver_operator_apiservice_available",
+		Help: "Reports 1 if the aggregated APIService is available, its service exists and accepts tls connections, 0 otherwise.",
+	}, []string{"apiservice"})
+)
+
+// RegisterMetrics exposes the availability of the aggregated APIServices.
+func RegisterMetrics() {
+	registerMetrics.Do(func() {
+		legacyregistry.MustRegister(apiServiceAvailableGauge)
+	})
+}
@@ -3,10 +3,6 @@ package boundsatokensignercontroller
 import (
 	"bytes"
 	"context"
-	"crypto/rand"
-	"crypto/rsa"
-	"crypto/x509"
-	"encoding/pem"
 	"fmt"
 	"time"
 
@@ -18,9 +14,9 @@ import (
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/kubernetes"
 	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
-	"k8s.io/client-go/util/keyutil"
 	"k8s.io/klog/v2"
 
+	operatorv1 "github.com/openshift/api/operator/v1"
 	"github.com/openshift/library-go/pkg/controller/factory"
 	"github.com/openshift/library-go/pkg/operator/events"
 	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
@@ -33,7 +29,6 @@ const (
 	operatorNamespace = operatorclient.OperatorNamespace
 	targetNamespace   = operatorclient.TargetNamespace
 
-	keySize = 2048
 	// A new keypair will first be written to this secret in the operator namespace...
 	NextSigningKeySecretName = "next-bound-service-account-signing-key"
 	// ...and will copied to this secret in the operand namespace once
@@ -43,6 +38,36 @@ const (
 	PublicKeyKey         = "service-account.pub"
 
 	PublicKeyConfigMapName = "bound-sa-token-signing-certs"
+
+	// SigningConfigConfigMapName is an optional configmap in the operator namespace
+	// that allows an admin to configure how signing keys are generated.
+	SigningConfigConfigMapName = "bound-sa-token-signing-config"
+	// KeyAlgorithmConfigKey selects the algorithm of newly generated signing keys,
+	// one of SupportedKeyAlgorithms. It defaults to DefaultKeyAlgorithm.
+	KeyAlgorithmConfigKey = "keyAlgorithm"
+	// RotationIntervalConfigKey enables scheduled rotation of the signing key. The
+	// value is a duration, e.g. "8760h". Rotation is disabled when unset.
+	RotationIntervalConfigKey = "rotationInterval"
+	// MaxTokenLifetimeConfigKey is the longest lifetime of an issued bound token. Public
+	// keys that have not signed tokens for this long are pruned when rotation is enabled.
+	MaxTokenLifetimeConfigKey = "maxTokenLifetime"
+	// PromotionTimeoutConfigKey is how long the next signing key may wait for promotion
+	// before the controller reports PromotionDegradedConditionType.
+	PromotionTimeoutConfigKey = "promotionTimeout"
+
+	// PromotionDegradedConditionType is true when the next signing key could not be
+	// promoted to the operand namespace within the promotion timeout.
+	PromotionDegradedConditionType = "BoundSATokenSigningKeyPromotionDegraded"
+
+	// KeyCreatedAtAnnotation records when the keypair in the next signing key secret was generated.
+	KeyCreatedAtAnnotation = "kubeapiserver.openshift.io/bound-sa-key-created-at"
+	// SigningKeyAnnotation names the key of the public key configmap whose keypair is in the
+	// operand signing secret. It is set once the keypair has been promoted, so the revisions of
+	// the configmap record which keypair the apiservers installed from them sign with.
+	SigningKeyAnnotation = "kubeapiserver.openshift.io/bound-sa-signing-key"
+	// KeyRetiredAtAnnotationPrefix is followed by a key of the public key configmap and records
+	// since when the public key is not used for signing tokens anymore.
+	KeyRetiredAtAnnotationPrefix = "bound-sa-key-retired-at.kubeapiserver.openshift.io/"
 )
 
 // BoundSATokenSignerController manages the keypair used to sign bound
@@ -68,6 +93,7 @@ func NewBoundSATokenSignerController(
 
 	return factory.New().WithInformers(
 		kubeInformersForNamespaces.InformersFor(operatorNamespace).Core().V1().Secrets().Informer(),
+		kubeInformersForNamespaces.InformersFor(operatorNamespace).Core().V1().ConfigMaps().Informer(),
 		kubeInformersForNamespaces.InformersFor(targetNamespace).Core().V1().Secrets().Informer(),
 		kubeInformersForNamespaces.InformersFor(targetNamespace).Core().V1().ConfigMaps().Informer(),
 		operatorClient.Informer(),
@@ -79,6 +105,9 @@ func (c *BoundSATokenSignerController) sync(ctx context.Context, syncCtx factory
 		c.ensureNextOperatorSigningSecret,
 		c.ensurePublicKeyConfigMap,
 		c.ensureOperandSigningSecret,
+		c.ensureSigningKeyAnnotation,
+		c.pruneRetiredPublicKeys,
+		c.updatePromotionCondition,
 	}
 	errs := []error{}
 	for _, syncMethod := range syncMethods {
@@ -92,8 +121,9 @@ func (c *BoundSATokenSignerController) sync(ctx context.Context, syncCtx factory
 }
 
 // ensureNextOperatorSigningSecret ensures the existence of a secret in the operator
-// namespace containing an RSA keypair used for signing and validating bound service
-// account tokens.
+// namespace containing a keypair used for signing and validating bound service
+// account tokens. A supplied keypair is validated and only replaced by scheduled
+// rotation once it has been promoted to the operand namespace.
 func (c *BoundSATokenSignerController) ensureNextOperatorSigningSecret(ctx context.Context, syncCtx factory.SyncContext) error {
 	// Attempt to retrieve the operator secret
 	secret, err := c.secretClient.Secrets(operatorNamespace).Get(ctx, NextSigningKeySecretName, metav1.GetOptions{})
@@ -101,22 +131,32 @@ func (c *BoundSATokenSignerController) ensureNextOperatorSigningSecret(ctx conte
 		return err
 	}
 
+	config, err := c.signingConfig(ctx)
+	if err != nil {
+		return err
+	}
+
 	// Create or update the secret if it is missing or lacks the expected keypair data
 	needKeypair := secret == nil || len(secret.Data[PrivateKeyKey]) == 0 || len(secret.Data[PublicKeyKey]) == 0
-	if needKeypair {
-		klog.V(2).Infof("Creating a new signing secret for bound service account tokens.")
-		newSecret, err := newNextSigningSecret()
-		if err != nil {
-			return err
+	if !needKeypair {
+		if err := ValidateKeyPairPEM(secret.Data[PrivateKeyKey], secret.Data[PublicKeyKey]); err != nil {
+			return fmt.Errorf("secret %s/%s contains an invalid keypair: %v", operatorNamespace, NextSigningKeySecretName, err)
 		}
-
-		secret, _, err = resourceapply.ApplySecret(ctx, c.secretClient, syncCtx.Recorder(), newSecret)
-		if err != nil {
+		rotate, err := c.rotationDue(ctx, secret, config)
+		if err != nil || !rotate {
 			return err
 		}
+		klog.V(2).Infof("Rotating the signing secret for bound service account tokens created at %s.", keyCreationTime(secret).Format(time.RFC3339))
 	}
 
-	return nil
+	klog.V(2).Infof("Creating a new %s signing secret for bound service account tokens.", config.keyAlgorithm)
+	newSecret, err := newNextSigningSecret(config.keyAlgorithm)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = resourceapply.ApplySecret(ctx, c.secretClient, syncCtx.Recorder(), newSecret)
+	return err
 }
 
 // ensurePublicKeyConfigMap ensures that the public key in the operator secret is
@@ -161,8 +201,9 @@ func (c *BoundSATokenSignerController) ensurePublicKeyConfigMap(ctx context.Cont
 		// Increment until a unique name is found to ensure that the new public key
 		// does not overwrite an existing one. Except where key revocation is
 		// involved (which would require manual deletion of the verifying public
-		// key), existing public keys in the configmap should be maintained to
-		// minimize the potential for not being able to validate issued tokens.
+		// key) or scheduled rotation prunes keys that cannot have signed a valid
+		// token anymore, existing public keys in the configmap should be maintained
+		// to minimize the potential for not being able to validate issued tokens.
 		nextKeyIndex := len(configMap.Data) + 1
 		nextKeyKey := ""
 		for {
@@ -254,26 +295,49 @@ func (c *BoundSATokenSignerController) ensureOperandSigningSecret(ctx context.Co
 	return err
 }
 
+// updatePromotionCondition reports PromotionDegradedConditionType when the keypair in the
+// operator secret has been waiting for promotion to the operand namespace for longer than
+// the promotion timeout, e.g. because its public key never reaches all master nodes.
+func (c *BoundSATokenSignerController) updatePromotionCondition(ctx context.Context, syncCtx factory.SyncContext) error {
+	config, err := c.signingConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	operatorSecret, err := c.secretClient.Secrets(operatorNamespace).Get(ctx, NextSigningKeySecretName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	operandSecret, err := c.secretClient.Secrets(targetNamespace).Get(ctx, SigningKeySecretName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		operandSecret = nil
+	} else if err != nil {
+		return err
+	}
+
+	condition := operatorv1.OperatorCondition{
+		Type:   PromotionDegradedConditionType,
+		Status: operatorv1.ConditionFalse,
+	}
+	if pending := promotionPendingFor(operatorSecret, operandSecret, time.Now()); pending > config.promotionTimeout {
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "PromotionBlocked"
+		condition.Message = fmt.Sprintf("The next bound service account token signing key has been waiting for promotion for %v, its public key has not been synced to all master nodes.", pending.Round(time.Minute))
+	}
+
+	_, _, err = v1helpers.UpdateStaticPodStatus(ctx, c.operatorClient, v1helpers.UpdateStaticPodConditionFn(condition))
+	return err
+}
+
 // publicKeySyncedToAllNodes indicates whether the given public key is present on the
 // current revisions of the apiserver nodes by checking for the key with the
 // configmaps associated with those revisions.
 func (c *BoundSATokenSignerController) publicKeySyncedToAllNodes(ctx context.Context, publicKey string) (bool, error) {
-	_, operatorStatus, _, err := c.operatorClient.GetStaticPodOperatorState()
+	uniqueRevisions, err := operatorclient.CurrentRevisions(c.operatorClient)
 	if err != nil {
 		return false, err
 	}
 
-	// Collect the unique set of revisions of the apiserver nodes
-	revisionMap := map[int32]struct{}{}
-	uniqueRevisions := []int32{}
-	for _, nodeStatus := range operatorStatus.NodeStatuses {
-		revision := nodeStatus.CurrentRevision
-		if _, ok := revisionMap[revision]; !ok {
-			revisionMap[revision] = struct{}{}
-			uniqueRevisions = append(uniqueRevisions, revision)
-		}
-	}
-
 	// For each revision, check that the configmap for that revision contains the
 	// current public key. If any configmap for any given revision is missing or does
 	// not contain the public key, assume the public key is not present on that node.
@@ -292,16 +356,8 @@ func (c *BoundSATokenSignerController) publicKeySyncedToAllNodes(ctx context.Con
 }
 
 // newNextSigningSecret creates a new secret populated with a new keypair.
-func newNextSigningSecret() (*corev1.Secret, error) {
-	rsaKey, err := rsa.GenerateKey(rand.Reader, keySize)
-	if err != nil {
-		return nil, err
-	}
-	privateBytes, err := keyutil.MarshalPrivateKeyToPEM(rsaKey)
-	if err != nil {
-		return nil, err
-	}
-	publicBytes, err := publicKeyToPem(&rsaKey.PublicKey)
+func newNextSigningSecret(keyAlgorithm KeyAlgorithm) (*corev1.Secret, error) {
+	publicBytes, privateBytes, err := GenerateKeyPairPEM(keyAlgorithm)
 	if err != nil {
 		return nil, err
 	}
@@ -309,6 +365,9 @@ func newNextSigningSecret() (*corev1.Secret, error) {
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: operatorNamespace,
 			Name:      NextSigningKeySecretName,
+			Annotations: map[string]string{
+				KeyCreatedAtAnnotation: time.Now().UTC().Format(time.RFC3339),
+			},
 		},
 		Data: map[string][]byte{
 			PrivateKeyKey: privateBytes,
@@ -317,20 +376,6 @@ func newNextSigningSecret() (*corev1.Secret, error) {
 	}, nil
 }
 
-func publicKeyToPem(key *rsa.PublicKey) ([]byte, error) {
-	keyInBytes, err := x509.MarshalPKIXPublicKey(key)
-	if err != nil {
-		return nil, err
-	}
-	keyinPem := pem.EncodeToMemory(
-		&pem.Block{
-			Type:  "RSA PUBLIC KEY",
-			Bytes: keyInBytes,
-		},
-	)
-	return keyinPem, nil
-}
-
 func configMapHasValue(configMap *corev1.ConfigMap, desiredValue string) bool {
 	for _, value := range configMap.Data {
 		if value == desiredValue {
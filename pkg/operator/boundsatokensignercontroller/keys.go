@@ -0,0 +1,149 @@
+package boundsatokensignercontroller
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	"k8s.io/client-go/util/keyutil"
+)
+
+// KeyAlgorithm identifies the type and size of a bound service account token signing key.
+type KeyAlgorithm string
+
+const (
+	KeyAlgorithmRSA2048   KeyAlgorithm = "RSA-2048"
+	KeyAlgorithmRSA4096   KeyAlgorithm = "RSA-4096"
+	KeyAlgorithmECDSAP256 KeyAlgorithm = "ECDSA-P256"
+	KeyAlgorithmECDSAP384 KeyAlgorithm = "ECDSA-P384"
+
+	// DefaultKeyAlgorithm is used by the operator when no algorithm is configured.
+	DefaultKeyAlgorithm = KeyAlgorithmRSA2048
+)
+
+// SupportedKeyAlgorithms lists the algorithms that can be used to sign bound service account tokens.
+var SupportedKeyAlgorithms = []KeyAlgorithm{
+	KeyAlgorithmRSA2048,
+	KeyAlgorithmRSA4096,
+	KeyAlgorithmECDSAP256,
+	KeyAlgorithmECDSAP384,
+}
+
+// ParseKeyAlgorithm returns the KeyAlgorithm for the given name. Empty means DefaultKeyAlgorithm.
+func ParseKeyAlgorithm(name string) (KeyAlgorithm, error) {
+	if len(name) == 0 {
+		return DefaultKeyAlgorithm, nil
+	}
+	for _, alg := range SupportedKeyAlgorithms {
+		if strings.EqualFold(string(alg), name) {
+			return alg, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported bound service account signing key algorithm %q, must be one of %v", name, SupportedKeyAlgorithms)
+}
+
+// GenerateKeyPairPEM creates a new keypair of the given algorithm and returns the PEM encoded public and private key.
+func GenerateKeyPairPEM(alg KeyAlgorithm) (publicKeyPEM []byte, privateKeyPEM []byte, err error) {
+	var privateKey crypto.Signer
+	switch alg {
+	case KeyAlgorithmRSA2048:
+		privateKey, err = rsa.GenerateKey(rand.Reader, 2048)
+	case KeyAlgorithmRSA4096:
+		privateKey, err = rsa.GenerateKey(rand.Reader, 4096)
+	case KeyAlgorithmECDSAP256:
+		privateKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case KeyAlgorithmECDSAP384:
+		privateKey, err = ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	default:
+		return nil, nil, fmt.Errorf("unsupported bound service account signing key algorithm %q", alg)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	privateKeyPEM, err = keyutil.MarshalPrivateKeyToPEM(privateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	publicKeyPEM, err = publicKeyToPem(privateKey.Public())
+	if err != nil {
+		return nil, nil, err
+	}
+	return publicKeyPEM, privateKeyPEM, nil
+}
+
+// ValidateKeyPairPEM checks that the PEM encoded private and public key are an RSA or ECDSA keypair and belong together.
+// Any key size and curve is accepted, SupportedKeyAlgorithms only restricts the keys generated by the operator.
+func ValidateKeyPairPEM(privateKeyPEM, publicKeyPEM []byte) error {
+	privateKey, err := keyutil.ParsePrivateKeyPEM(privateKeyPEM)
+	if err != nil {
+		return fmt.Errorf("failed to parse private key: %v", err)
+	}
+	var signer crypto.Signer
+	switch k := privateKey.(type) {
+	case *rsa.PrivateKey:
+		signer = k
+	case *ecdsa.PrivateKey:
+		signer = k
+	default:
+		return fmt.Errorf("unsupported private key type %T, must be RSA or ECDSA", privateKey)
+	}
+
+	publicKeys, err := keyutil.ParsePublicKeysPEM(publicKeyPEM)
+	if err != nil {
+		return fmt.Errorf("failed to parse public key: %v", err)
+	}
+	if len(publicKeys) != 1 {
+		return fmt.Errorf("expected exactly one public key, found %d", len(publicKeys))
+	}
+
+	publicKey, ok := publicKeys[0].(interface{ Equal(crypto.PublicKey) bool })
+	if !ok || !publicKey.Equal(signer.Public()) {
+		return fmt.Errorf("the public key does not match the private key")
+	}
+
+	return nil
+}
+
+// PublicKeyFingerprint returns the hex encoded SHA-256 digest of the DER encoding of the PEM encoded public key.
+func PublicKeyFingerprint(publicKeyPEM []byte) (string, error) {
+	publicKeys, err := keyutil.ParsePublicKeysPEM(publicKeyPEM)
+	if err != nil {
+		return "", err
+	}
+	if len(publicKeys) != 1 {
+		return "", fmt.Errorf("expected exactly one public key, found %d", len(publicKeys))
+	}
+	der, err := x509.MarshalPKIXPublicKey(publicKeys[0])
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func publicKeyToPem(key crypto.PublicKey) ([]byte, error) {
+	keyInBytes, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return nil, err
+	}
+	blockType := "PUBLIC KEY"
+	if _, ok := key.(*rsa.PublicKey); ok {
+		blockType = "RSA PUBLIC KEY"
+	}
+	keyinPem := pem.EncodeToMemory(
+		&pem.Block{
+			Type:  blockType,
+			Bytes: keyInBytes,
+		},
+	)
+	return keyinPem, nil
+}
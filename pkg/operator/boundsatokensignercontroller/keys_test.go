@@ -0,0 +1,98 @@
+package boundsatokensignercontroller
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"k8s.io/client-go/util/keyutil"
+)
+
+func TestGenerateAndValidateKeyPairPEM(t *testing.T) {
+	for _, alg := range SupportedKeyAlgorithms {
+		t.Run(string(alg), func(t *testing.T) {
+			pub, priv, err := GenerateKeyPairPEM(alg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := ValidateKeyPairPEM(priv, pub); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestValidateSuppliedKeyPairPEM(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 3072)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, key := range map[string]crypto.Signer{"RSA-3072": rsaKey, "ECDSA-P521": ecdsaKey} {
+		t.Run(name, func(t *testing.T) {
+			priv, err := keyutil.MarshalPrivateKeyToPEM(key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pub, err := publicKeyToPem(key.Public())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := ValidateKeyPairPEM(priv, pub); err != nil {
+				t.Errorf("expected a supplied %s keypair to be accepted: %v", name, err)
+			}
+		})
+	}
+}
+
+func TestValidateKeyPairPEMMismatch(t *testing.T) {
+	pub, _, err := GenerateKeyPairPEM(KeyAlgorithmECDSAP256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, priv, err := GenerateKeyPairPEM(KeyAlgorithmECDSAP256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidateKeyPairPEM(priv, pub); err == nil {
+		t.Errorf("expected mismatching keys to be rejected")
+	}
+
+	_, rsaPriv, err := GenerateKeyPairPEM(KeyAlgorithmRSA2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidateKeyPairPEM(rsaPriv, pub); err == nil {
+		t.Errorf("expected keys of different algorithms to be rejected")
+	}
+}
+
+func TestParseKeyAlgorithm(t *testing.T) {
+	tests := []struct {
+		name      string
+		expected  KeyAlgorithm
+		expectErr bool
+	}{
+		{name: "", expected: DefaultKeyAlgorithm},
+		{name: "RSA-4096", expected: KeyAlgorithmRSA4096},
+		{name: "ecdsa-p384", expected: KeyAlgorithmECDSAP384},
+		{name: "Ed25519", expectErr: true},
+		{name: "RSA-1024", expectErr: true},
+	}
+	for _, tc := range tests {
+		actual, err := ParseKeyAlgorithm(tc.name)
+		if (err != nil) != tc.expectErr {
+			t.Errorf("%q: unexpected error %v", tc.name, err)
+			continue
+		}
+		if actual != tc.expected {
+			t.Errorf("%q: expected %q, got %q", tc.name, tc.expected, actual)
+		}
+	}
+}
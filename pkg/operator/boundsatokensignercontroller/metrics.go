@@ -0,0 +1,134 @@
+package boundsatokensignercontroller
+
+import (
+	"bytes"
+	"sync"
+	"time"
+
+	"github.com/blang/semver/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/component-base/metrics/legacyregistry"
+
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+var registerMetrics sync.Once
+
+// RegisterMetrics exposes metrics about the bound service account token signing keys.
+func RegisterMetrics(kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces) {
+	registerMetrics.Do(func() {
+		legacyregistry.MustRegister(newSigningKeyMetrics(
+			kubeInformersForNamespaces.InformersFor(operatorNamespace).Core().V1().Secrets().Lister(),
+			kubeInformersForNamespaces.InformersFor(targetNamespace).Core().V1().Secrets().Lister(),
+			kubeInformersForNamespaces.InformersFor(targetNamespace).Core().V1().ConfigMaps().Lister(),
+		))
+	})
+}
+
+// signingKeyMetrics computes metrics from the cached signing key secrets and public key configmap.
+type signingKeyMetrics struct {
+	operatorSecretLister corev1listers.SecretLister
+	operandSecretLister  corev1listers.SecretLister
+	configMapLister      corev1listers.ConfigMapLister
+
+	publicKeys       prometheus.Gauge
+	keyCreation      *prometheus.GaugeVec
+	activeKey        *prometheus.GaugeVec
+	pendingPromotion prometheus.Gauge
+}
+
+func newSigningKeyMetrics(operatorSecretLister, operandSecretLister corev1listers.SecretLister, configMapLister corev1listers.ConfigMapLister) *signingKeyMetrics {
+	return &signingKeyMetrics{
+		operatorSecretLister: operatorSecretLister,
+		operandSecretLister:  operandSecretLister,
+		configMapLister:      configMapLister,
+		publicKeys: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "openshift_kube_apiserver_operator_bound_sa_token_signing_public_keys",
+			Help: "Reports the number of public keys used to verify bound service account tokens.",
+		}),
+		keyCreation: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "openshift_kube_apiserver_operator_bound_sa_token_signing_key_creation_timestamp_seconds",
+			Help: "Reports when the bound service account token signing keys were generated. key is active for the key kube-apiserver signs tokens with and next for the key waiting to be promoted.",
+		}, []string{"key", "fingerprint"}),
+		activeKey: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "openshift_kube_apiserver_operator_bound_sa_token_signing_active_key_info",
+			Help: "Reports the SHA-256 fingerprint of the public key of the active bound service account token signing key. The value is always 1.",
+		}, []string{"fingerprint"}),
+		pendingPromotion: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "openshift_kube_apiserver_operator_bound_sa_token_signing_next_key_pending_promotion_seconds",
+			Help: "Reports for how long the next bound service account token signing key has been waiting to be promoted. The value is 0 if no key is pending.",
+		}),
+	}
+}
+
+func (m *signingKeyMetrics) Create(version *semver.Version) bool {
+	return true
+}
+
+// Describe reports the metadata for metrics to the prometheus collector.
+func (m *signingKeyMetrics) Describe(ch chan<- *prometheus.Desc) {
+	ch <- m.publicKeys.Desc()
+	ch <- m.keyCreation.WithLabelValues("", "").Desc()
+	ch <- m.activeKey.WithLabelValues("").Desc()
+	ch <- m.pendingPromotion.Desc()
+}
+
+// Collect calculates metrics from the cached resources and reports them to the prometheus collector.
+func (m *signingKeyMetrics) Collect(ch chan<- prometheus.Metric) {
+	if configMap, err := m.configMapLister.ConfigMaps(targetNamespace).Get(PublicKeyConfigMapName); err == nil {
+		m.publicKeys.Set(float64(len(configMap.Data)))
+		ch <- m.publicKeys
+	}
+
+	nextSecret, err := m.operatorSecretLister.Secrets(operatorNamespace).Get(NextSigningKeySecretName)
+	if err != nil {
+		nextSecret = nil
+	}
+	operandSecret, err := m.operandSecretLister.Secrets(targetNamespace).Get(SigningKeySecretName)
+	if err != nil {
+		operandSecret = nil
+	}
+
+	if nextSecret != nil {
+		if fingerprint, err := PublicKeyFingerprint(nextSecret.Data[PublicKeyKey]); err == nil {
+			g := m.keyCreation.WithLabelValues("next", fingerprint)
+			g.Set(float64(keyCreationTime(nextSecret).Unix()))
+			ch <- g
+		}
+		m.pendingPromotion.Set(promotionPendingFor(nextSecret, operandSecret, time.Now()).Seconds())
+		ch <- m.pendingPromotion
+	}
+
+	if operandSecret != nil {
+		if fingerprint, err := PublicKeyFingerprint(operandSecret.Data[PublicKeyKey]); err == nil {
+			g := m.keyCreation.WithLabelValues("active", fingerprint)
+			g.Set(float64(keyCreationTime(operandSecret).Unix()))
+			ch <- g
+
+			info := m.activeKey.WithLabelValues(fingerprint)
+			info.Set(1)
+			ch <- info
+		}
+	}
+}
+
+func (m *signingKeyMetrics) ClearState() {}
+
+func (m *signingKeyMetrics) FQName() string {
+	return "cluster_kube_apiserver_operator_bound_sa_token_signing"
+}
+
+// promotionPendingFor returns for how long the keypair of the next signing secret has been waiting
+// to be copied to the operand secret, or zero if it has been promoted.
+func promotionPendingFor(nextSecret, operandSecret *corev1.Secret, now time.Time) time.Duration {
+	if operandSecret == nil || bytes.Equal(operandSecret.Data[PublicKeyKey], nextSecret.Data[PublicKeyKey]) {
+		return 0
+	}
+	pending := now.Sub(keyCreationTime(nextSecret))
+	if pending < 0 {
+		return 0
+	}
+	return pending
+}
@@ -0,0 +1,145 @@
+package boundsatokensignercontroller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+func newSigningSecret(t *testing.T, namespace, name string, createdAt time.Time) *corev1.Secret {
+	publicKey, privateKey, err := GenerateKeyPairPEM(KeyAlgorithmECDSAP256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   namespace,
+			Name:        name,
+			Annotations: map[string]string{KeyCreatedAtAnnotation: createdAt.UTC().Format(time.RFC3339)},
+		},
+		Data: map[string][]byte{PublicKeyKey: publicKey, PrivateKeyKey: privateKey},
+	}
+}
+
+func TestSigningKeyMetrics(t *testing.T) {
+	createdAt := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	nextSecret := newSigningSecret(t, operatorNamespace, NextSigningKeySecretName, createdAt)
+	operandSecret := nextSecret.DeepCopy()
+	operandSecret.Namespace, operandSecret.Name = targetNamespace, SigningKeySecretName
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: targetNamespace, Name: PublicKeyConfigMapName},
+		Data: map[string]string{
+			"service-account-001.pub": "old",
+			"service-account-002.pub": string(nextSecret.Data[PublicKeyKey]),
+		},
+	}
+
+	secretIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	configMapIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, obj := range []interface{}{nextSecret, operandSecret} {
+		if err := secretIndexer.Add(obj); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := configMapIndexer.Add(configMap); err != nil {
+		t.Fatal(err)
+	}
+	secretLister := corev1listers.NewSecretLister(secretIndexer)
+	m := newSigningKeyMetrics(secretLister, secretLister, corev1listers.NewConfigMapLister(configMapIndexer))
+
+	fingerprint, err := PublicKeyFingerprint(nextSecret.Data[PublicKeyKey])
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := fmt.Sprintf(`
+# HELP openshift_kube_apiserver_operator_bound_sa_token_signing_active_key_info Reports the SHA-256 fingerprint of the public key of the active bound service account token signing key. The value is always 1.
+# TYPE openshift_kube_apiserver_operator_bound_sa_token_signing_active_key_info gauge
+openshift_kube_apiserver_operator_bound_sa_token_signing_active_key_info{fingerprint="%[1]s"} 1
+# HELP openshift_kube_apiserver_operator_bound_sa_token_signing_key_creation_timestamp_seconds Reports when the bound service account token signing keys were generated. key is active for the key kube-apiserver signs tokens with and next for the key waiting to be promoted.
+# TYPE openshift_kube_apiserver_operator_bound_sa_token_signing_key_creation_timestamp_seconds gauge
+openshift_kube_apiserver_operator_bound_sa_token_signing_key_creation_timestamp_seconds{fingerprint="%[1]s",key="active"} %[2]d
+openshift_kube_apiserver_operator_bound_sa_token_signing_key_creation_timestamp_seconds{fingerprint="%[1]s",key="next"} %[2]d
+# HELP openshift_kube_apiserver_operator_bound_sa_token_signing_next_key_pending_promotion_seconds Reports for how long the next bound service account token signing key has been waiting to be promoted. The value is 0 if no key is pending.
+# TYPE openshift_kube_apiserver_operator_bound_sa_token_signing_next_key_pending_promotion_seconds gauge
+openshift_kube_apiserver_operator_bound_sa_token_signing_next_key_pending_promotion_seconds 0
+# HELP openshift_kube_apiserver_operator_bound_sa_token_signing_public_keys Reports the number of public keys used to verify bound service account tokens.
+# TYPE openshift_kube_apiserver_operator_bound_sa_token_signing_public_keys gauge
+openshift_kube_apiserver_operator_bound_sa_token_signing_public_keys 2
+`, fingerprint, createdAt.Unix())
+	if err := testutil.CollectAndCompare(m, strings.NewReader(expected)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestUpdatePromotionCondition(t *testing.T) {
+	tests := []struct {
+		name           string
+		pendingSince   time.Duration
+		promoted       bool
+		expectedStatus operatorv1.ConditionStatus
+	}{
+		{
+			name:           "promoted",
+			pendingSince:   48 * time.Hour,
+			promoted:       true,
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+		{
+			name:           "pending within the timeout",
+			pendingSince:   time.Hour,
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+		{
+			name:           "pending for too long",
+			pendingSince:   48 * time.Hour,
+			expectedStatus: operatorv1.ConditionTrue,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			nextSecret := newSigningSecret(t, operatorNamespace, NextSigningKeySecretName, time.Now().Add(-tc.pendingSince))
+			operandSecret := newSigningSecret(t, targetNamespace, SigningKeySecretName, time.Now().Add(-100*time.Hour))
+			if tc.promoted {
+				operandSecret.Data = nextSecret.Data
+			}
+			kubeClient := fake.NewSimpleClientset(nextSecret, operandSecret)
+			operatorClient := v1helpers.NewFakeStaticPodOperatorClient(&operatorv1.StaticPodOperatorSpec{}, &operatorv1.StaticPodOperatorStatus{}, nil, nil)
+
+			c := &BoundSATokenSignerController{
+				operatorClient:  operatorClient,
+				secretClient:    kubeClient.CoreV1(),
+				configMapClient: kubeClient.CoreV1(),
+			}
+			syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))
+			if err := c.updatePromotionCondition(context.TODO(), syncCtx); err != nil {
+				t.Fatal(err)
+			}
+
+			_, status, _, err := operatorClient.GetStaticPodOperatorState()
+			if err != nil {
+				t.Fatal(err)
+			}
+			condition := v1helpers.FindOperatorCondition(status.Conditions, PromotionDegradedConditionType)
+			if condition == nil {
+				t.Fatalf("expected condition %s to be set", PromotionDegradedConditionType)
+			}
+			if condition.Status != tc.expectedStatus {
+				t.Errorf("expected status %s, got %s: %s", tc.expectedStatus, condition.Status, condition.Message)
+			}
+		})
+	}
+}
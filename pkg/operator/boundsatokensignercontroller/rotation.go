@@ -0,0 +1,288 @@
+package boundsatokensignercontroller
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/operatorclient"
+)
+
+const (
+	// defaultMaxTokenLifetime matches the lifetime kube-apiserver extends projected tokens to.
+	defaultMaxTokenLifetime = 365 * 24 * time.Hour
+	// defaultPromotionTimeout leaves room for several revision rollouts to distribute the public key.
+	defaultPromotionTimeout = 6 * time.Hour
+)
+
+// signingConfig is read from the optional SigningConfigConfigMapName configmap.
+type signingConfig struct {
+	keyAlgorithm KeyAlgorithm
+	// rotationInterval is zero when scheduled rotation is disabled.
+	rotationInterval time.Duration
+	maxTokenLifetime time.Duration
+	promotionTimeout time.Duration
+}
+
+// signingConfig returns the admin provided configuration for signing keys, falling back to defaults.
+func (c *BoundSATokenSignerController) signingConfig(ctx context.Context) (*signingConfig, error) {
+	configMap, err := c.configMapClient.ConfigMaps(operatorNamespace).Get(ctx, SigningConfigConfigMapName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return parseSigningConfig(nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return parseSigningConfig(configMap.Data)
+}
+
+func parseSigningConfig(data map[string]string) (*signingConfig, error) {
+	config := &signingConfig{
+		maxTokenLifetime: defaultMaxTokenLifetime,
+		promotionTimeout: defaultPromotionTimeout,
+	}
+
+	var err error
+	config.keyAlgorithm, err = ParseKeyAlgorithm(data[KeyAlgorithmConfigKey])
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s in configmap %s/%s: %v", KeyAlgorithmConfigKey, operatorNamespace, SigningConfigConfigMapName, err)
+	}
+
+	for key, into := range map[string]*time.Duration{
+		RotationIntervalConfigKey: &config.rotationInterval,
+		MaxTokenLifetimeConfigKey: &config.maxTokenLifetime,
+		PromotionTimeoutConfigKey: &config.promotionTimeout,
+	} {
+		value := data[key]
+		if len(value) == 0 {
+			continue
+		}
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s in configmap %s/%s: %v", key, operatorNamespace, SigningConfigConfigMapName, err)
+		}
+		if duration <= 0 {
+			return nil, fmt.Errorf("invalid %s in configmap %s/%s: must be positive", key, operatorNamespace, SigningConfigConfigMapName)
+		}
+		*into = duration
+	}
+
+	return config, nil
+}
+
+// keyCreationTime returns when the keypair of the given secret was generated. Secrets created
+// before the creation was recorded in an annotation fall back to the secret creation timestamp.
+func keyCreationTime(secret *corev1.Secret) time.Time {
+	if createdAt, err := time.Parse(time.RFC3339, secret.Annotations[KeyCreatedAtAnnotation]); err == nil {
+		return createdAt
+	}
+	return secret.CreationTimestamp.Time
+}
+
+// rotationDue indicates whether the keypair in the given next signing secret should be replaced. A
+// keypair is only rotated after it has been promoted to the operand namespace, so a pending promotion
+// is never skipped.
+func (c *BoundSATokenSignerController) rotationDue(ctx context.Context, nextSecret *corev1.Secret, config *signingConfig) (bool, error) {
+	if config.rotationInterval == 0 {
+		return false, nil
+	}
+	if time.Since(keyCreationTime(nextSecret)) < config.rotationInterval {
+		return false, nil
+	}
+
+	operandSecret, err := c.secretClient.Secrets(targetNamespace).Get(ctx, SigningKeySecretName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if !bytes.Equal(operandSecret.Data[PublicKeyKey], nextSecret.Data[PublicKeyKey]) {
+		klog.V(4).Info("Rotation of the bound service account token signing key is pending promotion of the current key.")
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// pruneRetiredPublicKeys removes public keys from the operand configmap once no token signed by them
+// can still be valid. A public key is considered retired from the moment the apiservers on all nodes
+// sign with a different key, which is recorded in an annotation on the configmap. Keys are pruned
+// only when scheduled rotation is enabled.
+func (c *BoundSATokenSignerController) pruneRetiredPublicKeys(ctx context.Context, syncCtx factory.SyncContext) error {
+	config, err := c.signingConfig(ctx)
+	if err != nil {
+		return err
+	}
+	if config.rotationInterval == 0 {
+		return nil
+	}
+
+	operatorSecret, err := c.secretClient.Secrets(operatorNamespace).Get(ctx, NextSigningKeySecretName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	operandSecret, err := c.secretClient.Secrets(targetNamespace).Get(ctx, SigningKeySecretName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// Until the active key is used on all nodes, older keys may still be signing tokens.
+	rolledOut, err := c.signingKeyRolledOutToAllNodes(ctx, string(operandSecret.Data[PublicKeyKey]))
+	if err != nil || !rolledOut {
+		return err
+	}
+
+	cachedConfigMap, err := c.configMapClient.ConfigMaps(targetNamespace).Get(ctx, PublicKeyConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	configMap, pruned, modified := prunePublicKeys(cachedConfigMap.DeepCopy(), [][]byte{
+		operatorSecret.Data[PublicKeyKey],
+		operandSecret.Data[PublicKeyKey],
+	}, config.maxTokenLifetime, time.Now())
+	if !modified {
+		return nil
+	}
+	if len(pruned) > 0 {
+		syncCtx.Recorder().Eventf("BoundSATokenSigningKeysPruned", "Removed public keys %s that have not signed bound service account tokens for more than %v", strings.Join(pruned, ", "), config.maxTokenLifetime)
+	}
+
+	_, _, err = resourceapply.ApplyConfigMap(ctx, c.configMapClient, syncCtx.Recorder(), configMap)
+	return err
+}
+
+// prunePublicKeys marks public keys of the given configmap that are not in use as retired at now and
+// removes the ones that have been retired for longer than maxTokenLifetime. It returns the names of the
+// removed keys and whether the configmap was modified.
+func prunePublicKeys(configMap *corev1.ConfigMap, inUse [][]byte, maxTokenLifetime time.Duration, now time.Time) (*corev1.ConfigMap, []string, bool) {
+	if configMap.Annotations == nil {
+		configMap.Annotations = map[string]string{}
+	}
+
+	modified := false
+	pruned := []string{}
+	for key, value := range configMap.Data {
+		annotation := KeyRetiredAtAnnotationPrefix + key
+		retiredAt, retired := configMap.Annotations[annotation]
+
+		used := false
+		for _, publicKey := range inUse {
+			if len(publicKey) > 0 && value == string(publicKey) {
+				used = true
+				break
+			}
+		}
+		switch {
+		case used:
+			if retired {
+				// the "-" suffix makes resourceapply remove the annotation
+				delete(configMap.Annotations, annotation)
+				configMap.Annotations[annotation+"-"] = ""
+				modified = true
+			}
+		case !retired:
+			configMap.Annotations[annotation] = now.UTC().Format(time.RFC3339)
+			modified = true
+		default:
+			retiredAtTime, err := time.Parse(time.RFC3339, retiredAt)
+			if err != nil {
+				klog.Warningf("Resetting invalid %s annotation %q on configmap %s/%s", annotation, retiredAt, configMap.Namespace, configMap.Name)
+				configMap.Annotations[annotation] = now.UTC().Format(time.RFC3339)
+				modified = true
+				continue
+			}
+			if now.Sub(retiredAtTime) <= maxTokenLifetime {
+				continue
+			}
+			delete(configMap.Data, key)
+			delete(configMap.Annotations, annotation)
+			configMap.Annotations[annotation+"-"] = ""
+			pruned = append(pruned, key)
+			modified = true
+		}
+	}
+	sort.Strings(pruned)
+
+	return configMap, pruned, modified
+}
+
+// ensureSigningKeyAnnotation records the public key of the operand signing secret in the
+// SigningKeyAnnotation of the public key configmap. The annotation is only set after promotion,
+// so a revision created from the configmap never claims a keypair its installer did not copy.
+func (c *BoundSATokenSignerController) ensureSigningKeyAnnotation(ctx context.Context, syncCtx factory.SyncContext) error {
+	operandSecret, err := c.secretClient.Secrets(targetNamespace).Get(ctx, SigningKeySecretName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	cachedConfigMap, err := c.configMapClient.ConfigMaps(targetNamespace).Get(ctx, PublicKeyConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	signingKey := ""
+	for key, value := range cachedConfigMap.Data {
+		if len(operandSecret.Data[PublicKeyKey]) > 0 && value == string(operandSecret.Data[PublicKeyKey]) {
+			signingKey = key
+			break
+		}
+	}
+	if len(signingKey) == 0 || cachedConfigMap.Annotations[SigningKeyAnnotation] == signingKey {
+		return nil
+	}
+
+	configMap := cachedConfigMap.DeepCopy()
+	if configMap.Annotations == nil {
+		configMap.Annotations = map[string]string{}
+	}
+	configMap.Annotations[SigningKeyAnnotation] = signingKey
+	_, _, err = resourceapply.ApplyConfigMap(ctx, c.configMapClient, syncCtx.Recorder(), configMap)
+	return err
+}
+
+// signingKeyRolledOutToAllNodes indicates whether the apiservers on all nodes sign tokens with the
+// keypair of the given public key. The signing key secret is not revisioned and the apiservers only
+// read it on start, so this checks the SigningKeyAnnotation the public key configmaps of their
+// current revisions were created with.
+func (c *BoundSATokenSignerController) signingKeyRolledOutToAllNodes(ctx context.Context, publicKey string) (bool, error) {
+	uniqueRevisions, err := operatorclient.CurrentRevisions(c.operatorClient)
+	if err != nil {
+		return false, err
+	}
+
+	for _, revision := range uniqueRevisions {
+		configMapNameWithRevision := fmt.Sprintf("%s-%d", PublicKeyConfigMapName, revision)
+		configMap, err := c.configMapClient.ConfigMaps(targetNamespace).Get(ctx, configMapNameWithRevision, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		signingKey := configMap.Annotations[SigningKeyAnnotation]
+		if len(signingKey) == 0 || configMap.Data[signingKey] != publicKey {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
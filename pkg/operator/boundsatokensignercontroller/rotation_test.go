@@ -0,0 +1,274 @@
+package boundsatokensignercontroller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+func TestParseSigningConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      map[string]string
+		expected  *signingConfig
+		expectErr bool
+	}{
+		{
+			name:     "defaults",
+			expected: &signingConfig{keyAlgorithm: DefaultKeyAlgorithm, maxTokenLifetime: defaultMaxTokenLifetime, promotionTimeout: defaultPromotionTimeout},
+		},
+		{
+			name: "rotation enabled",
+			data: map[string]string{
+				KeyAlgorithmConfigKey:     "ECDSA-P256",
+				RotationIntervalConfigKey: "2160h",
+				MaxTokenLifetimeConfigKey: "48h",
+				PromotionTimeoutConfigKey: "1h",
+			},
+			expected: &signingConfig{keyAlgorithm: KeyAlgorithmECDSAP256, rotationInterval: 2160 * time.Hour, maxTokenLifetime: 48 * time.Hour, promotionTimeout: time.Hour},
+		},
+		{
+			name:      "invalid interval",
+			data:      map[string]string{RotationIntervalConfigKey: "yearly"},
+			expectErr: true,
+		},
+		{
+			name:      "negative lifetime",
+			data:      map[string]string{MaxTokenLifetimeConfigKey: "-1h"},
+			expectErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := parseSigningConfig(tc.data)
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(actual, tc.expected) {
+				t.Errorf("expected %#v, got %#v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestPrunePublicKeys(t *testing.T) {
+	now := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	retiredAnnotation := func(key string) string { return KeyRetiredAtAnnotationPrefix + key }
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				retiredAnnotation("service-account-001.pub"): now.Add(-49 * time.Hour).Format(time.RFC3339),
+				retiredAnnotation("service-account-002.pub"): now.Add(-time.Hour).Format(time.RFC3339),
+				retiredAnnotation("service-account-004.pub"): now.Add(-100 * time.Hour).Format(time.RFC3339),
+			},
+		},
+		Data: map[string]string{
+			"service-account-001.pub": "expired",
+			"service-account-002.pub": "recently-retired",
+			"service-account-003.pub": "newly-retired",
+			"service-account-004.pub": "active",
+			"service-account-005.pub": "next",
+		},
+	}
+
+	actual, pruned, modified := prunePublicKeys(configMap, [][]byte{[]byte("next"), []byte("active")}, 48*time.Hour, now)
+	if !modified {
+		t.Fatal("expected the configmap to be modified")
+	}
+	if expected := []string{"service-account-001.pub"}; !reflect.DeepEqual(pruned, expected) {
+		t.Errorf("expected %v to be pruned, got %v", expected, pruned)
+	}
+
+	expectedData := map[string]string{
+		"service-account-002.pub": "recently-retired",
+		"service-account-003.pub": "newly-retired",
+		"service-account-004.pub": "active",
+		"service-account-005.pub": "next",
+	}
+	if !reflect.DeepEqual(actual.Data, expectedData) {
+		t.Errorf("expected data %v, got %v", expectedData, actual.Data)
+	}
+	expectedAnnotations := map[string]string{
+		retiredAnnotation("service-account-001.pub") + "-": "",
+		retiredAnnotation("service-account-002.pub"):       now.Add(-time.Hour).Format(time.RFC3339),
+		retiredAnnotation("service-account-003.pub"):       now.Format(time.RFC3339),
+		retiredAnnotation("service-account-004.pub") + "-": "",
+	}
+	if !reflect.DeepEqual(actual.Annotations, expectedAnnotations) {
+		t.Errorf("expected annotations %v, got %v", expectedAnnotations, actual.Annotations)
+	}
+
+	_, _, modified = prunePublicKeys(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{retiredAnnotation("service-account-002.pub"): now.Format(time.RFC3339)}},
+		Data:       map[string]string{"service-account-001.pub": "active", "service-account-002.pub": "retired"},
+	}, [][]byte{[]byte("active")}, 48*time.Hour, now)
+	if modified {
+		t.Errorf("expected an up to date configmap not to be modified")
+	}
+}
+
+func TestRotationDue(t *testing.T) {
+	nextSecret := func(publicKey string, createdAt time.Time) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   operatorNamespace,
+				Name:        NextSigningKeySecretName,
+				Annotations: map[string]string{KeyCreatedAtAnnotation: createdAt.UTC().Format(time.RFC3339)},
+			},
+			Data: map[string][]byte{PublicKeyKey: []byte(publicKey)},
+		}
+	}
+	operandSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: targetNamespace, Name: SigningKeySecretName},
+		Data:       map[string][]byte{PublicKeyKey: []byte("promoted")},
+	}
+	rotationEnabled := &signingConfig{rotationInterval: 24 * time.Hour}
+
+	tests := []struct {
+		name     string
+		secret   *corev1.Secret
+		config   *signingConfig
+		expected bool
+	}{
+		{
+			name:   "rotation disabled",
+			secret: nextSecret("promoted", time.Now().Add(-48*time.Hour)),
+			config: &signingConfig{},
+		},
+		{
+			name:   "key too young",
+			secret: nextSecret("promoted", time.Now().Add(-time.Hour)),
+			config: rotationEnabled,
+		},
+		{
+			name:   "key not promoted yet",
+			secret: nextSecret("pending", time.Now().Add(-48*time.Hour)),
+			config: rotationEnabled,
+		},
+		{
+			name:     "key promoted and old enough",
+			secret:   nextSecret("promoted", time.Now().Add(-48*time.Hour)),
+			config:   rotationEnabled,
+			expected: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &BoundSATokenSignerController{secretClient: fake.NewSimpleClientset(operandSecret).CoreV1()}
+			actual, err := c.rotationDue(context.TODO(), tc.secret, tc.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestPruneRetiredPublicKeys(t *testing.T) {
+	retiredAt := time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339)
+	publicKeys := map[string]string{"service-account-001.pub": "old", "service-account-002.pub": "new"}
+	// revisionedConfigMap is the public key configmap of a revision created when the given key was
+	// signing, the signing key secret itself is not revisioned.
+	revisionedConfigMap := func(revision int, signingKey string) *corev1.ConfigMap {
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: targetNamespace, Name: fmt.Sprintf("%s-%d", PublicKeyConfigMapName, revision)},
+			Data:       publicKeys,
+		}
+		if len(signingKey) > 0 {
+			configMap.Annotations = map[string]string{SigningKeyAnnotation: signingKey}
+		}
+		return configMap
+	}
+
+	tests := []struct {
+		name           string
+		revisions      []runtime.Object
+		expectedPruned bool
+	}{
+		{
+			name:      "revision created before promotion",
+			revisions: []runtime.Object{revisionedConfigMap(3, ""), revisionedConfigMap(4, "service-account-002.pub")},
+		},
+		{
+			name:      "revision signing with the old key",
+			revisions: []runtime.Object{revisionedConfigMap(3, "service-account-001.pub"), revisionedConfigMap(4, "service-account-002.pub")},
+		},
+		{
+			name:      "revision missing",
+			revisions: []runtime.Object{revisionedConfigMap(4, "service-account-002.pub")},
+		},
+		{
+			name:           "all revisions signing with the new key",
+			revisions:      []runtime.Object{revisionedConfigMap(3, "service-account-002.pub"), revisionedConfigMap(4, "service-account-002.pub")},
+			expectedPruned: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			objects := append([]runtime.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: operatorNamespace, Name: SigningConfigConfigMapName},
+					Data:       map[string]string{RotationIntervalConfigKey: "24h", MaxTokenLifetimeConfigKey: "1h"},
+				},
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: operatorNamespace, Name: NextSigningKeySecretName},
+					Data:       map[string][]byte{PublicKeyKey: []byte("new")},
+				},
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: targetNamespace, Name: SigningKeySecretName},
+					Data:       map[string][]byte{PublicKeyKey: []byte("new")},
+				},
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace:   targetNamespace,
+						Name:        PublicKeyConfigMapName,
+						Annotations: map[string]string{KeyRetiredAtAnnotationPrefix + "service-account-001.pub": retiredAt},
+					},
+					Data: publicKeys,
+				},
+			}, tc.revisions...)
+			kubeClient := fake.NewSimpleClientset(objects...)
+			c := &BoundSATokenSignerController{
+				operatorClient: v1helpers.NewFakeStaticPodOperatorClient(&operatorv1.StaticPodOperatorSpec{}, &operatorv1.StaticPodOperatorStatus{
+					NodeStatuses: []operatorv1.NodeStatus{{NodeName: "a", CurrentRevision: 3}, {NodeName: "b", CurrentRevision: 4}},
+				}, nil, nil),
+				secretClient:    kubeClient.CoreV1(),
+				configMapClient: kubeClient.CoreV1(),
+			}
+			syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))
+
+			if err := c.ensureSigningKeyAnnotation(context.TODO(), syncCtx); err != nil {
+				t.Fatal(err)
+			}
+			if err := c.pruneRetiredPublicKeys(context.TODO(), syncCtx); err != nil {
+				t.Fatal(err)
+			}
+
+			configMap, err := kubeClient.CoreV1().ConfigMaps(targetNamespace).Get(context.TODO(), PublicKeyConfigMapName, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if signingKey := configMap.Annotations[SigningKeyAnnotation]; signingKey != "service-account-002.pub" {
+				t.Errorf("expected the promoted key to be annotated, got %q", signingKey)
+			}
+			if _, present := configMap.Data["service-account-001.pub"]; present == tc.expectedPruned {
+				t.Errorf("expected pruned %v, got data %v", tc.expectedPruned, configMap.Data)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package certrotationcontroller
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/operatorclient"
+)
+
+const (
+	// LoadBalancerServingConfigMapName is an optional configmap in the operator namespace that allows an admin to add DNS
+	// names and IPs to the load balancer serving certificates, e.g. for a second DNS name or a VIP in front of the API.
+	LoadBalancerServingConfigMapName = "loadbalancer-serving-config"
+	// AdditionalExternalHostnamesConfigKey is a comma separated list of DNS names and IPs added to the external load
+	// balancer serving certificate.
+	AdditionalExternalHostnamesConfigKey = "additionalExternalHostnames"
+	// AdditionalInternalHostnamesConfigKey is a comma separated list of DNS names and IPs added to the internal load
+	// balancer serving certificate.
+	AdditionalInternalHostnamesConfigKey = "additionalInternalHostnames"
+)
+
+// additionalHostnames returns the valid DNS names and IPs configured under the given key of the load balancer serving
+// config. Invalid entries are skipped, the LoadBalancerServingConfigController reports them.
+func (c *CertRotationController) additionalHostnames(key string) ([]string, error) {
+	configMap, err := c.configMapLister.ConfigMaps(operatorclient.OperatorNamespace).Get(LoadBalancerServingConfigMapName)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	hostnames, err := parseHostnames(configMap.Data[key], key)
+	if err != nil {
+		klog.Warningf("Skipping invalid load balancer hostnames: %v", err)
+	}
+	return hostnames, nil
+}
+
+// parseHostnames returns the valid DNS names and IPs of the given comma separated list and an error for the invalid ones.
+func parseHostnames(value, key string) ([]string, error) {
+	hostnames := []string{}
+	var errs []error
+	for _, hostname := range strings.Split(value, ",") {
+		hostname = strings.TrimSpace(hostname)
+		if len(hostname) == 0 {
+			continue
+		}
+		if net.ParseIP(hostname) == nil {
+			if validationErrs := validation.IsDNS1123Subdomain(strings.TrimPrefix(hostname, "*.")); len(validationErrs) > 0 {
+				errs = append(errs, fmt.Errorf("invalid %s in configmap %s/%s: %q is neither an IP nor a DNS name: %s",
+					key, operatorclient.OperatorNamespace, LoadBalancerServingConfigMapName, hostname, strings.Join(validationErrs, ", ")))
+				continue
+			}
+		}
+		hostnames = append(hostnames, hostname)
+	}
+	return hostnames, utilerrors.NewAggregate(errs)
+}
+
+// loadBalancerServingConfigEventHandler queues the given work queue for changes of the load balancer serving config.
+func loadBalancerServingConfigEventHandler(queue workqueue.Interface) cache.ResourceEventHandler {
+	return cache.FilteringResourceEventHandler{
+		FilterFunc: func(obj interface{}) bool {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			configMap, ok := obj.(*corev1.ConfigMap)
+			return ok && configMap.Name == LoadBalancerServingConfigMapName
+		},
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { queue.Add(workQueueKey) },
+			UpdateFunc: func(old, new interface{}) { queue.Add(workQueueKey) },
+			DeleteFunc: func(obj interface{}) { queue.Add(workQueueKey) },
+		},
+	}
+}
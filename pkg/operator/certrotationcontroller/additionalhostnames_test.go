@@ -0,0 +1,165 @@
+package certrotationcontroller
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	configlisterv1 "github.com/openshift/client-go/config/listers/config/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/operatorclient"
+)
+
+func TestSyncLoadBalancerHostnames(t *testing.T) {
+	testCases := []struct {
+		name             string
+		data             map[string]string
+		expectedExternal []string
+		expectedInternal []string
+	}{
+		{
+			name:             "NoConfig",
+			expectedExternal: []string{"api.example.com"},
+			expectedInternal: []string{"api-int.example.com"},
+		},
+		{
+			name: "AdditionalHostnames",
+			data: map[string]string{
+				AdditionalExternalHostnamesConfigKey: "api.example.org, 192.0.2.10,*.apps.example.org,",
+				AdditionalInternalHostnamesConfigKey: "api-int.example.org,fd00::10",
+			},
+			expectedExternal: []string{"api.example.com", "api.example.org", "192.0.2.10", "*.apps.example.org"},
+			expectedInternal: []string{"api-int.example.com", "api-int.example.org", "fd00::10"},
+		},
+		{
+			name:             "OnlyExternal",
+			data:             map[string]string{AdditionalExternalHostnamesConfigKey: "api.example.org"},
+			expectedExternal: []string{"api.example.com", "api.example.org"},
+			expectedInternal: []string{"api-int.example.com"},
+		},
+		{
+			name: "Invalid",
+			data: map[string]string{
+				AdditionalExternalHostnamesConfigKey: "api.example.org,Not_A_Hostname",
+				AdditionalInternalHostnamesConfigKey: "api-int_example.org",
+			},
+			expectedExternal: []string{"api.example.com", "api.example.org"},
+			expectedInternal: []string{"api-int.example.com"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			infrastructureIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if err := infrastructureIndexer.Add(&configv1.Infrastructure{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Status: configv1.InfrastructureStatus{
+					APIServerURL:         "https://api.example.com:6443",
+					APIServerInternalURL: "https://api-int.example.com:6443",
+				},
+			}); err != nil {
+				t.Fatal(err)
+			}
+			configMapIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			if tc.data != nil {
+				if err := configMapIndexer.Add(&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: LoadBalancerServingConfigMapName},
+					Data:       tc.data,
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			c := &CertRotationController{
+				infrastructureLister: configlisterv1.NewInfrastructureLister(infrastructureIndexer),
+				configMapLister:      corev1listers.NewConfigMapLister(configMapIndexer),
+				externalLoadBalancer: &DynamicServingRotation{hostnamesChanged: make(chan struct{}, 10)},
+				internalLoadBalancer: &DynamicServingRotation{hostnamesChanged: make(chan struct{}, 10)},
+			}
+			if err := c.syncExternalLoadBalancerHostnames(); err != nil {
+				t.Fatal(err)
+			}
+			if err := c.syncInternalLoadBalancerHostnames(); err != nil {
+				t.Fatal(err)
+			}
+			if actual := c.externalLoadBalancer.GetHostnames(); !reflect.DeepEqual(actual, tc.expectedExternal) {
+				t.Errorf("expected external hostnames %v, got %v", tc.expectedExternal, actual)
+			}
+			if actual := c.internalLoadBalancer.GetHostnames(); !reflect.DeepEqual(actual, tc.expectedInternal) {
+				t.Errorf("expected internal hostnames %v, got %v", tc.expectedInternal, actual)
+			}
+		})
+	}
+}
+
+func TestLoadBalancerServingConfigController(t *testing.T) {
+	testCases := []struct {
+		name            string
+		data            map[string]string
+		expectedStatus  operatorv1.ConditionStatus
+		expectedMessage string
+	}{
+		{
+			name:           "NoConfig",
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+		{
+			name:           "Valid",
+			data:           map[string]string{AdditionalExternalHostnamesConfigKey: "api.example.org,192.0.2.10"},
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+		{
+			name:            "Invalid",
+			data:            map[string]string{AdditionalInternalHostnamesConfigKey: "api-int.example.org,Not_A_Hostname"},
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedMessage: `invalid additionalInternalHostnames in configmap openshift-kube-apiserver-operator/loadbalancer-serving-config: "Not_A_Hostname" is neither an IP nor a DNS name`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			if tc.data != nil {
+				if err := indexer.Add(&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: LoadBalancerServingConfigMapName},
+					Data:       tc.data,
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			operatorClient := v1helpers.NewFakeStaticPodOperatorClient(&operatorv1.StaticPodOperatorSpec{}, &operatorv1.StaticPodOperatorStatus{}, nil, nil)
+			c := &loadBalancerServingConfigController{
+				operatorClient:  operatorClient,
+				configMapLister: corev1listers.NewConfigMapLister(indexer),
+			}
+
+			if err := c.sync(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))); err != nil {
+				t.Fatal(err)
+			}
+
+			_, status, _, err := operatorClient.GetStaticPodOperatorState()
+			if err != nil {
+				t.Fatal(err)
+			}
+			condition := v1helpers.FindOperatorCondition(status.Conditions, LoadBalancerServingConfigDegradedConditionType)
+			if condition == nil {
+				t.Fatalf("expected condition %s to be set", LoadBalancerServingConfigDegradedConditionType)
+			}
+			if condition.Status != tc.expectedStatus {
+				t.Errorf("expected status %s, got %s: %s", tc.expectedStatus, condition.Status, condition.Message)
+			}
+			if !strings.Contains(condition.Message, tc.expectedMessage) {
+				t.Errorf("expected message containing %q, got %q", tc.expectedMessage, condition.Message)
+			}
+		})
+	}
+}
@@ -9,13 +9,16 @@ import (
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
+	corev1listers "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 
 	configinformers "github.com/openshift/client-go/config/informers/externalversions"
 	configlisterv1 "github.com/openshift/client-go/config/listers/config/v1"
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/externalsigner"
 	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/operatorclient"
 	"github.com/openshift/library-go/pkg/controller/factory"
 
@@ -29,9 +32,15 @@ const defaultRotationDay = 24 * time.Hour
 
 type CertRotationController struct {
 	certRotators []factory.Controller
+	inventory    []ManagedCertificate
+
+	// externalSigner signs the target certificates instead of the in-cluster signers if set.
+	externalSigner externalsigner.Signer
 
 	networkLister        configlisterv1.NetworkLister
 	infrastructureLister configlisterv1.InfrastructureLister
+	dnsLister            cache.GenericLister
+	configMapLister      corev1listers.ConfigMapLister
 
 	serviceNetwork        *DynamicServingRotation
 	serviceHostnamesQueue workqueue.RateLimitingInterface
@@ -51,17 +60,21 @@ func NewCertRotationController(
 	kubeClient kubernetes.Interface,
 	operatorClient v1helpers.StaticPodOperatorClient,
 	configInformer configinformers.SharedInformerFactory,
+	dnsInformer informers.GenericInformer,
 	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
 	eventRecorder events.Recorder,
-	day time.Duration,
+	config RotationConfig,
+	externalSigner externalsigner.Signer,
 ) (*CertRotationController, error) {
 	return newCertRotationController(
 		kubeClient,
 		operatorClient,
 		configInformer,
+		dnsInformer,
 		kubeInformersForNamespaces,
 		eventRecorder,
-		day,
+		config,
+		externalSigner,
 		false,
 	)
 }
@@ -70,17 +83,21 @@ func NewCertRotationControllerOnlyWhenExpired(
 	kubeClient kubernetes.Interface,
 	operatorClient v1helpers.StaticPodOperatorClient,
 	configInformer configinformers.SharedInformerFactory,
+	dnsInformer informers.GenericInformer,
 	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
 	eventRecorder events.Recorder,
-	day time.Duration,
+	config RotationConfig,
 ) (*CertRotationController, error) {
 	return newCertRotationController(
 		kubeClient,
 		operatorClient,
 		configInformer,
+		dnsInformer,
 		kubeInformersForNamespaces,
 		eventRecorder,
-		day,
+		config,
+		// recovery must not depend on reaching the external signer, the in-cluster signers are still trusted
+		nil,
 		true,
 	)
 }
@@ -89,14 +106,20 @@ func newCertRotationController(
 	kubeClient kubernetes.Interface,
 	operatorClient v1helpers.StaticPodOperatorClient,
 	configInformer configinformers.SharedInformerFactory,
+	dnsInformer informers.GenericInformer,
 	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
 	eventRecorder events.Recorder,
-	day time.Duration,
+	config RotationConfig,
+	externalSigner externalsigner.Signer,
 	refreshOnlyWhenExpired bool,
 ) (*CertRotationController, error) {
 	ret := &CertRotationController{
+		externalSigner: externalSigner,
+
 		networkLister:        configInformer.Config().V1().Networks().Lister(),
 		infrastructureLister: configInformer.Config().V1().Infrastructures().Lister(),
+		dnsLister:            dnsInformer.Lister(),
+		configMapLister:      kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().ConfigMaps().Lister(),
 
 		serviceHostnamesQueue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "ServiceHostnames"),
 		serviceNetwork:        &DynamicServingRotation{hostnamesChanged: make(chan struct{}, 10)},
@@ -111,30 +134,27 @@ func newCertRotationController(
 		cachesToSync: []cache.InformerSynced{
 			configInformer.Config().V1().Networks().Informer().HasSynced,
 			configInformer.Config().V1().Infrastructures().Informer().HasSynced,
+			dnsInformer.Informer().HasSynced,
+			kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().ConfigMaps().Informer().HasSynced,
 		},
 	}
 
 	configInformer.Config().V1().Networks().Informer().AddEventHandler(ret.serviceHostnameEventHandler())
+	dnsInformer.Informer().AddEventHandler(ret.serviceHostnameEventHandler())
 	configInformer.Config().V1().Infrastructures().Informer().AddEventHandler(ret.externalLoadBalancerHostnameEventHandler())
+	configInformer.Config().V1().Infrastructures().Informer().AddEventHandler(ret.internalLoadBalancerHostnameEventHandler())
+	operatorConfigMapInformer := kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().ConfigMaps().Informer()
+	operatorConfigMapInformer.AddEventHandler(loadBalancerServingConfigEventHandler(ret.externalLoadBalancerHostnamesQueue))
+	operatorConfigMapInformer.AddEventHandler(loadBalancerServingConfigEventHandler(ret.internalLoadBalancerHostnamesQueue))
 
-	rotationDay := defaultRotationDay
-	if day != time.Duration(0) {
-		rotationDay = day
-		klog.Warningf("!!! UNSUPPORTED VALUE SET !!!")
-		klog.Warningf("Certificate rotation base set to %q", rotationDay)
-	} else {
-		// for the development cycle, make the rotation 60 times faster (every twelve hours or so).
-		// This must be reverted before we ship
-		rotationDay = rotationDay / 60
-	}
-
-	certRotator := certrotation.NewCertRotationController(
+	ret.addCertRotator(
 		"AggregatorProxyClientCert",
+		certConsumers{caBundle: "aggregated apiservers", target: "kube-apiserver"},
 		certrotation.RotatedSigningCASecret{
 			Namespace:              operatorclient.OperatorNamespace,
 			Name:                   "aggregator-client-signer",
-			Validity:               30 * rotationDay,
-			Refresh:                15 * rotationDay,
+			Validity:               config[AggregatorClientSignerCategory].Validity,
+			Refresh:                config[AggregatorClientSignerCategory].Refresh,
 			RefreshOnlyWhenExpired: refreshOnlyWhenExpired,
 			Informer:               kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().Secrets(),
 			Lister:                 kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().Secrets().Lister(),
@@ -152,8 +172,8 @@ func newCertRotationController(
 		certrotation.RotatedSelfSignedCertKeySecret{
 			Namespace:              operatorclient.TargetNamespace,
 			Name:                   "aggregator-client",
-			Validity:               30 * rotationDay,
-			Refresh:                15 * rotationDay,
+			Validity:               config[ClientCertCategory].Validity,
+			Refresh:                config[ClientCertCategory].Refresh,
 			RefreshOnlyWhenExpired: refreshOnlyWhenExpired,
 			CertCreator: &certrotation.ClientRotation{
 				UserInfo: &user.DefaultInfo{Name: "system:openshift-aggregator"},
@@ -166,15 +186,15 @@ func newCertRotationController(
 		operatorClient,
 		eventRecorder,
 	)
-	ret.certRotators = append(ret.certRotators, certRotator)
 
-	certRotator = certrotation.NewCertRotationController(
+	ret.addCertRotator(
 		"KubeAPIServerToKubeletClientCert",
+		certConsumers{caBundle: "kubelet", target: "kube-apiserver"},
 		certrotation.RotatedSigningCASecret{
 			Namespace:              operatorclient.OperatorNamespace,
 			Name:                   "kube-apiserver-to-kubelet-signer",
-			Validity:               1 * 365 * defaultRotationDay, // this comes from the installer
-			Refresh:                8 * 365 * defaultRotationDay, // this means we effectively do not rotate
+			Validity:               config[KubeletClientSignerCategory].Validity,
+			Refresh:                config[KubeletClientSignerCategory].Refresh,
 			RefreshOnlyWhenExpired: refreshOnlyWhenExpired,
 			Informer:               kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().Secrets(),
 			Lister:                 kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().Secrets().Lister(),
@@ -192,8 +212,8 @@ func newCertRotationController(
 		certrotation.RotatedSelfSignedCertKeySecret{
 			Namespace:              operatorclient.TargetNamespace,
 			Name:                   "kubelet-client",
-			Validity:               30 * rotationDay,
-			Refresh:                15 * rotationDay,
+			Validity:               config[ClientCertCategory].Validity,
+			Refresh:                config[ClientCertCategory].Refresh,
 			RefreshOnlyWhenExpired: refreshOnlyWhenExpired,
 			CertCreator: &certrotation.ClientRotation{
 				UserInfo: &user.DefaultInfo{Name: "system:kube-apiserver", Groups: []string{"kube-master"}},
@@ -206,15 +226,15 @@ func newCertRotationController(
 		operatorClient,
 		eventRecorder,
 	)
-	ret.certRotators = append(ret.certRotators, certRotator)
 
-	certRotator = certrotation.NewCertRotationController(
+	ret.addCertRotator(
 		"LocalhostServing",
+		certConsumers{caBundle: "kube-apiserver clients", target: "kube-apiserver"},
 		certrotation.RotatedSigningCASecret{
 			Namespace:              operatorclient.OperatorNamespace,
 			Name:                   "localhost-serving-signer",
-			Validity:               10 * 365 * defaultRotationDay, // this comes from the installer
-			Refresh:                8 * 365 * defaultRotationDay,  // this means we effectively do not rotate
+			Validity:               config[ServingSignerCategory].Validity,
+			Refresh:                config[ServingSignerCategory].Refresh,
 			RefreshOnlyWhenExpired: refreshOnlyWhenExpired,
 			Informer:               kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().Secrets(),
 			Lister:                 kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().Secrets().Lister(),
@@ -232,8 +252,8 @@ func newCertRotationController(
 		certrotation.RotatedSelfSignedCertKeySecret{
 			Namespace:              operatorclient.TargetNamespace,
 			Name:                   "localhost-serving-cert-certkey",
-			Validity:               30 * rotationDay,
-			Refresh:                15 * rotationDay,
+			Validity:               config[ServingCertCategory].Validity,
+			Refresh:                config[ServingCertCategory].Refresh,
 			RefreshOnlyWhenExpired: refreshOnlyWhenExpired,
 			CertCreator: &certrotation.ServingRotation{
 				Hostnames: func() []string { return []string{"localhost", "127.0.0.1"} },
@@ -246,15 +266,15 @@ func newCertRotationController(
 		operatorClient,
 		eventRecorder,
 	)
-	ret.certRotators = append(ret.certRotators, certRotator)
 
-	certRotator = certrotation.NewCertRotationController(
+	ret.addCertRotator(
 		"ServiceNetworkServing",
+		certConsumers{caBundle: "kube-apiserver clients", target: "kube-apiserver"},
 		certrotation.RotatedSigningCASecret{
 			Namespace:              operatorclient.OperatorNamespace,
 			Name:                   "service-network-serving-signer",
-			Validity:               10 * 365 * defaultRotationDay, // this comes from the installer
-			Refresh:                8 * 365 * defaultRotationDay,  // this means we effectively do not rotate
+			Validity:               config[ServingSignerCategory].Validity,
+			Refresh:                config[ServingSignerCategory].Refresh,
 			RefreshOnlyWhenExpired: refreshOnlyWhenExpired,
 			Informer:               kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().Secrets(),
 			Lister:                 kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().Secrets().Lister(),
@@ -272,8 +292,8 @@ func newCertRotationController(
 		certrotation.RotatedSelfSignedCertKeySecret{
 			Namespace:              operatorclient.TargetNamespace,
 			Name:                   "service-network-serving-certkey",
-			Validity:               30 * rotationDay,
-			Refresh:                15 * rotationDay,
+			Validity:               config[ServingCertCategory].Validity,
+			Refresh:                config[ServingCertCategory].Refresh,
 			RefreshOnlyWhenExpired: refreshOnlyWhenExpired,
 			CertCreator: &certrotation.ServingRotation{
 				Hostnames:        ret.serviceNetwork.GetHostnames,
@@ -287,15 +307,15 @@ func newCertRotationController(
 		operatorClient,
 		eventRecorder,
 	)
-	ret.certRotators = append(ret.certRotators, certRotator)
 
-	certRotator = certrotation.NewCertRotationController(
+	ret.addCertRotator(
 		"ExternalLoadBalancerServing",
+		certConsumers{caBundle: "kube-apiserver clients", target: "kube-apiserver"},
 		certrotation.RotatedSigningCASecret{
 			Namespace:              operatorclient.OperatorNamespace,
 			Name:                   "loadbalancer-serving-signer",
-			Validity:               10 * 365 * defaultRotationDay, // this comes from the installer
-			Refresh:                8 * 365 * defaultRotationDay,  // this means we effectively do not rotate
+			Validity:               config[ServingSignerCategory].Validity,
+			Refresh:                config[ServingSignerCategory].Refresh,
 			RefreshOnlyWhenExpired: refreshOnlyWhenExpired,
 			Informer:               kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().Secrets(),
 			Lister:                 kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().Secrets().Lister(),
@@ -313,8 +333,8 @@ func newCertRotationController(
 		certrotation.RotatedSelfSignedCertKeySecret{
 			Namespace:              operatorclient.TargetNamespace,
 			Name:                   "external-loadbalancer-serving-certkey",
-			Validity:               30 * rotationDay,
-			Refresh:                15 * rotationDay,
+			Validity:               config[ServingCertCategory].Validity,
+			Refresh:                config[ServingCertCategory].Refresh,
 			RefreshOnlyWhenExpired: refreshOnlyWhenExpired,
 			CertCreator: &certrotation.ServingRotation{
 				Hostnames:        ret.externalLoadBalancer.GetHostnames,
@@ -328,15 +348,15 @@ func newCertRotationController(
 		operatorClient,
 		eventRecorder,
 	)
-	ret.certRotators = append(ret.certRotators, certRotator)
 
-	certRotator = certrotation.NewCertRotationController(
+	ret.addCertRotator(
 		"InternalLoadBalancerServing",
+		certConsumers{caBundle: "kube-apiserver clients", target: "kube-apiserver"},
 		certrotation.RotatedSigningCASecret{
 			Namespace:              operatorclient.OperatorNamespace,
 			Name:                   "loadbalancer-serving-signer",
-			Validity:               10 * 365 * defaultRotationDay, // this comes from the installer
-			Refresh:                8 * 365 * defaultRotationDay,  // this means we effectively do not rotate
+			Validity:               config[ServingSignerCategory].Validity,
+			Refresh:                config[ServingSignerCategory].Refresh,
 			RefreshOnlyWhenExpired: refreshOnlyWhenExpired,
 			Informer:               kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().Secrets(),
 			Lister:                 kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().Secrets().Lister(),
@@ -354,8 +374,8 @@ func newCertRotationController(
 		certrotation.RotatedSelfSignedCertKeySecret{
 			Namespace:              operatorclient.TargetNamespace,
 			Name:                   "internal-loadbalancer-serving-certkey",
-			Validity:               30 * rotationDay,
-			Refresh:                15 * rotationDay,
+			Validity:               config[ServingCertCategory].Validity,
+			Refresh:                config[ServingCertCategory].Refresh,
 			RefreshOnlyWhenExpired: refreshOnlyWhenExpired,
 			CertCreator: &certrotation.ServingRotation{
 				Hostnames:        ret.internalLoadBalancer.GetHostnames,
@@ -369,15 +389,15 @@ func newCertRotationController(
 		operatorClient,
 		eventRecorder,
 	)
-	ret.certRotators = append(ret.certRotators, certRotator)
 
-	certRotator = certrotation.NewCertRotationController(
+	ret.addCertRotator(
 		"LocalhostRecoveryServing",
+		certConsumers{caBundle: "kube-apiserver clients", target: "kube-apiserver"},
 		certrotation.RotatedSigningCASecret{
 			Namespace:     operatorclient.OperatorNamespace,
 			Name:          "localhost-recovery-serving-signer",
-			Validity:      10 * 365 * defaultRotationDay, // this comes from the installer
-			Refresh:       8 * 365 * defaultRotationDay,  // this means we effectively do not rotate
+			Validity:      config[ServingSignerCategory].Validity,
+			Refresh:       config[ServingSignerCategory].Refresh,
 			Informer:      kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().Secrets(),
 			Lister:        kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().Secrets().Lister(),
 			Client:        kubeClient.CoreV1(),
@@ -394,8 +414,8 @@ func newCertRotationController(
 		certrotation.RotatedSelfSignedCertKeySecret{
 			Namespace: operatorclient.TargetNamespace,
 			Name:      "localhost-recovery-serving-certkey",
-			Validity:  10 * 365 * defaultRotationDay,
-			Refresh:   8 * 365 * defaultRotationDay, // this means we effectively do not rotate
+			Validity:  config[LocalhostRecoveryServingCertCategory].Validity,
+			Refresh:   config[LocalhostRecoveryServingCertCategory].Refresh,
 			CertCreator: &certrotation.ServingRotation{
 				Hostnames: func() []string { return []string{"localhost-recovery"} },
 			},
@@ -407,15 +427,15 @@ func newCertRotationController(
 		operatorClient,
 		eventRecorder,
 	)
-	ret.certRotators = append(ret.certRotators, certRotator)
 
-	certRotator = certrotation.NewCertRotationController(
+	ret.addCertRotator(
 		"KubeControllerManagerClient",
+		certConsumers{caBundle: "kube-apiserver", target: "kube-controller-manager"},
 		certrotation.RotatedSigningCASecret{
 			Namespace:              operatorclient.OperatorNamespace,
 			Name:                   "kube-control-plane-signer",
-			Validity:               60 * defaultRotationDay,
-			Refresh:                30 * defaultRotationDay,
+			Validity:               config[ControlPlaneClientSignerCategory].Validity,
+			Refresh:                config[ControlPlaneClientSignerCategory].Refresh,
 			RefreshOnlyWhenExpired: refreshOnlyWhenExpired,
 			Informer:               kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().Secrets(),
 			Lister:                 kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().Secrets().Lister(),
@@ -433,8 +453,8 @@ func newCertRotationController(
 		certrotation.RotatedSelfSignedCertKeySecret{
 			Namespace:              operatorclient.GlobalMachineSpecifiedConfigNamespace,
 			Name:                   "kube-controller-manager-client-cert-key",
-			Validity:               30 * rotationDay,
-			Refresh:                15 * rotationDay,
+			Validity:               config[ClientCertCategory].Validity,
+			Refresh:                config[ClientCertCategory].Refresh,
 			RefreshOnlyWhenExpired: refreshOnlyWhenExpired,
 			CertCreator: &certrotation.ClientRotation{
 				UserInfo: &user.DefaultInfo{Name: "system:kube-controller-manager"},
@@ -447,15 +467,15 @@ func newCertRotationController(
 		operatorClient,
 		eventRecorder,
 	)
-	ret.certRotators = append(ret.certRotators, certRotator)
 
-	certRotator = certrotation.NewCertRotationController(
+	ret.addCertRotator(
 		"KubeSchedulerClient",
+		certConsumers{caBundle: "kube-apiserver", target: "kube-scheduler"},
 		certrotation.RotatedSigningCASecret{
 			Namespace:              operatorclient.OperatorNamespace,
 			Name:                   "kube-control-plane-signer",
-			Validity:               60 * defaultRotationDay,
-			Refresh:                30 * defaultRotationDay,
+			Validity:               config[ControlPlaneClientSignerCategory].Validity,
+			Refresh:                config[ControlPlaneClientSignerCategory].Refresh,
 			RefreshOnlyWhenExpired: refreshOnlyWhenExpired,
 			Informer:               kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().Secrets(),
 			Lister:                 kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().Secrets().Lister(),
@@ -473,8 +493,8 @@ func newCertRotationController(
 		certrotation.RotatedSelfSignedCertKeySecret{
 			Namespace:              operatorclient.GlobalMachineSpecifiedConfigNamespace,
 			Name:                   "kube-scheduler-client-cert-key",
-			Validity:               30 * rotationDay,
-			Refresh:                15 * rotationDay,
+			Validity:               config[ClientCertCategory].Validity,
+			Refresh:                config[ClientCertCategory].Refresh,
 			RefreshOnlyWhenExpired: refreshOnlyWhenExpired,
 			CertCreator: &certrotation.ClientRotation{
 				UserInfo: &user.DefaultInfo{Name: "system:kube-scheduler"},
@@ -487,15 +507,15 @@ func newCertRotationController(
 		operatorClient,
 		eventRecorder,
 	)
-	ret.certRotators = append(ret.certRotators, certRotator)
 
-	certRotator = certrotation.NewCertRotationController(
+	ret.addCertRotator(
 		"ControlPlaneNodeAdminClient",
+		certConsumers{caBundle: "kube-apiserver", target: "kube-apiserver-cert-syncer"},
 		certrotation.RotatedSigningCASecret{
 			Namespace:              operatorclient.OperatorNamespace,
 			Name:                   "kube-control-plane-signer",
-			Validity:               60 * defaultRotationDay,
-			Refresh:                30 * defaultRotationDay,
+			Validity:               config[ControlPlaneClientSignerCategory].Validity,
+			Refresh:                config[ControlPlaneClientSignerCategory].Refresh,
 			RefreshOnlyWhenExpired: refreshOnlyWhenExpired,
 			Informer:               kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().Secrets(),
 			Lister:                 kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().Secrets().Lister(),
@@ -513,8 +533,8 @@ func newCertRotationController(
 		certrotation.RotatedSelfSignedCertKeySecret{
 			Namespace:              operatorclient.TargetNamespace,
 			Name:                   "control-plane-node-admin-client-cert-key",
-			Validity:               30 * rotationDay,
-			Refresh:                15 * rotationDay,
+			Validity:               config[ClientCertCategory].Validity,
+			Refresh:                config[ClientCertCategory].Refresh,
 			RefreshOnlyWhenExpired: refreshOnlyWhenExpired,
 			CertCreator: &certrotation.ClientRotation{
 				UserInfo: &user.DefaultInfo{Name: "system:control-plane-node-admin", Groups: []string{"system:masters"}},
@@ -527,15 +547,15 @@ func newCertRotationController(
 		operatorClient,
 		eventRecorder,
 	)
-	ret.certRotators = append(ret.certRotators, certRotator)
 
-	certRotator = certrotation.NewCertRotationController(
+	ret.addCertRotator(
 		"CheckEndpointsClient",
+		certConsumers{caBundle: "kube-apiserver", target: "kube-apiserver-check-endpoints"},
 		certrotation.RotatedSigningCASecret{
 			Namespace:              operatorclient.OperatorNamespace,
 			Name:                   "kube-control-plane-signer",
-			Validity:               60 * defaultRotationDay,
-			Refresh:                30 * defaultRotationDay,
+			Validity:               config[ControlPlaneClientSignerCategory].Validity,
+			Refresh:                config[ControlPlaneClientSignerCategory].Refresh,
 			RefreshOnlyWhenExpired: refreshOnlyWhenExpired,
 			Informer:               kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().Secrets(),
 			Lister:                 kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().Secrets().Lister(),
@@ -553,8 +573,8 @@ func newCertRotationController(
 		certrotation.RotatedSelfSignedCertKeySecret{
 			Namespace:              operatorclient.TargetNamespace,
 			Name:                   "check-endpoints-client-cert-key",
-			Validity:               30 * rotationDay,
-			Refresh:                15 * rotationDay,
+			Validity:               config[ClientCertCategory].Validity,
+			Refresh:                config[ClientCertCategory].Refresh,
 			RefreshOnlyWhenExpired: refreshOnlyWhenExpired,
 			CertCreator: &certrotation.ClientRotation{
 				UserInfo: &user.DefaultInfo{Name: "system:serviceaccount:openshift-kube-apiserver:check-endpoints"},
@@ -567,15 +587,15 @@ func newCertRotationController(
 		operatorClient,
 		eventRecorder,
 	)
-	ret.certRotators = append(ret.certRotators, certRotator)
 
-	certRotator = certrotation.NewCertRotationController(
+	ret.addCertRotator(
 		"NodeSystemAdminClient",
+		certConsumers{caBundle: "kube-apiserver", target: "node-system-admin kubeconfig"},
 		certrotation.RotatedSigningCASecret{
 			Namespace:              operatorclient.OperatorNamespace,
 			Name:                   "node-system-admin-signer",
-			Validity:               1 * 365 * defaultRotationDay,
-			Refresh:                292 * defaultRotationDay,
+			Validity:               config[NodeSystemAdminSignerCategory].Validity,
+			Refresh:                config[NodeSystemAdminSignerCategory].Refresh,
 			RefreshOnlyWhenExpired: refreshOnlyWhenExpired,
 			Informer:               kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().Secrets(),
 			Lister:                 kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().Secrets().Lister(),
@@ -591,14 +611,10 @@ func newCertRotationController(
 			EventRecorder: eventRecorder,
 		},
 		certrotation.RotatedSelfSignedCertKeySecret{
-			Namespace: operatorclient.OperatorNamespace,
-			Name:      "node-system-admin-client",
-			// This needs to live longer then control plane certs so there is high chance that if a cluster breaks
-			// because of expired certs these are still valid to use for collecting data using localhost-recovery
-			// endpoint with long lived serving certs for localhost.
-			Validity: 120 * defaultRotationDay,
-			// We rotate sooner so certs are always valid for 90 days (30 days more then kube-control-plane-signer)
-			Refresh:                30 * defaultRotationDay,
+			Namespace:              operatorclient.OperatorNamespace,
+			Name:                   "node-system-admin-client",
+			Validity:               config[NodeSystemAdminClientCertCategory].Validity,
+			Refresh:                config[NodeSystemAdminClientCertCategory].Refresh,
 			RefreshOnlyWhenExpired: refreshOnlyWhenExpired,
 			CertCreator: &certrotation.ClientRotation{
 				UserInfo: &user.DefaultInfo{
@@ -614,7 +630,6 @@ func newCertRotationController(
 		operatorClient,
 		eventRecorder,
 	)
-	ret.certRotators = append(ret.certRotators, certRotator)
 
 	return ret, nil
 }
@@ -0,0 +1,233 @@
+package certrotationcontroller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/operatorclient"
+)
+
+// RotationConfigConfigMapName is an optional configmap in the operator namespace that allows an admin to configure the
+// validity and refresh periods of the certificates managed by the operator. For every category the keys
+// <category>Validity and <category>Refresh hold durations, e.g. servingCertValidity: "720h". Unset keys keep the default.
+const RotationConfigConfigMapName = "cert-rotation-config"
+
+// RotationCategory groups certificates sharing their validity and refresh periods.
+type RotationCategory string
+
+const (
+	AggregatorClientSignerCategory   RotationCategory = "aggregatorClientSigner"
+	ControlPlaneClientSignerCategory RotationCategory = "controlPlaneClientSigner"
+	KubeletClientSignerCategory      RotationCategory = "kubeletClientSigner"
+	NodeSystemAdminSignerCategory    RotationCategory = "nodeSystemAdminSigner"
+	// ServingSignerCategory covers the localhost, service network, load balancer and localhost recovery serving signers.
+	ServingSignerCategory RotationCategory = "servingSigner"
+
+	// ClientCertCategory covers the client certificates of the kube-apiserver and the control plane components.
+	ClientCertCategory RotationCategory = "clientCert"
+	// ServingCertCategory covers the serving certificates of the kube-apiserver except the localhost recovery one.
+	ServingCertCategory                  RotationCategory = "servingCert"
+	LocalhostRecoveryServingCertCategory RotationCategory = "localhostRecoveryServingCert"
+	NodeSystemAdminClientCertCategory    RotationCategory = "nodeSystemAdminClientCert"
+)
+
+// RotationPeriod is how long a certificate is valid and after how long it is rotated at the latest.
+type RotationPeriod struct {
+	Validity time.Duration
+	Refresh  time.Duration
+}
+
+// RotationConfig holds the rotation period of every category.
+type RotationConfig map[RotationCategory]RotationPeriod
+
+// rotationCategories lists the categories with the shortest validity an admin may configure and the categories of the
+// certificates their signers issue.
+var rotationCategories = []struct {
+	category    RotationCategory
+	minValidity time.Duration
+	signs       []RotationCategory
+}{
+	{category: AggregatorClientSignerCategory, minValidity: 12 * time.Hour, signs: []RotationCategory{ClientCertCategory}},
+	{category: ControlPlaneClientSignerCategory, minValidity: 24 * time.Hour, signs: []RotationCategory{ClientCertCategory}},
+	// new kubelet and serving signers must reach every node and client before they are used
+	{category: KubeletClientSignerCategory, minValidity: 30 * defaultRotationDay, signs: []RotationCategory{ClientCertCategory}},
+	{category: NodeSystemAdminSignerCategory, minValidity: 30 * defaultRotationDay, signs: []RotationCategory{NodeSystemAdminClientCertCategory}},
+	{category: ServingSignerCategory, minValidity: 30 * defaultRotationDay, signs: []RotationCategory{ServingCertCategory, LocalhostRecoveryServingCertCategory}},
+	{category: ClientCertCategory, minValidity: 12 * time.Hour},
+	{category: ServingCertCategory, minValidity: 12 * time.Hour},
+	// every rotation of the localhost recovery serving certificate rolls out a new revision
+	{category: LocalhostRecoveryServingCertCategory, minValidity: 30 * defaultRotationDay},
+	{category: NodeSystemAdminClientCertCategory, minValidity: 24 * time.Hour},
+}
+
+// DefaultRotationConfig returns the built-in rotation periods. day scales the periods of the short-lived certificates, it
+// is only set by the unsupported cert rotation config.
+func DefaultRotationConfig(day time.Duration) RotationConfig {
+	rotationDay := defaultRotationDay
+	if day != time.Duration(0) {
+		rotationDay = day
+		klog.Warningf("!!! UNSUPPORTED VALUE SET !!!")
+		klog.Warningf("Certificate rotation base set to %q", rotationDay)
+	} else {
+		// for the development cycle, make the rotation 60 times faster (every twelve hours or so).
+		// This must be reverted before we ship
+		rotationDay = rotationDay / 60
+	}
+
+	return RotationConfig{
+		AggregatorClientSignerCategory:   {Validity: 30 * rotationDay, Refresh: 15 * rotationDay},
+		ControlPlaneClientSignerCategory: {Validity: 60 * defaultRotationDay, Refresh: 30 * defaultRotationDay},
+		KubeletClientSignerCategory: {
+			Validity: 1 * 365 * defaultRotationDay, // this comes from the installer
+			Refresh:  8 * 365 * defaultRotationDay, // this means we effectively do not rotate
+		},
+		NodeSystemAdminSignerCategory: {Validity: 1 * 365 * defaultRotationDay, Refresh: 292 * defaultRotationDay},
+		ServingSignerCategory: {
+			Validity: 10 * 365 * defaultRotationDay, // this comes from the installer
+			Refresh:  8 * 365 * defaultRotationDay,  // this means we effectively do not rotate
+		},
+		ClientCertCategory:  {Validity: 30 * rotationDay, Refresh: 15 * rotationDay},
+		ServingCertCategory: {Validity: 30 * rotationDay, Refresh: 15 * rotationDay},
+		LocalhostRecoveryServingCertCategory: {
+			Validity: 10 * 365 * defaultRotationDay,
+			Refresh:  8 * 365 * defaultRotationDay, // this means we effectively do not rotate
+		},
+		NodeSystemAdminClientCertCategory: {
+			// This needs to live longer then control plane certs so there is high chance that if a cluster breaks
+			// because of expired certs these are still valid to use for collecting data using localhost-recovery
+			// endpoint with long lived serving certs for localhost.
+			Validity: 120 * defaultRotationDay,
+			// We rotate sooner so certs are always valid for 90 days (30 days more then kube-control-plane-signer)
+			Refresh: 30 * defaultRotationDay,
+		},
+	}
+}
+
+// GetRotationConfig reads the rotation periods configured in the RotationConfigConfigMapName configmap on top of the
+// given defaults.
+func GetRotationConfig(ctx context.Context, client kubernetes.Interface, defaults RotationConfig) (RotationConfig, error) {
+	var data map[string]string
+	err := wait.PollImmediate(time.Second, 1*time.Minute, func() (bool, error) {
+		configMap, err := client.CoreV1().ConfigMaps(operatorclient.OperatorNamespace).Get(ctx, RotationConfigConfigMapName, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return true, nil
+		}
+		if err != nil {
+			klog.V(2).Infof("Failed to get configmap %s/%s: %v", operatorclient.OperatorNamespace, RotationConfigConfigMapName, err)
+			return false, nil
+		}
+		data = configMap.Data
+		return true, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get configmap %s/%s: %v", operatorclient.OperatorNamespace, RotationConfigConfigMapName, err)
+	}
+	return ParseRotationConfig(data, defaults)
+}
+
+// ParseRotationConfig overrides the defaults with the rotation periods in the given configmap data. A configured
+// validity must not be shorter than the minimum of its category, a configured refresh must not exceed the validity or be
+// shorter than half the minimum validity, and no certificate may be valid longer than its signer.
+func ParseRotationConfig(data map[string]string, defaults RotationConfig) (RotationConfig, error) {
+	config := RotationConfig{}
+	for category, period := range defaults {
+		config[category] = period
+	}
+
+	known := map[string]bool{}
+	for _, c := range rotationCategories {
+		validityKey, refreshKey := string(c.category)+"Validity", string(c.category)+"Refresh"
+		known[validityKey], known[refreshKey] = true, true
+
+		validity, err := parseDuration(data, validityKey)
+		if err != nil {
+			return nil, err
+		}
+		refresh, err := parseDuration(data, refreshKey)
+		if err != nil {
+			return nil, err
+		}
+		if validity == 0 && refresh == 0 {
+			continue
+		}
+
+		period := config[c.category]
+		if validity != 0 {
+			if validity < c.minValidity {
+				return nil, invalidKey(validityKey, fmt.Errorf("must be at least %v", c.minValidity))
+			}
+			period.Validity = validity
+		}
+		if refresh != 0 {
+			period.Refresh = refresh
+		}
+		if period.Refresh > period.Validity {
+			return nil, invalidKey(refreshKey, fmt.Errorf("%v must not exceed the validity %v", period.Refresh, period.Validity))
+		}
+		if period.Refresh < c.minValidity/2 {
+			return nil, invalidKey(refreshKey, fmt.Errorf("must be at least %v", c.minValidity/2))
+		}
+		config[c.category] = period
+	}
+
+	for key := range data {
+		if !known[key] {
+			return nil, invalidKey(key, fmt.Errorf("unknown key"))
+		}
+	}
+
+	for _, c := range rotationCategories {
+		for _, signed := range c.signs {
+			if config[signed].Validity > config[c.category].Validity {
+				return nil, fmt.Errorf("invalid configmap %s/%s: %sValidity %v exceeds %sValidity %v of its signer",
+					operatorclient.OperatorNamespace, RotationConfigConfigMapName, signed, config[signed].Validity, c.category, config[c.category].Validity)
+			}
+		}
+	}
+
+	return config, nil
+}
+
+func parseDuration(data map[string]string, key string) (time.Duration, error) {
+	value := data[key]
+	if len(value) == 0 {
+		return 0, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, invalidKey(key, err)
+	}
+	if duration <= 0 {
+		return 0, invalidKey(key, fmt.Errorf("must be positive"))
+	}
+	return duration, nil
+}
+
+func invalidKey(key string, err error) error {
+	return fmt.Errorf("invalid %s in configmap %s/%s: %v", key, operatorclient.OperatorNamespace, RotationConfigConfigMapName, err)
+}
+
+// String lists the rotation periods of all categories.
+func (c RotationConfig) String() string {
+	categories := make([]string, 0, len(c))
+	for category := range c {
+		categories = append(categories, string(category))
+	}
+	sort.Strings(categories)
+
+	periods := make([]string, 0, len(categories))
+	for _, category := range categories {
+		period := c[RotationCategory(category)]
+		periods = append(periods, fmt.Sprintf("%s validity %v refresh %v", category, period.Validity, period.Refresh))
+	}
+	return strings.Join(periods, ", ")
+}
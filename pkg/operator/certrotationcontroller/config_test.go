@@ -0,0 +1,189 @@
+package certrotationcontroller
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/operatorclient"
+)
+
+func TestParseRotationConfig(t *testing.T) {
+	defaults := DefaultRotationConfig(0)
+
+	testCases := []struct {
+		name          string
+		data          map[string]string
+		expected      map[RotationCategory]RotationPeriod
+		expectedError string
+	}{
+		{
+			name: "Defaults",
+		},
+		{
+			name: "ShorterLeafCertificates",
+			data: map[string]string{"servingCertValidity": "24h", "servingCertRefresh": "12h"},
+			expected: map[RotationCategory]RotationPeriod{
+				ServingCertCategory: {Validity: 24 * time.Hour, Refresh: 12 * time.Hour},
+			},
+		},
+		{
+			name: "LongerSignerAndLeafCertificates",
+			data: map[string]string{
+				"controlPlaneClientSignerValidity": "8760h",
+				"controlPlaneClientSignerRefresh":  "4380h",
+				"clientCertValidity":               "720h",
+				"clientCertRefresh":                "360h",
+				"aggregatorClientSignerValidity":   "1440h",
+				"aggregatorClientSignerRefresh":    "720h",
+			},
+			expected: map[RotationCategory]RotationPeriod{
+				ControlPlaneClientSignerCategory: {Validity: 8760 * time.Hour, Refresh: 4380 * time.Hour},
+				ClientCertCategory:               {Validity: 720 * time.Hour, Refresh: 360 * time.Hour},
+				AggregatorClientSignerCategory:   {Validity: 1440 * time.Hour, Refresh: 720 * time.Hour},
+			},
+		},
+		{
+			name:          "InvalidDuration",
+			data:          map[string]string{"servingCertValidity": "a month"},
+			expectedError: "invalid servingCertValidity",
+		},
+		{
+			name:          "Negative",
+			data:          map[string]string{"servingCertRefresh": "-1h"},
+			expectedError: "must be positive",
+		},
+		{
+			name:          "BelowMinimum",
+			data:          map[string]string{"servingSignerValidity": "24h", "servingSignerRefresh": "12h"},
+			expectedError: "invalid servingSignerValidity in configmap openshift-kube-apiserver-operator/cert-rotation-config: must be at least 720h0m0s",
+		},
+		{
+			name:          "RefreshExceedsValidity",
+			data:          map[string]string{"kubeletClientSignerValidity": "17520h"},
+			expectedError: "invalid kubeletClientSignerRefresh",
+		},
+		{
+			name:          "RefreshTooOften",
+			data:          map[string]string{"servingCertValidity": "24h", "servingCertRefresh": "1h"},
+			expectedError: "must be at least 6h0m0s",
+		},
+		{
+			name:          "OutlivesSigner",
+			data:          map[string]string{"clientCertValidity": "720h", "clientCertRefresh": "360h"},
+			expectedError: "clientCertValidity 720h0m0s exceeds aggregatorClientSignerValidity 12h0m0s of its signer",
+		},
+		{
+			name:          "UnknownKey",
+			data:          map[string]string{"servingCertLifetime": "24h"},
+			expectedError: "invalid servingCertLifetime",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := ParseRotationConfig(tc.data, defaults)
+			if len(tc.expectedError) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+					t.Fatalf("expected error containing %q, got %v", tc.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for category, period := range defaults {
+				expected, ok := tc.expected[category]
+				if !ok {
+					expected = period
+				}
+				if actual[category] != expected {
+					t.Errorf("%s: expected %+v, got %+v", category, expected, actual[category])
+				}
+			}
+		})
+	}
+}
+
+func TestRotationConfigController(t *testing.T) {
+	defaults := DefaultRotationConfig(0)
+
+	testCases := []struct {
+		name           string
+		data           map[string]string
+		expectedStatus operatorv1.ConditionStatus
+		expectedExit   bool
+	}{
+		{
+			name:           "Unchanged",
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+		{
+			name:           "Invalid",
+			data:           map[string]string{"servingCertValidity": "1h"},
+			expectedStatus: operatorv1.ConditionTrue,
+		},
+		{
+			name:         "Changed",
+			data:         map[string]string{"servingCertValidity": "24h", "servingCertRefresh": "12h"},
+			expectedExit: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			if tc.data != nil {
+				if err := indexer.Add(&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: RotationConfigConfigMapName},
+					Data:       tc.data,
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			operatorClient := v1helpers.NewFakeStaticPodOperatorClient(&operatorv1.StaticPodOperatorSpec{}, &operatorv1.StaticPodOperatorStatus{}, nil, nil)
+			exited := false
+			c := &rotationConfigController{
+				operatorClient:  operatorClient,
+				configMapLister: corev1listers.NewConfigMapLister(indexer),
+				running:         defaults,
+				defaults:        defaults,
+				exit:            func() { exited = true },
+			}
+
+			if err := c.sync(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))); err != nil {
+				t.Fatal(err)
+			}
+			if exited != tc.expectedExit {
+				t.Fatalf("expected exit %v, got %v", tc.expectedExit, exited)
+			}
+			if tc.expectedExit {
+				return
+			}
+
+			_, status, _, err := operatorClient.GetStaticPodOperatorState()
+			if err != nil {
+				t.Fatal(err)
+			}
+			condition := v1helpers.FindOperatorCondition(status.Conditions, RotationConfigDegradedConditionType)
+			if condition == nil {
+				t.Fatalf("expected condition %s to be set", RotationConfigDegradedConditionType)
+			}
+			if condition.Status != tc.expectedStatus {
+				t.Errorf("expected status %s, got %s: %s", tc.expectedStatus, condition.Status, condition.Message)
+			}
+			if !strings.Contains(condition.Message, "servingCert validity 12h0m0s refresh 6h0m0s") {
+				t.Errorf("expected the running periods in the message, got %q", condition.Message)
+			}
+		})
+	}
+}
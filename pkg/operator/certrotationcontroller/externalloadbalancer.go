@@ -24,8 +24,14 @@ func (c *CertRotationController) syncExternalLoadBalancerHostnames() error {
 	hostname_arr := strings.Split(hostname, ":")
 	hostname = hostname_arr[0]
 
-	klog.V(2).Infof("syncing external loadbalancer hostnames: %v", hostname)
-	c.externalLoadBalancer.setHostnames([]string{hostname})
+	additionalHostnames, err := c.additionalHostnames(AdditionalExternalHostnamesConfigKey)
+	if err != nil {
+		return err
+	}
+	hostnames := append([]string{hostname}, additionalHostnames...)
+
+	klog.V(2).Infof("syncing external loadbalancer hostnames: %v", hostnames)
+	c.externalLoadBalancer.setHostnames(hostnames)
 	return nil
 }
 
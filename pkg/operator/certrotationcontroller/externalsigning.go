@@ -0,0 +1,228 @@
+package certrotationcontroller
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/cert"
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/library-go/pkg/crypto"
+	"github.com/openshift/library-go/pkg/operator/certrotation"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/externalsigner"
+)
+
+const (
+	externalSigningKeyBits = 2048
+	externalSigningTimeout = time.Minute
+
+	// ExternalSignerTrustedSinceAnnotation is set on a CA bundle to the time the CA certificates of the external signer
+	// were last added to it.
+	ExternalSignerTrustedSinceAnnotation = "kubeapiserver.operator.openshift.io/external-signer-trusted-since"
+)
+
+// externalSigningRotation issues the certificates of the wrapped creator with an external signer instead of the
+// in-cluster signer. The CA certificates of the external signer are added to the CA bundle first, the cert rotation
+// controller keeps them there until they expire. Like a new in-cluster signer, the external signer is only used once
+// its CA certificates have been in the bundle for trustPropagation, so that the consumers of the bundle trust them.
+// Until then the wrapped creator issues short-lived certificates with the in-cluster signer.
+type externalSigningRotation struct {
+	certrotation.TargetCertCreator
+
+	signer           externalsigner.Signer
+	caBundle         certrotation.CABundleConfigMap
+	trustPropagation time.Duration
+}
+
+var _ certrotation.TargetCertRechecker = &externalSigningRotation{}
+
+func (r *externalSigningRotation) NewCertificate(signer *crypto.CA, validity time.Duration) (*crypto.TLSCertificateConfig, error) {
+	template, usage, err := r.certificateRequest()
+	if err != nil {
+		return nil, err
+	}
+	key, err := rsa.GenerateKey(rand.Reader, externalSigningKeyBits)
+	if err != nil {
+		return nil, err
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, template, key)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), externalSigningTimeout)
+	defer cancel()
+	resp, err := r.signer.Sign(ctx, externalsigner.Request{
+		CSR:      pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csr}),
+		Usage:    usage,
+		Validity: validity,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyIssuedCertificate(resp, &key.PublicKey, usage); err != nil {
+		return nil, err
+	}
+	// the cert rotation controller looks for the issuer of the certificate in the CA bundle, so intermediates are added too
+	caCerts := append(append([]*x509.Certificate{}, resp.CABundle...), resp.Certificates[1:]...)
+	trustedSince, err := r.ensureCABundle(ctx, caCerts)
+	if err != nil {
+		return nil, err
+	}
+
+	if wait := time.Until(trustedSince.Add(r.trustPropagation)); wait > 0 {
+		// the certificate is rotated at 80% of its validity, which is once the external signer is trusted
+		interimValidity := (wait + time.Minute) * 5 / 4
+		if interimValidity > validity {
+			interimValidity = validity
+		}
+		klog.V(2).Infof("Issuing %s with the in-cluster signer, the external signer is trusted by configmap %s/%s in %v",
+			interimValidity, r.caBundle.Namespace, r.caBundle.Name, wait)
+		return r.TargetCertCreator.NewCertificate(signer, interimValidity)
+	}
+
+	return &crypto.TLSCertificateConfig{Certs: resp.Certificates, Key: key}, nil
+}
+
+func (r *externalSigningRotation) RecheckChannel() <-chan struct{} {
+	if rechecker, ok := r.TargetCertCreator.(certrotation.TargetCertRechecker); ok {
+		return rechecker.RecheckChannel()
+	}
+	return nil
+}
+
+// certificateRequest returns the subject and subject alternative names the wrapped creator would issue.
+func (r *externalSigningRotation) certificateRequest() (*x509.CertificateRequest, externalsigner.Usage, error) {
+	switch creator := r.TargetCertCreator.(type) {
+	case *certrotation.ClientRotation:
+		return &x509.CertificateRequest{
+			Subject: pkix.Name{CommonName: creator.UserInfo.GetName(), Organization: creator.UserInfo.GetGroups()},
+		}, externalsigner.UsageClient, nil
+
+	case *certrotation.ServingRotation:
+		if len(creator.CertificateExtensionFn) > 0 {
+			return nil, "", fmt.Errorf("certificate extensions are not supported by the external signer")
+		}
+		hostnames := sets.NewString(creator.Hostnames()...).List()
+		if len(hostnames) == 0 {
+			return nil, "", fmt.Errorf("no hostnames set")
+		}
+		template := &x509.CertificateRequest{Subject: pkix.Name{CommonName: hostnames[0]}}
+		for _, hostname := range hostnames {
+			if ip := net.ParseIP(hostname); ip != nil {
+				template.IPAddresses = append(template.IPAddresses, ip)
+			} else {
+				template.DNSNames = append(template.DNSNames, hostname)
+			}
+		}
+		return template, externalsigner.UsageServing, nil
+
+	default:
+		return nil, "", fmt.Errorf("%T is not supported by the external signer", creator)
+	}
+}
+
+// verifyIssuedCertificate checks that the signer issued a certificate for the given key that chains up to its CA bundle.
+func verifyIssuedCertificate(resp *externalsigner.Response, key *rsa.PublicKey, usage externalsigner.Usage) error {
+	if len(resp.Certificates) == 0 {
+		return fmt.Errorf("external signer returned no certificate")
+	}
+	if len(resp.CABundle) == 0 {
+		return fmt.Errorf("external signer returned no CA bundle")
+	}
+	if !key.Equal(resp.Certificates[0].PublicKey) {
+		return fmt.Errorf("external signer returned a certificate for a different key")
+	}
+
+	opts := x509.VerifyOptions{
+		Roots:         x509.NewCertPool(),
+		Intermediates: x509.NewCertPool(),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	if usage == externalsigner.UsageServing {
+		opts.KeyUsages = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+	}
+	for _, ca := range resp.CABundle {
+		opts.Roots.AddCert(ca)
+	}
+	for _, intermediate := range resp.Certificates[1:] {
+		opts.Intermediates.AddCert(intermediate)
+	}
+	if _, err := resp.Certificates[0].Verify(opts); err != nil {
+		return fmt.Errorf("external signer returned an invalid certificate: %v", err)
+	}
+	return nil
+}
+
+// ensureCABundle adds the CA certificates of the external signer to the CA bundle and returns since when the bundle
+// holds all of them. The configmap is read from the server, the cert rotation controller may have just added a new
+// in-cluster signer to it.
+func (r *externalSigningRotation) ensureCABundle(ctx context.Context, caCerts []*x509.Certificate) (time.Time, error) {
+	configMap, err := r.caBundle.Client.ConfigMaps(r.caBundle.Namespace).Get(ctx, r.caBundle.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		configMap = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: r.caBundle.Namespace, Name: r.caBundle.Name}}
+	} else if err != nil {
+		return time.Time{}, err
+	}
+
+	certificates := []*x509.Certificate{}
+	if caBundle := configMap.Data["ca-bundle.crt"]; len(caBundle) > 0 {
+		if certificates, err = cert.ParseCertsPEM([]byte(caBundle)); err != nil {
+			return time.Time{}, err
+		}
+	}
+	missing := false
+	for _, caCert := range caCerts {
+		if !containsCertificate(certificates, caCert) {
+			certificates = append(certificates, caCert)
+			missing = true
+		}
+	}
+	trustedSince, err := time.Parse(time.RFC3339, configMap.Annotations[ExternalSignerTrustedSinceAnnotation])
+	if !missing && err == nil {
+		return trustedSince, nil
+	}
+
+	caBytes, err := crypto.EncodeCertificates(certificates...)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+	configMap.Data["ca-bundle.crt"] = string(caBytes)
+	if configMap.Annotations == nil {
+		configMap.Annotations = map[string]string{}
+	}
+	trustedSince = time.Now()
+	configMap.Annotations[ExternalSignerTrustedSinceAnnotation] = trustedSince.Format(time.RFC3339)
+	certrotation.LabelAsManagedConfigMap(configMap, certrotation.CertificateTypeCABundle)
+
+	if _, _, err := resourceapply.ApplyConfigMap(ctx, r.caBundle.Client, r.caBundle.EventRecorder, configMap); err != nil {
+		return time.Time{}, err
+	}
+	return trustedSince, nil
+}
+
+func containsCertificate(certificates []*x509.Certificate, certificate *x509.Certificate) bool {
+	for _, existing := range certificates {
+		if bytes.Equal(existing.Raw, certificate.Raw) {
+			return true
+		}
+	}
+	return false
+}
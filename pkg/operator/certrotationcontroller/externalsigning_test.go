@@ -0,0 +1,279 @@
+package certrotationcontroller
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/util/cert"
+
+	"github.com/openshift/library-go/pkg/crypto"
+	"github.com/openshift/library-go/pkg/operator/certrotation"
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/externalsigner"
+)
+
+// fakeSigner issues certificates with an intermediate of its root CA.
+type fakeSigner struct {
+	root         *crypto.TLSCertificateConfig
+	intermediate *crypto.TLSCertificateConfig
+	// otherKey makes the signer issue the certificate for a different key than requested.
+	otherKey bool
+	requests []externalsigner.Request
+}
+
+func (s *fakeSigner) Sign(_ context.Context, request externalsigner.Request) (*externalsigner.Response, error) {
+	s.requests = append(s.requests, request)
+	block, _ := pem.Decode(request.CSR)
+	if block == nil {
+		return nil, fmt.Errorf("invalid CSR")
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	publicKey := csr.PublicKey
+	if s.otherKey {
+		if publicKey, _, err = crypto.NewKeyPair(); err != nil {
+			return nil, err
+		}
+	}
+	usage := x509.ExtKeyUsageClientAuth
+	if request.Usage == externalsigner.UsageServing {
+		usage = x509.ExtKeyUsageServerAuth
+	}
+	signed, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      csr.Subject,
+		DNSNames:     csr.DNSNames,
+		IPAddresses:  csr.IPAddresses,
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(request.Validity),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}, s.intermediate.Certs[0], publicKey, s.intermediate.Key)
+	if err != nil {
+		return nil, err
+	}
+	leaf, err := x509.ParseCertificate(signed)
+	if err != nil {
+		return nil, err
+	}
+	return &externalsigner.Response{
+		Certificates: []*x509.Certificate{leaf, s.intermediate.Certs[0]},
+		CABundle:     []*x509.Certificate{s.root.Certs[0]},
+	}, nil
+}
+
+func TestExternalSigningRotation(t *testing.T) {
+	root, err := crypto.MakeSelfSignedCAConfigForDuration("external-root", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	intermediate, err := crypto.MakeCAConfigForDuration("external-intermediate", time.Hour, &crypto.CA{Config: root, SerialGenerator: &crypto.RandomSerialGenerator{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	inClusterSigner, err := crypto.MakeSelfSignedCAConfigForDuration("in-cluster-signer", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inClusterBundle, err := crypto.EncodeCertificates(inClusterSigner.Certs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name              string
+		creator           certrotation.TargetCertCreator
+		otherKey          bool
+		expectedUsage     externalsigner.Usage
+		expectedCN        string
+		expectedHostnames []string
+		expectedError     string
+	}{
+		{
+			name:          "Client",
+			creator:       &certrotation.ClientRotation{UserInfo: &user.DefaultInfo{Name: "system:kube-apiserver", Groups: []string{"kube-master"}}},
+			expectedUsage: externalsigner.UsageClient,
+			expectedCN:    "system:kube-apiserver",
+		},
+		{
+			name:              "Serving",
+			creator:           &certrotation.ServingRotation{Hostnames: func() []string { return []string{"localhost", "127.0.0.1"} }},
+			expectedUsage:     externalsigner.UsageServing,
+			expectedCN:        "127.0.0.1",
+			expectedHostnames: []string{"127.0.0.1", "localhost"},
+		},
+		{
+			name:          "NoHostnames",
+			creator:       &certrotation.ServingRotation{Hostnames: func() []string { return nil }},
+			expectedError: "no hostnames set",
+		},
+		{
+			name:          "Signer",
+			creator:       &certrotation.SignerRotation{SignerName: "signer"},
+			expectedError: "*certrotation.SignerRotation is not supported by the external signer",
+		},
+		{
+			name:          "DifferentKey",
+			creator:       &certrotation.ClientRotation{UserInfo: &user.DefaultInfo{Name: "system:kube-apiserver"}},
+			otherKey:      true,
+			expectedError: "external signer returned a certificate for a different key",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			kubeClient := fake.NewSimpleClientset(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "ca-bundle"},
+				Data:       map[string]string{"ca-bundle.crt": string(inClusterBundle)},
+			})
+			signer := &fakeSigner{root: root, intermediate: intermediate, otherKey: tc.otherKey}
+			r := &externalSigningRotation{
+				TargetCertCreator: tc.creator,
+				signer:            signer,
+				caBundle: certrotation.CABundleConfigMap{
+					Namespace:     "ns",
+					Name:          "ca-bundle",
+					Client:        kubeClient.CoreV1(),
+					EventRecorder: events.NewInMemoryRecorder("test"),
+				},
+			}
+
+			// issuing twice must not add the external CA twice
+			for i := 0; i < 2; i++ {
+				certKeyPair, err := r.NewCertificate(nil, time.Hour)
+				if len(tc.expectedError) > 0 {
+					if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+						t.Fatalf("expected error containing %q, got %v", tc.expectedError, err)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				if _, _, err := certKeyPair.GetPEMBytes(); err != nil {
+					t.Fatal(err)
+				}
+				leaf := certKeyPair.Certs[0]
+				if leaf.Subject.CommonName != tc.expectedCN {
+					t.Errorf("expected CN %q, got %q", tc.expectedCN, leaf.Subject.CommonName)
+				}
+				if leaf.Issuer.CommonName != intermediate.Certs[0].Subject.CommonName {
+					t.Errorf("expected the certificate to be issued by the intermediate, got %q", leaf.Issuer.CommonName)
+				}
+				if hostnames := r.SetAnnotations(certKeyPair, map[string]string{})[certrotation.CertificateHostnames]; len(tc.expectedHostnames) > 0 && hostnames != strings.Join(tc.expectedHostnames, ",") {
+					t.Errorf("expected hostnames %v, got %q", tc.expectedHostnames, hostnames)
+				}
+			}
+			if signer.requests[0].Usage != tc.expectedUsage || signer.requests[0].Validity != time.Hour {
+				t.Errorf("unexpected request %+v", signer.requests[0])
+			}
+
+			configMap, err := kubeClient.CoreV1().ConfigMaps("ns").Get(context.TODO(), "ca-bundle", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			caCerts, err := cert.ParseCertsPEM([]byte(configMap.Data["ca-bundle.crt"]))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var commonNames []string
+			for _, caCert := range caCerts {
+				commonNames = append(commonNames, caCert.Subject.CommonName)
+			}
+			if expected := []string{"in-cluster-signer", "external-root", "external-intermediate"}; !reflect.DeepEqual(commonNames, expected) {
+				t.Errorf("expected CA bundle %v, got %v", expected, commonNames)
+			}
+		})
+	}
+}
+
+func TestExternalSigningRotationTrustPropagation(t *testing.T) {
+	root, err := crypto.MakeSelfSignedCAConfigForDuration("external-root", 48*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	intermediate, err := crypto.MakeCAConfigForDuration("external-intermediate", 48*time.Hour, &crypto.CA{Config: root, SerialGenerator: &crypto.RandomSerialGenerator{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	inClusterSigner, err := crypto.MakeSelfSignedCAConfigForDuration("in-cluster-signer", 48*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inClusterBundle, err := crypto.EncodeCertificates(inClusterSigner.Certs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kubeClient := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "ca-bundle"},
+		Data:       map[string]string{"ca-bundle.crt": string(inClusterBundle)},
+	})
+	r := &externalSigningRotation{
+		TargetCertCreator: &certrotation.ClientRotation{UserInfo: &user.DefaultInfo{Name: "system:kube-apiserver"}},
+		signer:            &fakeSigner{root: root, intermediate: intermediate},
+		caBundle: certrotation.CABundleConfigMap{
+			Namespace:     "ns",
+			Name:          "ca-bundle",
+			Client:        kubeClient.CoreV1(),
+			EventRecorder: events.NewInMemoryRecorder("test"),
+		},
+		trustPropagation: time.Hour,
+	}
+	inClusterCA := &crypto.CA{Config: inClusterSigner, SerialGenerator: &crypto.RandomSerialGenerator{}}
+
+	// the bundle does not hold the external CA yet, the in-cluster signer issues a certificate that is rotated once the
+	// external CA is trusted
+	certKeyPair, err := r.NewCertificate(inClusterCA, 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf := certKeyPair.Certs[0]
+	if leaf.Issuer.CommonName != "in-cluster-signer" {
+		t.Errorf("expected the certificate to be issued by the in-cluster signer, got %q", leaf.Issuer.CommonName)
+	}
+	if validity := leaf.NotAfter.Sub(leaf.NotBefore); validity*4/5 < time.Hour || validity > 2*time.Hour {
+		t.Errorf("expected the certificate to be rotated right after an hour, got validity %v", validity)
+	}
+	configMap, err := kubeClient.CoreV1().ConfigMaps("ns").Get(context.TODO(), "ca-bundle", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	caCerts, err := cert.ParseCertsPEM([]byte(configMap.Data["ca-bundle.crt"]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(caCerts) != 3 {
+		t.Errorf("expected the external CA in the bundle, got %d certificates", len(caCerts))
+	}
+	if _, err := time.Parse(time.RFC3339, configMap.Annotations[ExternalSignerTrustedSinceAnnotation]); err != nil {
+		t.Errorf("expected annotation %s, got %v", ExternalSignerTrustedSinceAnnotation, err)
+	}
+
+	// the external CA has been in the bundle long enough
+	configMap.Annotations[ExternalSignerTrustedSinceAnnotation] = time.Now().Add(-2 * time.Hour).Format(time.RFC3339)
+	if _, err := kubeClient.CoreV1().ConfigMaps("ns").Update(context.TODO(), configMap, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	certKeyPair, err = r.NewCertificate(inClusterCA, 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if leaf := certKeyPair.Certs[0]; leaf.Issuer.CommonName != "external-intermediate" {
+		t.Errorf("expected the certificate to be issued by the external signer, got %q", leaf.Issuer.CommonName)
+	}
+}
@@ -24,8 +24,14 @@ func (c *CertRotationController) syncInternalLoadBalancerHostnames() error {
 	hostname = strings.Replace(hostname, "https://", "", 1)
 	hostname = hostname[0:strings.LastIndex(hostname, ":")]
 
-	klog.V(2).Infof("syncing internal loadbalancer hostnames: %v", hostname)
-	c.internalLoadBalancer.setHostnames([]string{hostname})
+	additionalHostnames, err := c.additionalHostnames(AdditionalInternalHostnamesConfigKey)
+	if err != nil {
+		return err
+	}
+	hostnames := append([]string{hostname}, additionalHostnames...)
+
+	klog.V(2).Infof("syncing internal loadbalancer hostnames: %v", hostnames)
+	c.internalLoadBalancer.setHostnames(hostnames)
 	return nil
 }
 
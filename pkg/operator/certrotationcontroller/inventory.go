@@ -0,0 +1,118 @@
+package certrotationcontroller
+
+import (
+	"time"
+
+	"github.com/openshift/library-go/pkg/operator/certrotation"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+// ManagedCertificate is a secret or config map holding certificates kept up to date by the cert rotation controller.
+type ManagedCertificate struct {
+	Namespace string
+	Name      string
+	Type      certrotation.CertificateType
+	// Refresh is the duration after NotBefore when the certificate is rotated at the latest. It is zero for CA bundles.
+	Refresh time.Duration
+	// RefreshOnlyWhenExpired is set when the certificate is only rotated once it expired.
+	RefreshOnlyWhenExpired bool
+	// Consumer is the component using the certificate.
+	Consumer string
+	// SignerNamespace and SignerName locate the signer of a target certificate.
+	SignerNamespace string
+	SignerName      string
+}
+
+// certConsumers names the components trusting the CA bundle and using the target certificate.
+type certConsumers struct {
+	caBundle string
+	target   string
+}
+
+// addCertRotator creates a cert rotation controller for the signer, CA bundle and target and adds them to the inventory.
+func (c *CertRotationController) addCertRotator(
+	name string,
+	consumers certConsumers,
+	signer certrotation.RotatedSigningCASecret,
+	caBundle certrotation.CABundleConfigMap,
+	target certrotation.RotatedSelfSignedCertKeySecret,
+	operatorClient v1helpers.StaticPodOperatorClient,
+	eventRecorder events.Recorder,
+) {
+	if c.externalSigner != nil {
+		// the same time library-go waits before issuing with a new in-cluster signer
+		target.CertCreator = &externalSigningRotation{
+			TargetCertCreator: target.CertCreator,
+			signer:            c.externalSigner,
+			caBundle:          caBundle,
+			trustPropagation:  target.Refresh / 10,
+		}
+	}
+	c.certRotators = append(c.certRotators, certrotation.NewCertRotationController(name, signer, caBundle, target, operatorClient, eventRecorder))
+	c.addToInventory(ManagedCertificate{
+		Namespace:              signer.Namespace,
+		Name:                   signer.Name,
+		Type:                   certrotation.CertificateTypeSigner,
+		Refresh:                signer.Refresh,
+		RefreshOnlyWhenExpired: signer.RefreshOnlyWhenExpired,
+		Consumer:               "kube-apiserver-operator",
+	})
+	c.addToInventory(ManagedCertificate{
+		Namespace: caBundle.Namespace,
+		Name:      caBundle.Name,
+		Type:      certrotation.CertificateTypeCABundle,
+		Consumer:  consumers.caBundle,
+	})
+	c.addToInventory(ManagedCertificate{
+		Namespace:              target.Namespace,
+		Name:                   target.Name,
+		Type:                   certrotation.CertificateTypeTarget,
+		Refresh:                target.Refresh,
+		RefreshOnlyWhenExpired: target.RefreshOnlyWhenExpired,
+		Consumer:               consumers.target,
+		SignerNamespace:        signer.Namespace,
+		SignerName:             signer.Name,
+	})
+}
+
+// addToInventory adds the certificate unless it is already listed, signers and CA bundles are shared by several targets.
+func (c *CertRotationController) addToInventory(certificate ManagedCertificate) {
+	for _, existing := range c.inventory {
+		if existing.Namespace == certificate.Namespace && existing.Name == certificate.Name {
+			return
+		}
+	}
+	c.inventory = append(c.inventory, certificate)
+}
+
+// RefreshTime returns when the certificate is rotated at the latest: at expiry, after 80% of its validity or after the
+// refresh duration, whichever comes first. Targets are rotated after the refresh duration only once their signer has been
+// valid for a tenth of it, signerNotBefore is ignored for other certificates. CA bundles are not rotated.
+func (m ManagedCertificate) RefreshTime(notBefore, notAfter, signerNotBefore time.Time) (time.Time, bool) {
+	switch m.Type {
+	case certrotation.CertificateTypeSigner, certrotation.CertificateTypeTarget:
+	default:
+		return time.Time{}, false
+	}
+	if m.RefreshOnlyWhenExpired {
+		return notAfter, true
+	}
+	latest := notAfter.Add(-notAfter.Sub(notBefore) / 5)
+	if m.Refresh <= 0 {
+		return latest, true
+	}
+	refresh := notBefore.Add(m.Refresh)
+	if trusted := signerNotBefore.Add(m.Refresh / 10); m.Type == certrotation.CertificateTypeTarget && trusted.After(refresh) {
+		refresh = trusted
+	}
+	if refresh.Before(latest) {
+		return refresh, true
+	}
+	return latest, true
+}
+
+// Inventory returns the certificates managed by the controller.
+func (c *CertRotationController) Inventory() []ManagedCertificate {
+	return append([]ManagedCertificate(nil), c.inventory...)
+}
@@ -0,0 +1,79 @@
+package certrotationcontroller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift/library-go/pkg/operator/certrotation"
+)
+
+func TestRefreshTime(t *testing.T) {
+	notBefore := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	notAfter := notBefore.Add(100 * time.Hour)
+
+	testCases := []struct {
+		name            string
+		managed         ManagedCertificate
+		signerNotBefore time.Time
+		expected        time.Time
+		expectedOK      bool
+	}{
+		{
+			name:    "CABundle",
+			managed: ManagedCertificate{Type: certrotation.CertificateTypeCABundle},
+		},
+		{
+			name:       "Refresh",
+			managed:    ManagedCertificate{Type: certrotation.CertificateTypeTarget, Refresh: 50 * time.Hour},
+			expected:   notBefore.Add(50 * time.Hour),
+			expectedOK: true,
+		},
+		{
+			name:       "EightyPercent",
+			managed:    ManagedCertificate{Type: certrotation.CertificateTypeSigner, Refresh: 90 * time.Hour},
+			expected:   notBefore.Add(80 * time.Hour),
+			expectedOK: true,
+		},
+		{
+			name:       "NoRefresh",
+			managed:    ManagedCertificate{Type: certrotation.CertificateTypeSigner},
+			expected:   notBefore.Add(80 * time.Hour),
+			expectedOK: true,
+		},
+		{
+			name:       "OnlyWhenExpired",
+			managed:    ManagedCertificate{Type: certrotation.CertificateTypeTarget, Refresh: 50 * time.Hour, RefreshOnlyWhenExpired: true},
+			expected:   notAfter,
+			expectedOK: true,
+		},
+		{
+			name:            "NewSigner",
+			managed:         ManagedCertificate{Type: certrotation.CertificateTypeTarget, Refresh: 50 * time.Hour},
+			signerNotBefore: notBefore.Add(48 * time.Hour),
+			expected:        notBefore.Add(53 * time.Hour),
+			expectedOK:      true,
+		},
+		{
+			name:            "NewSignerAfterEightyPercent",
+			managed:         ManagedCertificate{Type: certrotation.CertificateTypeTarget, Refresh: 50 * time.Hour},
+			signerNotBefore: notBefore.Add(78 * time.Hour),
+			expected:        notBefore.Add(80 * time.Hour),
+			expectedOK:      true,
+		},
+		{
+			name:            "SignerIgnoredForSigners",
+			managed:         ManagedCertificate{Type: certrotation.CertificateTypeSigner, Refresh: 50 * time.Hour},
+			signerNotBefore: notBefore.Add(48 * time.Hour),
+			expected:        notBefore.Add(50 * time.Hour),
+			expectedOK:      true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, ok := tc.managed.RefreshTime(notBefore, notAfter, tc.signerNotBefore)
+			if ok != tc.expectedOK || !actual.Equal(tc.expected) {
+				t.Errorf("expected %v %v, got %v %v", tc.expected, tc.expectedOK, actual, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package certrotationcontroller
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/operatorclient"
+)
+
+// LoadBalancerServingConfigDegradedConditionType reports invalid entries of the LoadBalancerServingConfigMapName
+// configmap. The load balancer serving certificates are issued without them.
+const LoadBalancerServingConfigDegradedConditionType = "LoadBalancerServingConfigDegraded"
+
+// loadBalancerServingConfigController reports the hostnames of the load balancer serving config the cert rotation
+// controller skips.
+type loadBalancerServingConfigController struct {
+	operatorClient  v1helpers.StaticPodOperatorClient
+	configMapLister corev1listers.ConfigMapLister
+}
+
+// NewLoadBalancerServingConfigController reports invalid entries of the load balancer serving config in the operator status.
+func NewLoadBalancerServingConfigController(
+	operatorClient v1helpers.StaticPodOperatorClient,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &loadBalancerServingConfigController{
+		operatorClient:  operatorClient,
+		configMapLister: kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().ConfigMaps().Lister(),
+	}
+
+	return factory.New().WithInformers(
+		kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().ConfigMaps().Informer(),
+		operatorClient.Informer(),
+	).ResyncEvery(time.Minute).WithSync(c.sync).ToController("LoadBalancerServingConfigController", eventRecorder)
+}
+
+func (c *loadBalancerServingConfigController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	var data map[string]string
+	configMap, err := c.configMapLister.ConfigMaps(operatorclient.OperatorNamespace).Get(LoadBalancerServingConfigMapName)
+	switch {
+	case errors.IsNotFound(err):
+	case err != nil:
+		return err
+	default:
+		data = configMap.Data
+	}
+
+	var errs []error
+	for _, key := range []string{AdditionalExternalHostnamesConfigKey, AdditionalInternalHostnamesConfigKey} {
+		if _, err := parseHostnames(data[key], key); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	condition := operatorv1.OperatorCondition{
+		Type:   LoadBalancerServingConfigDegradedConditionType,
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+	if err := utilerrors.NewAggregate(errs); err != nil {
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "InvalidConfig"
+		condition.Message = err.Error() + ". The load balancer serving certificates are issued without these entries."
+	}
+
+	_, _, err = v1helpers.UpdateStaticPodStatus(ctx, c.operatorClient, v1helpers.UpdateStaticPodConditionFn(condition))
+	return err
+}
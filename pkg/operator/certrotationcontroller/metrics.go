@@ -0,0 +1,141 @@
+package certrotationcontroller
+
+import (
+	"crypto/x509"
+	"sync"
+	"time"
+
+	"github.com/blang/semver/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/util/cert"
+	"k8s.io/component-base/metrics/legacyregistry"
+
+	"github.com/openshift/library-go/pkg/operator/certrotation"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+var registerMetrics sync.Once
+
+// RegisterMetrics exposes the validity and refresh time of the certificates in the inventory.
+func RegisterMetrics(inventory []ManagedCertificate, kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces) {
+	registerMetrics.Do(func() {
+		legacyregistry.MustRegister(newCertificateMetrics(
+			inventory,
+			kubeInformersForNamespaces.SecretLister(),
+			kubeInformersForNamespaces.ConfigMapLister(),
+		))
+	})
+}
+
+// certificateMetrics computes metrics from the cached secrets and config maps of the inventory.
+type certificateMetrics struct {
+	inventory       []ManagedCertificate
+	secretLister    corev1listers.SecretLister
+	configMapLister corev1listers.ConfigMapLister
+
+	notBefore *prometheus.GaugeVec
+	notAfter  *prometheus.GaugeVec
+	refresh   *prometheus.GaugeVec
+}
+
+func newCertificateMetrics(inventory []ManagedCertificate, secretLister corev1listers.SecretLister, configMapLister corev1listers.ConfigMapLister) *certificateMetrics {
+	// namespace and name would clash with the target labels of the operator
+	labels := []string{"resource_namespace", "resource_name", "role"}
+	return &certificateMetrics{
+		inventory:       inventory,
+		secretLister:    secretLister,
+		configMapLister: configMapLister,
+		notBefore: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "openshift_kube_apiserver_operator_certificate_not_before_timestamp_seconds",
+			Help: "Reports when the certificates managed by the operator were issued. role is one of signer, ca-bundle or target, the newest certificate of a CA bundle is reported.",
+		}, labels),
+		notAfter: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "openshift_kube_apiserver_operator_certificate_not_after_timestamp_seconds",
+			Help: "Reports when the certificates managed by the operator expire. role is one of signer, ca-bundle or target, the newest certificate of a CA bundle is reported.",
+		}, labels),
+		refresh: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "openshift_kube_apiserver_operator_certificate_refresh_timestamp_seconds",
+			Help: "Reports when the signers and target certificates managed by the operator are rotated at the latest.",
+		}, labels),
+	}
+}
+
+func (m *certificateMetrics) Create(version *semver.Version) bool {
+	return true
+}
+
+// Describe reports the metadata for metrics to the prometheus collector.
+func (m *certificateMetrics) Describe(ch chan<- *prometheus.Desc) {
+	ch <- m.notBefore.WithLabelValues("", "", "").Desc()
+	ch <- m.notAfter.WithLabelValues("", "", "").Desc()
+	ch <- m.refresh.WithLabelValues("", "", "").Desc()
+}
+
+// Collect calculates metrics from the cached resources and reports them to the prometheus collector.
+func (m *certificateMetrics) Collect(ch chan<- prometheus.Metric) {
+	// the inventory lists signers before the targets they sign
+	signerNotBefore := map[string]time.Time{}
+	for _, managed := range m.inventory {
+		c := m.certificate(managed)
+		if c == nil {
+			continue
+		}
+		labels := []string{managed.Namespace, managed.Name, string(managed.Type)}
+
+		notBefore := m.notBefore.WithLabelValues(labels...)
+		notBefore.Set(float64(c.NotBefore.Unix()))
+		ch <- notBefore
+		notAfter := m.notAfter.WithLabelValues(labels...)
+		notAfter.Set(float64(c.NotAfter.Unix()))
+		ch <- notAfter
+
+		if managed.Type == certrotation.CertificateTypeSigner {
+			signerNotBefore[managed.Namespace+"/"+managed.Name] = c.NotBefore
+		}
+		if refreshTime, ok := managed.RefreshTime(c.NotBefore, c.NotAfter, signerNotBefore[managed.SignerNamespace+"/"+managed.SignerName]); ok {
+			refresh := m.refresh.WithLabelValues(labels...)
+			refresh.Set(float64(refreshTime.Unix()))
+			ch <- refresh
+		}
+	}
+}
+
+// certificate returns the certificate of a signer or target secret, or the newest certificate of a CA bundle. It returns
+// nil if the resource is missing or cannot be parsed.
+func (m *certificateMetrics) certificate(managed ManagedCertificate) *x509.Certificate {
+	if managed.Type != certrotation.CertificateTypeCABundle {
+		secret, err := m.secretLister.Secrets(managed.Namespace).Get(managed.Name)
+		if err != nil {
+			return nil
+		}
+		certs, err := cert.ParseCertsPEM(secret.Data[corev1.TLSCertKey])
+		if err != nil {
+			return nil
+		}
+		return certs[0]
+	}
+
+	configMap, err := m.configMapLister.ConfigMaps(managed.Namespace).Get(managed.Name)
+	if err != nil {
+		return nil
+	}
+	certs, err := cert.ParseCertsPEM([]byte(configMap.Data["ca-bundle.crt"]))
+	if err != nil {
+		return nil
+	}
+	newest := certs[0]
+	for _, c := range certs[1:] {
+		if c.NotAfter.After(newest.NotAfter) {
+			newest = c
+		}
+	}
+	return newest
+}
+
+func (m *certificateMetrics) ClearState() {}
+
+func (m *certificateMetrics) FQName() string {
+	return "openshift_kube_apiserver_operator_certificate"
+}
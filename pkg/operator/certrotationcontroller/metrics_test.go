@@ -0,0 +1,106 @@
+package certrotationcontroller
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openshift/library-go/pkg/operator/certrotation"
+)
+
+func newCertPEM(t *testing.T, commonName string, notBefore, notAfter time.Time) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestCertificateMetrics(t *testing.T) {
+	signerNotBefore := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	targetNotBefore := signerNotBefore.Add(100 * time.Hour)
+	oldSignerPEM := newCertPEM(t, "old-signer", signerNotBefore.Add(-1000*time.Hour), signerNotBefore.Add(10*time.Hour))
+	signerPEM := newCertPEM(t, "signer", signerNotBefore, signerNotBefore.Add(1000*time.Hour))
+	targetPEM := newCertPEM(t, "target", targetNotBefore, targetNotBefore.Add(30*time.Hour))
+
+	secretIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	configMapIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, obj := range []interface{}{
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "operator", Name: "signer"}, Data: map[string][]byte{"tls.crt": signerPEM}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "target", Name: "target"}, Data: map[string][]byte{"tls.crt": targetPEM}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "target", Name: "invalid"}, Data: map[string][]byte{"tls.crt": []byte("invalid")}},
+	} {
+		if err := secretIndexer.Add(obj); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := configMapIndexer.Add(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "operator", Name: "ca-bundle"},
+		Data:       map[string]string{"ca-bundle.crt": string(signerPEM) + string(oldSignerPEM)},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	m := newCertificateMetrics(
+		[]ManagedCertificate{
+			{Namespace: "operator", Name: "signer", Type: certrotation.CertificateTypeSigner, Refresh: 500 * time.Hour},
+			{Namespace: "operator", Name: "ca-bundle", Type: certrotation.CertificateTypeCABundle},
+			{Namespace: "target", Name: "target", Type: certrotation.CertificateTypeTarget, Refresh: 15 * time.Hour, SignerNamespace: "operator", SignerName: "signer"},
+			{Namespace: "target", Name: "invalid", Type: certrotation.CertificateTypeTarget, Refresh: 15 * time.Hour, SignerNamespace: "operator", SignerName: "signer"},
+			{Namespace: "target", Name: "missing", Type: certrotation.CertificateTypeTarget, Refresh: 15 * time.Hour, SignerNamespace: "operator", SignerName: "signer"},
+		},
+		corev1listers.NewSecretLister(secretIndexer),
+		corev1listers.NewConfigMapLister(configMapIndexer),
+	)
+
+	expected := fmt.Sprintf(`
+# HELP openshift_kube_apiserver_operator_certificate_not_after_timestamp_seconds Reports when the certificates managed by the operator expire. role is one of signer, ca-bundle or target, the newest certificate of a CA bundle is reported.
+# TYPE openshift_kube_apiserver_operator_certificate_not_after_timestamp_seconds gauge
+openshift_kube_apiserver_operator_certificate_not_after_timestamp_seconds{resource_name="ca-bundle",resource_namespace="operator",role="ca-bundle"} %[2]d
+openshift_kube_apiserver_operator_certificate_not_after_timestamp_seconds{resource_name="signer",resource_namespace="operator",role="signer"} %[2]d
+openshift_kube_apiserver_operator_certificate_not_after_timestamp_seconds{resource_name="target",resource_namespace="target",role="target"} %[4]d
+# HELP openshift_kube_apiserver_operator_certificate_not_before_timestamp_seconds Reports when the certificates managed by the operator were issued. role is one of signer, ca-bundle or target, the newest certificate of a CA bundle is reported.
+# TYPE openshift_kube_apiserver_operator_certificate_not_before_timestamp_seconds gauge
+openshift_kube_apiserver_operator_certificate_not_before_timestamp_seconds{resource_name="ca-bundle",resource_namespace="operator",role="ca-bundle"} %[1]d
+openshift_kube_apiserver_operator_certificate_not_before_timestamp_seconds{resource_name="signer",resource_namespace="operator",role="signer"} %[1]d
+openshift_kube_apiserver_operator_certificate_not_before_timestamp_seconds{resource_name="target",resource_namespace="target",role="target"} %[3]d
+# HELP openshift_kube_apiserver_operator_certificate_refresh_timestamp_seconds Reports when the signers and target certificates managed by the operator are rotated at the latest.
+# TYPE openshift_kube_apiserver_operator_certificate_refresh_timestamp_seconds gauge
+openshift_kube_apiserver_operator_certificate_refresh_timestamp_seconds{resource_name="signer",resource_namespace="operator",role="signer"} %[5]d
+openshift_kube_apiserver_operator_certificate_refresh_timestamp_seconds{resource_name="target",resource_namespace="target",role="target"} %[6]d
+`,
+		signerNotBefore.Unix(),
+		signerNotBefore.Add(1000*time.Hour).Unix(),
+		targetNotBefore.Unix(),
+		targetNotBefore.Add(30*time.Hour).Unix(),
+		signerNotBefore.Add(500*time.Hour).Unix(),
+		targetNotBefore.Add(15*time.Hour).Unix(),
+	)
+	if err := testutil.CollectAndCompare(m, strings.NewReader(expected)); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,91 @@
+package certrotationcontroller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/operatorclient"
+)
+
+// RotationConfigDegradedConditionType reports an invalid RotationConfigConfigMapName configmap. Its message lists the
+// rotation periods in effect.
+const RotationConfigDegradedConditionType = "CertRotationConfigDegraded"
+
+// rotationConfigController reports the rotation periods the cert rotation controllers were created with. The periods of
+// the cert rotation controllers cannot change, so it restarts the operator when the configured periods change.
+type rotationConfigController struct {
+	operatorClient  v1helpers.StaticPodOperatorClient
+	configMapLister corev1listers.ConfigMapLister
+
+	// running is the config the cert rotation controllers use.
+	running  RotationConfig
+	defaults RotationConfig
+	exit     func()
+}
+
+// NewRotationConfigController reports the running rotation config in the operator status and restarts the operator
+// when the configured one differs.
+func NewRotationConfigController(
+	running, defaults RotationConfig,
+	operatorClient v1helpers.StaticPodOperatorClient,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &rotationConfigController{
+		operatorClient:  operatorClient,
+		configMapLister: kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().ConfigMaps().Lister(),
+		running:         running,
+		defaults:        defaults,
+		exit:            func() { os.Exit(0) },
+	}
+
+	return factory.New().WithInformers(
+		kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().ConfigMaps().Informer(),
+		operatorClient.Informer(),
+	).ResyncEvery(time.Minute).WithSync(c.sync).ToController("CertRotationConfigController", eventRecorder)
+}
+
+func (c *rotationConfigController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	var data map[string]string
+	configMap, err := c.configMapLister.ConfigMaps(operatorclient.OperatorNamespace).Get(RotationConfigConfigMapName)
+	switch {
+	case errors.IsNotFound(err):
+	case err != nil:
+		return err
+	default:
+		data = configMap.Data
+	}
+
+	condition := operatorv1.OperatorCondition{
+		Type:    RotationConfigDegradedConditionType,
+		Status:  operatorv1.ConditionFalse,
+		Reason:  "AsExpected",
+		Message: fmt.Sprintf("Certificate rotation periods: %s.", c.running),
+	}
+	configured, err := ParseRotationConfig(data, c.defaults)
+	if err != nil {
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "InvalidConfig"
+		condition.Message = fmt.Sprintf("%v. Certificate rotation periods: %s.", err, c.running)
+	} else if !reflect.DeepEqual(configured, c.running) {
+		syncCtx.Recorder().Eventf("CertRotationConfigChanged", "Restarting to apply certificate rotation periods: %s", configured)
+		klog.Infof("Restarting to apply certificate rotation periods: %s", configured)
+		c.exit()
+		return nil
+	}
+
+	_, _, err = v1helpers.UpdateStaticPodStatus(ctx, c.operatorClient, v1helpers.UpdateStaticPodConditionFn(condition))
+	return err
+}
@@ -3,23 +3,40 @@ package certrotationcontroller
 import (
 	"fmt"
 	"net"
+	"strings"
 
 	"k8s.io/klog/v2"
 
 	"github.com/apparentlymart/go-cidr/cidr"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
 )
 
 const workQueueKey = "key"
 
+// DNSResource is the config of the DNS operator, its status reports the cluster domain.
+var DNSResource = operatorv1.GroupVersion.WithResource("dnses")
+
+// defaultClusterDomain is the cluster domain clients have been using since before it could be configured.
+const defaultClusterDomain = "cluster.local"
+
 func (c *CertRotationController) syncServiceHostnames() error {
 	hostnames := sets.NewString("kubernetes", "kubernetes.default", "kubernetes.default.svc")
 	hostnames.Insert("openshift", "openshift.default", "openshift.default.svc")
-	// our DNS operator doesn't allow this to change and doesn't expose this value anywhere.
-	hostnames.Insert("kubernetes.default.svc." + "cluster.local")
-	hostnames.Insert("openshift.default.svc." + "cluster.local")
+	clusterDomains, err := c.clusterDomains()
+	if err != nil {
+		return err
+	}
+	for _, clusterDomain := range clusterDomains {
+		hostnames.Insert("kubernetes.default.svc." + clusterDomain)
+		hostnames.Insert("openshift.default.svc." + clusterDomain)
+	}
 
 	networkConfig, err := c.networkLister.Get("cluster")
 	if err != nil {
@@ -42,6 +59,35 @@ func (c *CertRotationController) syncServiceHostnames() error {
 	return nil
 }
 
+// clusterDomains returns the default cluster domain and those reported by the DNS operator.
+func (c *CertRotationController) clusterDomains() ([]string, error) {
+	clusterDomains := sets.NewString(defaultClusterDomain)
+	dnses, err := c.dnsLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, obj := range dnses {
+		dns, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			return nil, fmt.Errorf("unexpected type %T in the DNS lister", obj)
+		}
+		clusterDomain, _, err := unstructured.NestedString(dns.Object, "status", "clusterDomain")
+		if err != nil {
+			return nil, fmt.Errorf("dns %s: %v", dns.GetName(), err)
+		}
+		clusterDomain = strings.TrimSuffix(clusterDomain, ".")
+		if len(clusterDomain) == 0 {
+			continue
+		}
+		if errs := validation.IsDNS1123Subdomain(clusterDomain); len(errs) > 0 {
+			klog.Warningf("Ignoring cluster domain %q of dns %s: %s", clusterDomain, dns.GetName(), strings.Join(errs, ", "))
+			continue
+		}
+		clusterDomains.Insert(clusterDomain)
+	}
+	return clusterDomains.List(), nil
+}
+
 func (c *CertRotationController) runServiceHostnames() {
 	for c.processServiceHostnames() {
 	}
@@ -0,0 +1,89 @@
+package certrotationcontroller
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/cache"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configlisterv1 "github.com/openshift/client-go/config/listers/config/v1"
+)
+
+func TestSyncServiceHostnames(t *testing.T) {
+	testCases := []struct {
+		name           string
+		clusterDomains []string
+		expected       []string
+	}{
+		{
+			name: "NoDNS",
+			expected: []string{
+				"172.30.0.1",
+				"kubernetes", "kubernetes.default", "kubernetes.default.svc", "kubernetes.default.svc.cluster.local",
+				"openshift", "openshift.default", "openshift.default.svc", "openshift.default.svc.cluster.local",
+			},
+		},
+		{
+			name:           "DefaultClusterDomain",
+			clusterDomains: []string{"cluster.local."},
+			expected: []string{
+				"172.30.0.1",
+				"kubernetes", "kubernetes.default", "kubernetes.default.svc", "kubernetes.default.svc.cluster.local",
+				"openshift", "openshift.default", "openshift.default.svc", "openshift.default.svc.cluster.local",
+			},
+		},
+		{
+			name:           "CustomClusterDomains",
+			clusterDomains: []string{"east.example.com", "west.example.com", "Invalid_Domain", ""},
+			expected: []string{
+				"172.30.0.1",
+				"kubernetes", "kubernetes.default", "kubernetes.default.svc",
+				"kubernetes.default.svc.cluster.local", "kubernetes.default.svc.east.example.com", "kubernetes.default.svc.west.example.com",
+				"openshift", "openshift.default", "openshift.default.svc",
+				"openshift.default.svc.cluster.local", "openshift.default.svc.east.example.com", "openshift.default.svc.west.example.com",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			networkIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if err := networkIndexer.Add(&configv1.Network{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Status:     configv1.NetworkStatus{ServiceNetwork: []string{"172.30.0.0/16"}},
+			}); err != nil {
+				t.Fatal(err)
+			}
+			dnsIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			for i, clusterDomain := range tc.clusterDomains {
+				dns := &unstructured.Unstructured{}
+				dns.SetAPIVersion("operator.openshift.io/v1")
+				dns.SetKind("DNS")
+				dns.SetName(string(rune('a' + i)))
+				if err := unstructured.SetNestedField(dns.Object, clusterDomain, "status", "clusterDomain"); err != nil {
+					t.Fatal(err)
+				}
+				if err := dnsIndexer.Add(dns); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			c := &CertRotationController{
+				networkLister:  configlisterv1.NewNetworkLister(networkIndexer),
+				dnsLister:      cache.NewGenericLister(dnsIndexer, DNSResource.GroupResource()),
+				serviceNetwork: &DynamicServingRotation{hostnamesChanged: make(chan struct{}, 10)},
+			}
+			if err := c.syncServiceHostnames(); err != nil {
+				t.Fatal(err)
+			}
+			if actual := c.serviceNetwork.GetHostnames(); !reflect.DeepEqual(actual, tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, actual)
+			}
+			if len(c.serviceNetwork.hostnamesChanged) != 1 {
+				t.Errorf("expected the hostnames change to be signalled once, got %d", len(c.serviceNetwork.hostnamesChanged))
+			}
+		})
+	}
+}
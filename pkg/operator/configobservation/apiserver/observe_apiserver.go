@@ -17,6 +17,7 @@ import (
 	"github.com/openshift/library-go/pkg/operator/resourcesynccontroller"
 
 	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/configobservation"
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/namedcertificatescontroller"
 	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/operatorclient"
 )
 
@@ -26,6 +27,9 @@ const (
 	namedUserServingCertResourceNameFormat = "user-serving-cert-%03d"
 )
 
+// namedUserServingCertResourceNames are the secrets the first named certificates used to be synced to before they were
+// bundled into the named certificates bundle secret. They are still synced for revisions and operators that reference
+// them, and can be dropped once upgrades from those releases are no longer supported.
 var namedUserServingCertResourceNames = []string{
 	fmt.Sprintf(namedUserServingCertResourceNameFormat, 0),
 	fmt.Sprintf(namedUserServingCertResourceNameFormat, 1),
@@ -39,8 +43,6 @@ var namedUserServingCertResourceNames = []string{
 	fmt.Sprintf(namedUserServingCertResourceNameFormat, 9),
 }
 
-var maxUserNamedCerts = len(namedUserServingCertResourceNames)
-
 // syncActionRules rules define source resource names indexed by destination resource names.
 // Empty value means to delete the destination.
 type syncActionRules map[string]string
@@ -93,12 +95,6 @@ func observeNamedCertificates(apiServer *configv1.APIServer, recorder events.Rec
 	observedConfig := map[string]interface{}{}
 
 	namedCertificates := apiServer.Spec.ServingCerts.NamedCertificates
-	if len(namedCertificates) > maxUserNamedCerts {
-		// TODO: This should be validation error, not operator error/event.
-		err := fmt.Errorf("spec.servingCerts.namedCertificates cannot have more than %d entries", maxUserNamedCerts)
-		recorder.Warningf("ObserveNamedCertificatesFailed", err.Error())
-		return previouslyObservedConfig, nil, append(errs, err)
-	}
 
 	// add the named cert info to the observed config. return the previously observed config on any error.
 	namedCertificatesPath := []string{"servingInfo", "namedCertificates"}
@@ -142,19 +138,18 @@ func observeNamedCertificates(apiServer *configv1.APIServer, recorder events.Rec
 			recorder.Warningf("ObserveNamedCertificatesFailed", err.Error())
 			return previouslyObservedConfig, nil, append(errs, err)
 		}
-		// pick one of the available target resource names
-		targetSecretName := fmt.Sprintf(namedUserServingCertResourceNameFormat, index)
-
-		// add a sync rule to copy the user specified secret to the operator namespace
-		resourceSyncRules[targetSecretName] = sourceSecretName
+		// keep the legacy target resource in sync
+		if index < len(namedUserServingCertResourceNames) {
+			resourceSyncRules[namedUserServingCertResourceNames[index]] = sourceSecretName
+		}
 
-		// add the named certificate to the observed config
-		certFile := fmt.Sprintf("/etc/kubernetes/static-pod-certs/secrets/%s/tls.crt", targetSecretName)
+		// add the named certificate to the observed config, the named certificates controller bundles the user specified secrets
+		certFile := fmt.Sprintf("/etc/kubernetes/static-pod-certs/secrets/%s/%s", namedcertificatescontroller.BundleSecretName, namedcertificatescontroller.CertFileName(sourceSecretName))
 		if err := unstructured.SetNestedField(observedNamedCertificate, certFile, "certFile"); err != nil {
 			return previouslyObservedConfig, nil, append(errs, err)
 		}
 
-		keyFile := fmt.Sprintf("/etc/kubernetes/static-pod-certs/secrets/%s/tls.key", targetSecretName)
+		keyFile := fmt.Sprintf("/etc/kubernetes/static-pod-certs/secrets/%s/%s", namedcertificatescontroller.BundleSecretName, namedcertificatescontroller.KeyFileName(sourceSecretName))
 		if err := unstructured.SetNestedField(observedNamedCertificate, keyFile, "keyFile"); err != nil {
 			return previouslyObservedConfig, nil, append(errs, err)
 		}
@@ -98,6 +98,40 @@ func TestObserveNamedCertificates(t *testing.T) {
 		},
 	}
 
+	// more named certificates than there are legacy slots
+	var manyCertificates []func(*configv1.APIServer)
+	manyNamedCertificates := []interface{}{
+		map[string]interface{}{
+			"certFile": "/etc/kubernetes/static-pod-certs/secrets/localhost-serving-cert-certkey/tls.crt",
+			"keyFile":  "/etc/kubernetes/static-pod-certs/secrets/localhost-serving-cert-certkey/tls.key",
+		},
+		map[string]interface{}{
+			"certFile": "/etc/kubernetes/static-pod-certs/secrets/service-network-serving-certkey/tls.crt",
+			"keyFile":  "/etc/kubernetes/static-pod-certs/secrets/service-network-serving-certkey/tls.key",
+		},
+		map[string]interface{}{
+			"certFile": "/etc/kubernetes/static-pod-certs/secrets/external-loadbalancer-serving-certkey/tls.crt",
+			"keyFile":  "/etc/kubernetes/static-pod-certs/secrets/external-loadbalancer-serving-certkey/tls.key",
+		},
+		map[string]interface{}{
+			"certFile": "/etc/kubernetes/static-pod-certs/secrets/internal-loadbalancer-serving-certkey/tls.crt",
+			"keyFile":  "/etc/kubernetes/static-pod-certs/secrets/internal-loadbalancer-serving-certkey/tls.key",
+		},
+		map[string]interface{}{
+			"certFile": "/etc/kubernetes/static-pod-resources/secrets/localhost-recovery-serving-certkey/tls.crt",
+			"keyFile":  "/etc/kubernetes/static-pod-resources/secrets/localhost-recovery-serving-certkey/tls.key",
+		},
+	}
+	for i := 0; i < 25; i++ {
+		name := fmt.Sprintf("cert-%03d", i)
+		manyCertificates = append(manyCertificates, withCertificate(withNames(name+".example.com"), withSecret(name)))
+		manyNamedCertificates = append(manyNamedCertificates, map[string]interface{}{
+			"certFile": "/etc/kubernetes/static-pod-certs/secrets/user-named-serving-certs/" + name + ".crt",
+			"keyFile":  "/etc/kubernetes/static-pod-certs/secrets/user-named-serving-certs/" + name + ".key",
+			"names":    []interface{}{name + ".example.com"},
+		})
+	}
+
 	testCases := []struct {
 		name           string
 		config         *configv1.APIServer
@@ -200,8 +234,8 @@ func TestObserveNamedCertificates(t *testing.T) {
 							"keyFile":  "/etc/kubernetes/static-pod-resources/secrets/localhost-recovery-serving-certkey/tls.key",
 						},
 						map[string]interface{}{
-							"certFile": "/etc/kubernetes/static-pod-certs/secrets/user-serving-cert-000/tls.crt",
-							"keyFile":  "/etc/kubernetes/static-pod-certs/secrets/user-serving-cert-000/tls.key",
+							"certFile": "/etc/kubernetes/static-pod-certs/secrets/user-named-serving-certs/foo.crt",
+							"keyFile":  "/etc/kubernetes/static-pod-certs/secrets/user-named-serving-certs/foo.key",
 							"names":    []interface{}{"*.foo.org"},
 						},
 					},
@@ -272,8 +306,8 @@ func TestObserveNamedCertificates(t *testing.T) {
 							"keyFile":  "/etc/kubernetes/static-pod-resources/secrets/localhost-recovery-serving-certkey/tls.key",
 						},
 						map[string]interface{}{
-							"certFile": "/etc/kubernetes/static-pod-certs/secrets/user-serving-cert-000/tls.crt",
-							"keyFile":  "/etc/kubernetes/static-pod-certs/secrets/user-serving-cert-000/tls.key",
+							"certFile": "/etc/kubernetes/static-pod-certs/secrets/user-named-serving-certs/foo.crt",
+							"keyFile":  "/etc/kubernetes/static-pod-certs/secrets/user-named-serving-certs/foo.key",
 						},
 					},
 				},
@@ -326,8 +360,8 @@ func TestObserveNamedCertificates(t *testing.T) {
 							"keyFile":  "/etc/kubernetes/static-pod-resources/secrets/localhost-recovery-serving-certkey/tls.key",
 						},
 						map[string]interface{}{
-							"certFile": "/etc/kubernetes/static-pod-certs/secrets/user-serving-cert-000/tls.crt",
-							"keyFile":  "/etc/kubernetes/static-pod-certs/secrets/user-serving-cert-000/tls.key",
+							"certFile": "/etc/kubernetes/static-pod-certs/secrets/user-named-serving-certs/foo.crt",
+							"keyFile":  "/etc/kubernetes/static-pod-certs/secrets/user-named-serving-certs/foo.key",
 							"names":    []interface{}{"*.foo.org", "foo.org", "*.bar.org"},
 						},
 					},
@@ -387,17 +421,17 @@ func TestObserveNamedCertificates(t *testing.T) {
 							"keyFile":  "/etc/kubernetes/static-pod-resources/secrets/localhost-recovery-serving-certkey/tls.key",
 						},
 						map[string]interface{}{
-							"certFile": "/etc/kubernetes/static-pod-certs/secrets/user-serving-cert-000/tls.crt",
-							"keyFile":  "/etc/kubernetes/static-pod-certs/secrets/user-serving-cert-000/tls.key",
+							"certFile": "/etc/kubernetes/static-pod-certs/secrets/user-named-serving-certs/one.crt",
+							"keyFile":  "/etc/kubernetes/static-pod-certs/secrets/user-named-serving-certs/one.key",
 							"names":    []interface{}{"one"},
 						},
 						map[string]interface{}{
-							"certFile": "/etc/kubernetes/static-pod-certs/secrets/user-serving-cert-001/tls.crt",
-							"keyFile":  "/etc/kubernetes/static-pod-certs/secrets/user-serving-cert-001/tls.key",
+							"certFile": "/etc/kubernetes/static-pod-certs/secrets/user-named-serving-certs/two.crt",
+							"keyFile":  "/etc/kubernetes/static-pod-certs/secrets/user-named-serving-certs/two.key",
 						},
 						map[string]interface{}{
-							"certFile": "/etc/kubernetes/static-pod-certs/secrets/user-serving-cert-002/tls.crt",
-							"keyFile":  "/etc/kubernetes/static-pod-certs/secrets/user-serving-cert-002/tls.key",
+							"certFile": "/etc/kubernetes/static-pod-certs/secrets/user-named-serving-certs/three.crt",
+							"keyFile":  "/etc/kubernetes/static-pod-certs/secrets/user-named-serving-certs/three.key",
 							"names":    []interface{}{"three", "tři"},
 						},
 					},
@@ -428,23 +462,26 @@ func TestObserveNamedCertificates(t *testing.T) {
 			expectErrs: true,
 		},
 		{
-			name: "TooManyNamedCertificates",
-			config: newAPIServerConfig(
-				withCertificate(withNames("000"), withSecret("000")),
-				withCertificate(withNames("001"), withSecret("001")),
-				withCertificate(withNames("002"), withSecret("002")),
-				withCertificate(withNames("003"), withSecret("003")),
-				withCertificate(withNames("004"), withSecret("004")),
-				withCertificate(withNames("005"), withSecret("005")),
-				withCertificate(withNames("006"), withSecret("006")),
-				withCertificate(withNames("007"), withSecret("007")),
-				withCertificate(withNames("008"), withSecret("008")),
-				withCertificate(withNames("009"), withSecret("009")),
-				withCertificate(withNames("010"), withSecret("010")),
-			),
-			existing:   existingConfig,
-			expected:   existingConfig,
-			expectErrs: true,
+			name:     "MoreNamedCertificatesThanLegacySlots",
+			config:   newAPIServerConfig(manyCertificates...),
+			existing: existingConfig,
+			expected: map[string]interface{}{
+				"servingInfo": map[string]interface{}{
+					"namedCertificates": manyNamedCertificates,
+				},
+			},
+			expectedSynced: map[string]string{
+				"secret/user-serving-cert-000.openshift-kube-apiserver": "secret/cert-000.openshift-config",
+				"secret/user-serving-cert-001.openshift-kube-apiserver": "secret/cert-001.openshift-config",
+				"secret/user-serving-cert-002.openshift-kube-apiserver": "secret/cert-002.openshift-config",
+				"secret/user-serving-cert-003.openshift-kube-apiserver": "secret/cert-003.openshift-config",
+				"secret/user-serving-cert-004.openshift-kube-apiserver": "secret/cert-004.openshift-config",
+				"secret/user-serving-cert-005.openshift-kube-apiserver": "secret/cert-005.openshift-config",
+				"secret/user-serving-cert-006.openshift-kube-apiserver": "secret/cert-006.openshift-config",
+				"secret/user-serving-cert-007.openshift-kube-apiserver": "secret/cert-007.openshift-config",
+				"secret/user-serving-cert-008.openshift-kube-apiserver": "secret/cert-008.openshift-config",
+				"secret/user-serving-cert-009.openshift-kube-apiserver": "secret/cert-009.openshift-config",
+			},
 		},
 	}
 	for _, tc := range testCases {
@@ -0,0 +1,135 @@
+package connectivitycheckcontroller
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+
+	"github.com/openshift/api/operatorcontrolplane/v1alpha1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/connectivitycheckcontroller"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/operatorclient"
+)
+
+const (
+	// BackendTargetLabel is set on the checks of webhook, APIService and kubelet backends to the kind of backend.
+	BackendTargetLabel = "kubeapiserver.operator.openshift.io/connectivity-check-backend"
+
+	webhookBackend    = "webhook"
+	apiServiceBackend = "apiservice"
+)
+
+// backendService is a service port kube-apiserver calls out to.
+type backendService struct {
+	namespace string
+	name      string
+	port      int32
+}
+
+func newBackendService(namespace, name string, port *int32) backendService {
+	// webhooks and APIServices default to port 443
+	backend := backendService{namespace: namespace, name: name, port: 443}
+	if port != nil {
+		backend.port = *port
+	}
+	return backend
+}
+
+func (b backendService) String() string {
+	return fmt.Sprintf("%s.%s:%d", b.name, b.namespace, b.port)
+}
+
+// getTemplatesForWebhookServices returns templates for the services of the mutating, validating and
+// CRD conversion webhooks.
+func (c *connectivityCheckTemplateProvider) getTemplatesForWebhookServices(syncContext factory.SyncContext) ([]*v1alpha1.PodNetworkConnectivityCheck, error) {
+	var backends []backendService
+	mutatingWebhookConfigurations, err := c.mutatingWebhookLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, configuration := range mutatingWebhookConfigurations {
+		for _, webhook := range configuration.Webhooks {
+			if service := webhook.ClientConfig.Service; service != nil {
+				backends = append(backends, newBackendService(service.Namespace, service.Name, service.Port))
+			}
+		}
+	}
+	validatingWebhookConfigurations, err := c.validatingWebhookLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, configuration := range validatingWebhookConfigurations {
+		for _, webhook := range configuration.Webhooks {
+			if service := webhook.ClientConfig.Service; service != nil {
+				backends = append(backends, newBackendService(service.Namespace, service.Name, service.Port))
+			}
+		}
+	}
+	crds, err := c.crdLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, crd := range crds {
+		conversion := crd.Spec.Conversion
+		if conversion == nil || conversion.Webhook == nil || conversion.Webhook.ClientConfig == nil {
+			continue
+		}
+		if service := conversion.Webhook.ClientConfig.Service; service != nil {
+			backends = append(backends, newBackendService(service.Namespace, service.Name, service.Port))
+		}
+	}
+	return c.getTemplatesForBackendServices(syncContext, webhookBackend, backends), nil
+}
+
+// getTemplatesForAPIServiceBackends returns templates for the services of the aggregated APIServices.
+// Local APIServices are served by kube-apiserver itself and have no backend to check.
+func (c *connectivityCheckTemplateProvider) getTemplatesForAPIServiceBackends(syncContext factory.SyncContext) ([]*v1alpha1.PodNetworkConnectivityCheck, error) {
+	apiServices, err := c.apiServiceLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	var backends []backendService
+	for _, apiService := range apiServices {
+		if service := apiService.Spec.Service; service != nil {
+			backends = append(backends, newBackendService(service.Namespace, service.Name, service.Port))
+		}
+	}
+	return c.getTemplatesForBackendServices(syncContext, apiServiceBackend, backends), nil
+}
+
+// getTemplatesForBackendServices returns a template per distinct backend service. The checks target the
+// cluster IP of the service because the check-endpoints container shares the host network of kube-apiserver
+// and cannot resolve service names.
+func (c *connectivityCheckTemplateProvider) getTemplatesForBackendServices(syncContext factory.SyncContext, kind string, backends []backendService) []*v1alpha1.PodNetworkConnectivityCheck {
+	sort.Slice(backends, func(i, j int) bool {
+		return backends[i].String() < backends[j].String()
+	})
+	var templates []*v1alpha1.PodNetworkConnectivityCheck
+	for i, backend := range backends {
+		if i > 0 && backends[i-1] == backend {
+			continue
+		}
+		service, err := c.allServicesLister.Services(backend.namespace).Get(backend.name)
+		if err != nil {
+			syncContext.Recorder().Warningf("EndpointDetectionFailure", "unable to find %s service %s: %v", kind, backend, err)
+			continue
+		}
+		if len(service.Spec.ClusterIP) == 0 || service.Spec.ClusterIP == corev1.ClusterIPNone {
+			syncContext.Recorder().Warningf("EndpointDetectionFailure", "%s service %s has no cluster IP", kind, backend)
+			continue
+		}
+		templates = append(templates, connectivitycheckcontroller.NewPodNetworkConnectivityCheckTemplate(
+			net.JoinHostPort(service.Spec.ClusterIP, strconv.Itoa(int(backend.port))),
+			operatorclient.TargetNamespace,
+			withTarget(kind, fmt.Sprintf("%s-%s-%d", backend.namespace, backend.name, backend.port)),
+			func(check *v1alpha1.PodNetworkConnectivityCheck) {
+				check.Labels = map[string]string{BackendTargetLabel: kind}
+			},
+		))
+	}
+	return templates
+}
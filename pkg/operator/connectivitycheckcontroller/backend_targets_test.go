@@ -0,0 +1,139 @@
+package connectivitycheckcontroller
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/openshift/api/operatorcontrolplane/v1alpha1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionslistersv1 "k8s.io/apiextensions-apiserver/pkg/client/listers/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	admissionregistrationlistersv1 "k8s.io/client-go/listers/admissionregistration/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
+	apiregistrationlistersv1 "k8s.io/kube-aggregator/pkg/client/listers/apiregistration/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestBackendTargets(t *testing.T) {
+	indexer := func(objs ...interface{}) cache.Indexer {
+		indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+		for _, obj := range objs {
+			if err := indexer.Add(obj); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return indexer
+	}
+	service := func(namespace, name, clusterIP string) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec:       corev1.ServiceSpec{ClusterIP: clusterIP},
+		}
+	}
+	webhookClientConfig := func(namespace, name string, port *int32) admissionregistrationv1.WebhookClientConfig {
+		return admissionregistrationv1.WebhookClientConfig{
+			Service: &admissionregistrationv1.ServiceReference{Namespace: namespace, Name: name, Port: port},
+		}
+	}
+
+	c := &connectivityCheckTemplateProvider{
+		mutatingWebhookLister: admissionregistrationlistersv1.NewMutatingWebhookConfigurationLister(indexer(
+			&admissionregistrationv1.MutatingWebhookConfiguration{
+				ObjectMeta: metav1.ObjectMeta{Name: "mutating"},
+				Webhooks: []admissionregistrationv1.MutatingWebhook{
+					{Name: "a", ClientConfig: webhookClientConfig("ns", "webhook", nil)},
+					// the same service is only checked once
+					{Name: "b", ClientConfig: webhookClientConfig("ns", "webhook", pointer.Int32(443))},
+					{Name: "url", ClientConfig: admissionregistrationv1.WebhookClientConfig{URL: pointer.String("https://example.com")}},
+				},
+			},
+		)),
+		validatingWebhookLister: admissionregistrationlistersv1.NewValidatingWebhookConfigurationLister(indexer(
+			&admissionregistrationv1.ValidatingWebhookConfiguration{
+				ObjectMeta: metav1.ObjectMeta{Name: "validating"},
+				Webhooks: []admissionregistrationv1.ValidatingWebhook{
+					{Name: "a", ClientConfig: webhookClientConfig("ns", "webhook", pointer.Int32(8443))},
+					{Name: "missing", ClientConfig: webhookClientConfig("ns", "missing", nil)},
+					{Name: "headless", ClientConfig: webhookClientConfig("ns", "headless", nil)},
+				},
+			},
+		)),
+		crdLister: apiextensionslistersv1.NewCustomResourceDefinitionLister(indexer(
+			&apiextensionsv1.CustomResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{Name: "widgets.example.com"},
+				Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+					Conversion: &apiextensionsv1.CustomResourceConversion{
+						Strategy: apiextensionsv1.WebhookConverter,
+						Webhook: &apiextensionsv1.WebhookConversion{
+							ClientConfig: &apiextensionsv1.WebhookClientConfig{
+								Service: &apiextensionsv1.ServiceReference{Namespace: "widgets", Name: "conversion", Port: pointer.Int32(9443)},
+							},
+						},
+					},
+				},
+			},
+			&apiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: "gadgets.example.com"}},
+		)),
+		apiServiceLister: apiregistrationlistersv1.NewAPIServiceLister(indexer(
+			&apiregistrationv1.APIService{
+				ObjectMeta: metav1.ObjectMeta{Name: "v1.apps.openshift.io"},
+				Spec: apiregistrationv1.APIServiceSpec{
+					Service: &apiregistrationv1.ServiceReference{Namespace: "openshift-apiserver", Name: "api", Port: pointer.Int32(443)},
+				},
+			},
+			&apiregistrationv1.APIService{
+				ObjectMeta: metav1.ObjectMeta{Name: "v1.build.openshift.io"},
+				Spec: apiregistrationv1.APIServiceSpec{
+					Service: &apiregistrationv1.ServiceReference{Namespace: "openshift-apiserver", Name: "api"},
+				},
+			},
+			// local
+			&apiregistrationv1.APIService{ObjectMeta: metav1.ObjectMeta{Name: "v1.apps"}},
+		)),
+		allServicesLister: corev1listers.NewServiceLister(indexer(
+			service("ns", "webhook", "172.30.0.10"),
+			service("ns", "headless", corev1.ClusterIPNone),
+			service("widgets", "conversion", "fd02::20"),
+			service("openshift-apiserver", "api", "172.30.0.30"),
+		)),
+	}
+	syncContext := factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))
+
+	webhookTemplates, err := c.getTemplatesForWebhookServices(syncContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectTargets(t, webhookTemplates, map[string]string{
+		"$(SOURCE)-to-webhook-ns-webhook-443":          "172.30.0.10:443",
+		"$(SOURCE)-to-webhook-ns-webhook-8443":         "172.30.0.10:8443",
+		"$(SOURCE)-to-webhook-widgets-conversion-9443": "[fd02::20]:9443",
+	}, webhookBackend)
+
+	apiServiceTemplates, err := c.getTemplatesForAPIServiceBackends(syncContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectTargets(t, apiServiceTemplates, map[string]string{
+		"$(SOURCE)-to-apiservice-openshift-apiserver-api-443": "172.30.0.30:443",
+	}, apiServiceBackend)
+}
+
+func expectTargets(t *testing.T, templates []*v1alpha1.PodNetworkConnectivityCheck, expected map[string]string, kind string) {
+	t.Helper()
+	actual := map[string]string{}
+	for _, template := range templates {
+		actual[template.Name] = template.Spec.TargetEndpoint
+		if template.Labels[BackendTargetLabel] != kind {
+			t.Errorf("expected %s to be labeled as a %s backend, got %v", template.Name, kind, template.Labels)
+		}
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected targets %v, got %v", expected, actual)
+	}
+}
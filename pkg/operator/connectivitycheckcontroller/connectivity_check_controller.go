@@ -5,13 +5,19 @@ import (
 	"fmt"
 	"net"
 	"net/url"
+	"reflect"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/ghodss/yaml"
 	"github.com/openshift/api/operatorcontrolplane/v1alpha1"
 	configinformers "github.com/openshift/client-go/config/informers/externalversions"
 	configv1listers "github.com/openshift/client-go/config/listers/config/v1"
 	operatorcontrolplaneclient "github.com/openshift/client-go/operatorcontrolplane/clientset/versioned"
+	operatorcontrolplaneinformersv1alpha1 "github.com/openshift/client-go/operatorcontrolplane/informers/externalversions/operatorcontrolplane/v1alpha1"
+	operatorcontrolplanelistersv1alpha1 "github.com/openshift/client-go/operatorcontrolplane/listers/operatorcontrolplane/v1alpha1"
+	checkendpoints "github.com/openshift/cluster-kube-apiserver-operator/pkg/cmd/checkendpoints/controller"
 	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/operatorclient"
 	"github.com/openshift/library-go/pkg/controller/factory"
 	"github.com/openshift/library-go/pkg/operator/connectivitycheckcontroller"
@@ -20,11 +26,15 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	apiextensionsinformers "k8s.io/apiextensions-apiserver/pkg/client/informers/externalversions"
+	apiextensionslistersv1 "k8s.io/apiextensions-apiserver/pkg/client/listers/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
-	"k8s.io/client-go/kubernetes"
+	admissionregistrationlistersv1 "k8s.io/client-go/listers/admissionregistration/v1"
 	corev1listers "k8s.io/client-go/listers/core/v1"
+	apiregistrationinformers "k8s.io/kube-aggregator/pkg/client/informers/externalversions"
+	apiregistrationlistersv1 "k8s.io/kube-aggregator/pkg/client/listers/apiregistration/v1"
 )
 
 type KubeAPIServerConnectivityCheckController interface {
@@ -32,15 +42,17 @@ type KubeAPIServerConnectivityCheckController interface {
 }
 
 func NewKubeAPIServerConnectivityCheckController(
-	kubeClient kubernetes.Interface,
 	operatorClient v1helpers.StaticPodOperatorClient,
 	apiextensionsClient *apiextensionsclient.Clientset,
 	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
 	operatorcontrolplaneClient *operatorcontrolplaneclient.Clientset,
+	checkInformer operatorcontrolplaneinformersv1alpha1.PodNetworkConnectivityCheckInformer,
 	configInformers configinformers.SharedInformerFactory,
 	apiextensionsInformers apiextensionsinformers.SharedInformerFactory,
+	apiregistrationInformers apiregistrationinformers.SharedInformerFactory,
 	recorder events.Recorder,
 ) KubeAPIServerConnectivityCheckController {
+	kubeInformersForAllNamespaces := kubeInformersForNamespaces.InformersFor("")
 	c := kubeAPIServerConnectivityCheckController{
 		ConnectivityCheckController: connectivitycheckcontroller.NewConnectivityCheckController(
 			operatorclient.TargetNamespace,
@@ -53,18 +65,35 @@ func NewKubeAPIServerConnectivityCheckController(
 				kubeInformersForNamespaces.InformersFor("openshift-apiserver").Core().V1().Endpoints().Informer(),
 				kubeInformersForNamespaces.InformersFor("openshift-apiserver").Core().V1().Services().Informer(),
 				configInformers.Config().V1().Infrastructures().Informer(),
+				kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().ConfigMaps().Informer(),
+				kubeInformersForAllNamespaces.Admissionregistration().V1().MutatingWebhookConfigurations().Informer(),
+				kubeInformersForAllNamespaces.Admissionregistration().V1().ValidatingWebhookConfigurations().Informer(),
+				kubeInformersForAllNamespaces.Core().V1().Services().Informer(),
+				kubeInformersForAllNamespaces.Core().V1().Nodes().Informer(),
+				apiextensionsInformers.Apiextensions().V1().CustomResourceDefinitions().Informer(),
+				apiregistrationInformers.Apiregistration().V1().APIServices().Informer(),
 			},
 			recorder,
 			false,
 		),
 	}
 	generator := &connectivityCheckTemplateProvider{
-		kubeClient:           kubeClient,
 		operatorClient:       operatorClient,
 		endpointsLister:      kubeInformersForNamespaces.InformersFor("openshift-apiserver").Core().V1().Endpoints().Lister(),
 		serviceLister:        kubeInformersForNamespaces.InformersFor("openshift-apiserver").Core().V1().Services().Lister(),
-		nodeLister:           kubeInformersForNamespaces.InformersFor("").Core().V1().Nodes().Lister(),
+		nodeLister:           kubeInformersForAllNamespaces.Core().V1().Nodes().Lister(),
 		infrastructureLister: configInformers.Config().V1().Infrastructures().Lister(),
+		configMapLister:      kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().ConfigMaps().Lister(),
+
+		mutatingWebhookLister:   kubeInformersForAllNamespaces.Admissionregistration().V1().MutatingWebhookConfigurations().Lister(),
+		validatingWebhookLister: kubeInformersForAllNamespaces.Admissionregistration().V1().ValidatingWebhookConfigurations().Lister(),
+		allServicesLister:       kubeInformersForAllNamespaces.Core().V1().Services().Lister(),
+		crdLister:               apiextensionsInformers.Apiextensions().V1().CustomResourceDefinitions().Lister(),
+		apiServiceLister:        apiregistrationInformers.Apiregistration().V1().APIServices().Lister(),
+
+		// not an informer of the controller, the checks change with every status update of check-endpoints
+		checkLister:                checkInformer.Lister().PodNetworkConnectivityChecks(operatorclient.TargetNamespace),
+		operatorcontrolplaneClient: operatorcontrolplaneClient,
 	}
 	return c.WithPodNetworkConnectivityCheckFn(generator.generate)
 }
@@ -74,12 +103,21 @@ type kubeAPIServerConnectivityCheckController struct {
 }
 
 type connectivityCheckTemplateProvider struct {
-	kubeClient           kubernetes.Interface
 	operatorClient       v1helpers.OperatorClient
 	endpointsLister      corev1listers.EndpointsLister
 	serviceLister        corev1listers.ServiceLister
 	nodeLister           corev1listers.NodeLister
 	infrastructureLister configv1listers.InfrastructureLister
+	configMapLister      corev1listers.ConfigMapLister
+
+	mutatingWebhookLister   admissionregistrationlistersv1.MutatingWebhookConfigurationLister
+	validatingWebhookLister admissionregistrationlistersv1.ValidatingWebhookConfigurationLister
+	allServicesLister       corev1listers.ServiceLister
+	crdLister               apiextensionslistersv1.CustomResourceDefinitionLister
+	apiServiceLister        apiregistrationlistersv1.APIServiceLister
+
+	checkLister                operatorcontrolplanelistersv1alpha1.PodNetworkConnectivityCheckNamespaceLister
+	operatorcontrolplaneClient operatorcontrolplaneclient.Interface
 }
 
 func (c *connectivityCheckTemplateProvider) generate(ctx context.Context, syncContext factory.SyncContext) ([]*v1alpha1.PodNetworkConnectivityCheck, error) {
@@ -112,16 +150,43 @@ func (c *connectivityCheckTemplateProvider) generate(ctx context.Context, syncCo
 	}
 	templates = append(templates, loadBalancerEndpoints...)
 
-	nodes, err := c.kubeClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{
-		LabelSelector: labels.Set{"node-role.kubernetes.io/master": ""}.AsSelector().String(),
-	})
+	// admin declared targets
+	userDefinedTargets, err := c.getTemplatesForUserDefinedTargets(syncContext)
+	if err != nil {
+		syncContext.Recorder().Warningf("EndpointDetectionFailure", "error reading user defined targets: %v", err)
+	}
+	templates = append(templates, userDefinedTargets...)
+
+	// webhook services
+	webhookServices, err := c.getTemplatesForWebhookServices(syncContext)
+	if err != nil {
+		syncContext.Recorder().Warningf("EndpointDetectionFailure", "error detecting webhook services: %v", err)
+	}
+	templates = append(templates, webhookServices...)
+
+	// aggregated apiservice backends
+	apiServiceBackends, err := c.getTemplatesForAPIServiceBackends(syncContext)
+	if err != nil {
+		syncContext.Recorder().Warningf("EndpointDetectionFailure", "error detecting apiservice backends: %v", err)
+	}
+	templates = append(templates, apiServiceBackends...)
+
+	// control plane and sampled worker kubelets
+	kubelets, err := c.getTemplatesForKubelets(syncContext)
+	if err != nil {
+		syncContext.Recorder().Warningf("EndpointDetectionFailure", "error detecting kubelet endpoints: %v", err)
+	}
+	templates = append(templates, kubelets...)
+
+	nodes, err := c.nodeLister.List(labels.Set{"node-role.kubernetes.io/master": ""}.AsSelector())
 	if err != nil {
 		return nil, fmt.Errorf("failed to list master nodes: %w", err)
 	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
 
 	// create each check per static pod
 	var checks []*v1alpha1.PodNetworkConnectivityCheck
-	for _, node := range nodes.Items {
+	for _, node := range nodes {
 		staticPodName := "kube-apiserver-" + node.Name
 		for _, template := range templates {
 			check := template.DeepCopy()
@@ -131,6 +196,13 @@ func (c *connectivityCheckTemplateProvider) generate(ctx context.Context, syncCo
 		}
 	}
 
+	// library-go only creates and updates checks, delete the generated checks of targets that are gone
+	for _, label := range []string{UserDefinedTargetLabel, BackendTargetLabel} {
+		if err := c.pruneGeneratedChecks(ctx, syncContext, label, checks); err != nil {
+			syncContext.Recorder().Warningf("EndpointDetectionFailure", "error pruning checks labeled %s: %v", label, err)
+		}
+	}
+
 	return checks, nil
 }
 
@@ -299,3 +371,41 @@ type endpointInfo struct {
 func withTarget(label, nodeName string) func(check *v1alpha1.PodNetworkConnectivityCheck) {
 	return connectivitycheckcontroller.WithTarget(label + "-" + nodeName)
 }
+
+// pruneGeneratedChecks deletes the checks with the given label that are no longer desired. Checks whose
+// probe annotations changed are deleted too, because existing checks only get their spec updated.
+func (c *connectivityCheckTemplateProvider) pruneGeneratedChecks(ctx context.Context, syncContext factory.SyncContext, label string, desired []*v1alpha1.PodNetworkConnectivityCheck) error {
+	desiredByName := map[string]*v1alpha1.PodNetworkConnectivityCheck{}
+	for _, check := range desired {
+		desiredByName[check.Name] = check
+	}
+	selector, err := labels.Parse(label)
+	if err != nil {
+		return err
+	}
+	existing, err := c.checkLister.List(selector)
+	if err != nil {
+		return err
+	}
+	checksClient := c.operatorcontrolplaneClient.ControlplaneV1alpha1().PodNetworkConnectivityChecks(operatorclient.TargetNamespace)
+	for _, check := range existing {
+		if want, ok := desiredByName[check.Name]; ok && reflect.DeepEqual(probeAnnotations(want.Annotations), probeAnnotations(check.Annotations)) {
+			continue
+		}
+		if err := checksClient.Delete(ctx, check.Name, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		syncContext.Recorder().Eventf("EndpointCheckDeleted", "Deleted podnetworkconnectivitycheck/%s because its target changed or was removed.", check.Name)
+	}
+	return nil
+}
+
+func probeAnnotations(annotations map[string]string) map[string]string {
+	result := map[string]string{}
+	for key, value := range annotations {
+		if strings.HasPrefix(key, checkendpoints.ProbeAnnotation) {
+			result[key] = value
+		}
+	}
+	return result
+}
@@ -0,0 +1,135 @@
+package connectivitycheckcontroller
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net"
+	"sort"
+	"strconv"
+
+	"github.com/openshift/api/operatorcontrolplane/v1alpha1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/connectivitycheckcontroller"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+
+	checkendpoints "github.com/openshift/cluster-kube-apiserver-operator/pkg/cmd/checkendpoints/controller"
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/operatorclient"
+)
+
+const (
+	// ConnectivityCheckConfigMapName is the configmap in the operator namespace tuning the generated checks.
+	ConnectivityCheckConfigMapName = "connectivity-check-config"
+	// KubeletWorkerSampleSizeKey is the number of worker kubelets checked in addition to the control plane
+	// kubelets. It defaults to DefaultKubeletWorkerSampleSize.
+	KubeletWorkerSampleSizeKey = "kubeletWorkerSampleSize"
+	// DefaultKubeletWorkerSampleSize keeps the number of checks bounded on large clusters.
+	DefaultKubeletWorkerSampleSize = 3
+
+	kubeletBackend = "kubelet"
+	masterNodeRole = "node-role.kubernetes.io/master"
+)
+
+// getTemplatesForKubelets returns templates for the kubelets of the control plane nodes and of a sample
+// of the worker nodes. The checks authenticate with the kubelet-client certificate and verify the serving
+// certificate of the kubelet like kube-apiserver does.
+func (c *connectivityCheckTemplateProvider) getTemplatesForKubelets(syncContext factory.SyncContext) ([]*v1alpha1.PodNetworkConnectivityCheck, error) {
+	sampleSize, err := c.kubeletWorkerSampleSize()
+	if err != nil {
+		syncContext.Recorder().Warningf("InvalidConnectivityCheckConfig", "configmap/%s: %v", ConnectivityCheckConfigMapName, err)
+		sampleSize = DefaultKubeletWorkerSampleSize
+	}
+	nodes, err := c.nodeLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	var templates []*v1alpha1.PodNetworkConnectivityCheck
+	for _, node := range sampleKubelets(nodes, sampleSize) {
+		address := nodeInternalIP(node)
+		if len(address) == 0 {
+			syncContext.Recorder().Warningf("EndpointDetectionFailure", "node/%s has no internal IP", node.Name)
+			continue
+		}
+		port := node.Status.DaemonEndpoints.KubeletEndpoint.Port
+		if port == 0 {
+			port = 10250
+		}
+		templates = append(templates, connectivitycheckcontroller.NewPodNetworkConnectivityCheckTemplate(
+			net.JoinHostPort(address, strconv.Itoa(int(port))),
+			operatorclient.TargetNamespace,
+			withTarget(kubeletBackend, node.Name),
+			connectivitycheckcontroller.WithTlsClientCert("kubelet-client"),
+			func(check *v1alpha1.PodNetworkConnectivityCheck) {
+				check.Labels = map[string]string{BackendTargetLabel: kubeletBackend}
+				check.Annotations = map[string]string{
+					checkendpoints.ProbeAnnotation:         string(checkendpoints.ProbeTLS),
+					checkendpoints.ProbeCABundleAnnotation: "kubelet-serving-ca",
+				}
+			},
+		))
+	}
+	return templates, nil
+}
+
+// kubeletWorkerSampleSize returns the number of worker kubelets to check.
+func (c *connectivityCheckTemplateProvider) kubeletWorkerSampleSize() (int, error) {
+	configMap, err := c.configMapLister.ConfigMaps(operatorclient.OperatorNamespace).Get(ConnectivityCheckConfigMapName)
+	if errors.IsNotFound(err) {
+		return DefaultKubeletWorkerSampleSize, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	value, ok := configMap.Data[KubeletWorkerSampleSizeKey]
+	if !ok {
+		return DefaultKubeletWorkerSampleSize, nil
+	}
+	sampleSize, err := strconv.Atoi(value)
+	if err != nil || sampleSize < 0 {
+		return 0, fmt.Errorf("invalid %s %q: must be a non-negative integer", KubeletWorkerSampleSizeKey, value)
+	}
+	return sampleSize, nil
+}
+
+// sampleKubelets returns all control plane nodes and up to sampleSize worker nodes. Workers are ranked by
+// a hash of their name so the sample is spread across machine pools and mostly stable as nodes come and go.
+func sampleKubelets(nodes []*corev1.Node, sampleSize int) []*corev1.Node {
+	var controlPlane, workers []*corev1.Node
+	for _, node := range nodes {
+		if _, ok := node.Labels[masterNodeRole]; ok {
+			controlPlane = append(controlPlane, node)
+		} else {
+			workers = append(workers, node)
+		}
+	}
+	sort.Slice(controlPlane, func(i, j int) bool {
+		return controlPlane[i].Name < controlPlane[j].Name
+	})
+	sort.Slice(workers, func(i, j int) bool {
+		hi, hj := nodeNameHash(workers[i].Name), nodeNameHash(workers[j].Name)
+		if hi != hj {
+			return hi < hj
+		}
+		return workers[i].Name < workers[j].Name
+	})
+	if len(workers) > sampleSize {
+		workers = workers[:sampleSize]
+	}
+	return append(controlPlane, workers...)
+}
+
+func nodeNameHash(name string) uint32 {
+	hash := fnv.New32a()
+	hash.Write([]byte(name))
+	return hash.Sum32()
+}
+
+func nodeInternalIP(node *corev1.Node) string {
+	for _, address := range node.Status.Addresses {
+		if address.Type == corev1.NodeInternalIP {
+			return address.Address
+		}
+	}
+	return ""
+}
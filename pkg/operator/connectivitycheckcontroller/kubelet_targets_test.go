@@ -0,0 +1,126 @@
+package connectivitycheckcontroller
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	checkendpoints "github.com/openshift/cluster-kube-apiserver-operator/pkg/cmd/checkendpoints/controller"
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/operatorclient"
+)
+
+func TestKubeletTargets(t *testing.T) {
+	node := func(name string, master bool, address string) *corev1.Node {
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{}}}
+		if master {
+			node.Labels[masterNodeRole] = ""
+		}
+		if len(address) > 0 {
+			node.Status.Addresses = []corev1.NodeAddress{
+				{Type: corev1.NodeHostName, Address: name},
+				{Type: corev1.NodeInternalIP, Address: address},
+			}
+		}
+		return node
+	}
+	nodes := []*corev1.Node{
+		node("master-0", true, "10.0.0.1"),
+		node("master-1", true, "10.0.0.2"),
+		node("master-2", true, ""),
+	}
+	for i := 0; i < 10; i++ {
+		nodes = append(nodes, node(fmt.Sprintf("worker-%d", i), false, fmt.Sprintf("10.0.1.%d", i)))
+	}
+	nodes[1].Status.DaemonEndpoints.KubeletEndpoint.Port = 10251
+
+	testCases := []struct {
+		name            string
+		data            map[string]string
+		expectedWorkers int
+	}{
+		{name: "Default", expectedWorkers: DefaultKubeletWorkerSampleSize},
+		{name: "Configured", data: map[string]string{KubeletWorkerSampleSizeKey: "5"}, expectedWorkers: 5},
+		{name: "NoWorkers", data: map[string]string{KubeletWorkerSampleSizeKey: "0"}, expectedWorkers: 0},
+		{name: "AllWorkers", data: map[string]string{KubeletWorkerSampleSizeKey: "100"}, expectedWorkers: 10},
+		{name: "Invalid", data: map[string]string{KubeletWorkerSampleSizeKey: "-1"}, expectedWorkers: DefaultKubeletWorkerSampleSize},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			nodeIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			for _, node := range nodes {
+				if err := nodeIndexer.Add(node); err != nil {
+					t.Fatal(err)
+				}
+			}
+			configMapIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if tc.data != nil {
+				if err := configMapIndexer.Add(&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: ConnectivityCheckConfigMapName},
+					Data:       tc.data,
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			c := &connectivityCheckTemplateProvider{
+				nodeLister:      corev1listers.NewNodeLister(nodeIndexer),
+				configMapLister: corev1listers.NewConfigMapLister(configMapIndexer),
+			}
+			templates, err := c.getTemplatesForKubelets(factory.NewSyncContext("test", events.NewInMemoryRecorder("test")))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			targets := map[string]string{}
+			for _, template := range templates {
+				targets[template.Name] = template.Spec.TargetEndpoint
+				if template.Spec.TLSClientCert.Name != "kubelet-client" {
+					t.Errorf("expected %s to use the kubelet-client certificate, got %q", template.Name, template.Spec.TLSClientCert.Name)
+				}
+				if template.Annotations[checkendpoints.ProbeAnnotation] != string(checkendpoints.ProbeTLS) || template.Labels[BackendTargetLabel] != kubeletBackend {
+					t.Errorf("unexpected metadata of %s: %#v", template.Name, template.ObjectMeta)
+				}
+			}
+			// master-2 has no internal IP and is skipped
+			if len(templates) != 2+tc.expectedWorkers {
+				t.Errorf("expected checks of 2 control plane and %d worker kubelets, got %v", tc.expectedWorkers, targets)
+			}
+			if targets["$(SOURCE)-to-kubelet-master-0"] != "10.0.0.1:10250" || targets["$(SOURCE)-to-kubelet-master-1"] != "10.0.0.2:10251" {
+				t.Errorf("expected checks of the control plane kubelets, got %v", targets)
+			}
+		})
+	}
+}
+
+func TestSampleKubeletsIsStable(t *testing.T) {
+	var nodes []*corev1.Node
+	for i := 0; i < 20; i++ {
+		nodes = append(nodes, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("worker-%d", i)}})
+	}
+	sample := sampleKubelets(nodes, 3)
+	reversed := make([]*corev1.Node, len(nodes))
+	for i, node := range nodes {
+		reversed[len(nodes)-1-i] = node
+	}
+	if actual := sampleKubelets(reversed, 3); !reflect.DeepEqual(actual, sample) {
+		t.Errorf("expected the sample not to depend on the order of the nodes, got %v and %v", sample, actual)
+	}
+	// removing nodes outside of the sample does not change it
+	var remaining []*corev1.Node
+	for _, node := range nodes {
+		for _, sampled := range sample {
+			if node == sampled {
+				remaining = append(remaining, node)
+			}
+		}
+	}
+	if actual := sampleKubelets(remaining, 3); !reflect.DeepEqual(actual, sample) {
+		t.Errorf("expected the sample to be stable, got %v instead of %v", actual, sample)
+	}
+}
@@ -0,0 +1,129 @@
+package connectivitycheckcontroller
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+
+	"github.com/openshift/api/operatorcontrolplane/v1alpha1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/connectivitycheckcontroller"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
+
+	checkendpoints "github.com/openshift/cluster-kube-apiserver-operator/pkg/cmd/checkendpoints/controller"
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/operatorclient"
+)
+
+const (
+	// UserDefinedTargetsConfigMapName is the configmap in the operator namespace declaring additional
+	// targets checked from every kube-apiserver pod. Each key is the name of a target and each value
+	// a YAML encoded userDefinedTarget, e.g.
+	//
+	//   oidc: |
+	//     endpoint: oidc.example.com:443
+	//     probe: https
+	//     httpPath: /.well-known/openid-configuration
+	UserDefinedTargetsConfigMapName = "connectivity-check-targets"
+	// UserDefinedTargetLabel is set on the checks of user defined targets to the name of the target.
+	UserDefinedTargetLabel = "kubeapiserver.operator.openshift.io/connectivity-check-target"
+)
+
+// userDefinedTarget is an additional target declared by an admin.
+type userDefinedTarget struct {
+	// Endpoint is the host:port to check.
+	Endpoint string `json:"endpoint"`
+	// Probe is one of the check-endpoints probe types, tcp by default.
+	Probe string `json:"probe,omitempty"`
+	// CABundle names a configmap in the openshift-kube-apiserver namespace whose ca-bundle.crt key
+	// verifies the serving certificate of the target.
+	CABundle       string `json:"caBundle,omitempty"`
+	ServerName     string `json:"serverName,omitempty"`
+	HTTPPath       string `json:"httpPath,omitempty"`
+	ExpectedStatus int    `json:"expectedStatus,omitempty"`
+	GRPCService    string `json:"grpcService,omitempty"`
+}
+
+// annotations returns the check-endpoints probe annotations of the target.
+func (t *userDefinedTarget) annotations() map[string]string {
+	annotations := map[string]string{}
+	set := func(key, value string) {
+		if len(value) > 0 {
+			annotations[key] = value
+		}
+	}
+	set(checkendpoints.ProbeAnnotation, t.Probe)
+	set(checkendpoints.ProbeCABundleAnnotation, t.CABundle)
+	set(checkendpoints.ProbeServerNameAnnotation, t.ServerName)
+	set(checkendpoints.ProbeHTTPPathAnnotation, t.HTTPPath)
+	if t.ExpectedStatus != 0 {
+		set(checkendpoints.ProbeHTTPExpectedStatusAnnotation, strconv.Itoa(t.ExpectedStatus))
+	}
+	set(checkendpoints.ProbeGRPCServiceAnnotation, t.GRPCService)
+	if len(annotations) == 0 {
+		return nil
+	}
+	return annotations
+}
+
+// parseUserDefinedTarget returns the target declared under the given configmap key.
+func parseUserDefinedTarget(name, data string) (*userDefinedTarget, error) {
+	if errs := validation.IsDNS1123Label(name); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid target name %q: %v", name, errs)
+	}
+	target := &userDefinedTarget{}
+	if err := yaml.UnmarshalStrict([]byte(data), target); err != nil {
+		return nil, fmt.Errorf("invalid target %q: %w", name, err)
+	}
+	host, port, err := net.SplitHostPort(target.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint of target %q: %w", name, err)
+	}
+	if _, err := strconv.ParseUint(port, 10, 16); err != nil || len(host) == 0 {
+		return nil, fmt.Errorf("invalid endpoint of target %q: %q is not a host:port", name, target.Endpoint)
+	}
+	if err := checkendpoints.ValidateProbeAnnotations(target.annotations()); err != nil {
+		return nil, fmt.Errorf("invalid probe of target %q: %w", name, err)
+	}
+	return target, nil
+}
+
+// getTemplatesForUserDefinedTargets returns templates for the valid targets declared in the
+// connectivity-check-targets configmap. Invalid targets are reported and skipped.
+func (c *connectivityCheckTemplateProvider) getTemplatesForUserDefinedTargets(syncContext factory.SyncContext) ([]*v1alpha1.PodNetworkConnectivityCheck, error) {
+	configMap, err := c.configMapLister.ConfigMaps(operatorclient.OperatorNamespace).Get(UserDefinedTargetsConfigMapName)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for name := range configMap.Data {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var templates []*v1alpha1.PodNetworkConnectivityCheck
+	for _, name := range names {
+		target, err := parseUserDefinedTarget(name, configMap.Data[name])
+		if err != nil {
+			syncContext.Recorder().Warningf("InvalidConnectivityCheckTarget", "configmap/%s: %v", UserDefinedTargetsConfigMapName, err)
+			continue
+		}
+		name := name
+		templates = append(templates, connectivitycheckcontroller.NewPodNetworkConnectivityCheckTemplate(
+			target.Endpoint,
+			operatorclient.TargetNamespace,
+			withTarget("user-defined", name),
+			func(check *v1alpha1.PodNetworkConnectivityCheck) {
+				check.Labels = map[string]string{UserDefinedTargetLabel: name}
+				check.Annotations = target.annotations()
+			},
+		))
+	}
+	return templates, nil
+}
@@ -0,0 +1,175 @@
+package connectivitycheckcontroller
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/openshift/api/operatorcontrolplane/v1alpha1"
+	operatorcontrolplanefake "github.com/openshift/client-go/operatorcontrolplane/clientset/versioned/fake"
+	operatorcontrolplanelistersv1alpha1 "github.com/openshift/client-go/operatorcontrolplane/listers/operatorcontrolplane/v1alpha1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	checkendpoints "github.com/openshift/cluster-kube-apiserver-operator/pkg/cmd/checkendpoints/controller"
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/operatorclient"
+)
+
+func TestParseUserDefinedTarget(t *testing.T) {
+	testCases := []struct {
+		name                string
+		targetName          string
+		data                string
+		expectedAnnotations map[string]string
+		expectedErr         string
+	}{
+		{
+			name:       "TCP",
+			targetName: "webhook-authenticator",
+			data:       "endpoint: 10.0.0.1:8443",
+		},
+		{
+			name:       "HTTPS",
+			targetName: "oidc",
+			data:       "endpoint: oidc.example.com:443\nprobe: https\ncaBundle: oidc-ca\nhttpPath: /healthz\nexpectedStatus: 204\n",
+			expectedAnnotations: map[string]string{
+				checkendpoints.ProbeAnnotation:                   "https",
+				checkendpoints.ProbeCABundleAnnotation:           "oidc-ca",
+				checkendpoints.ProbeHTTPPathAnnotation:           "/healthz",
+				checkendpoints.ProbeHTTPExpectedStatusAnnotation: "204",
+			},
+		},
+		{
+			name:        "InvalidName",
+			targetName:  "OIDC",
+			data:        "endpoint: oidc.example.com:443",
+			expectedErr: "invalid target name",
+		},
+		{
+			name:        "UnknownField",
+			targetName:  "oidc",
+			data:        "endpoint: oidc.example.com:443\nport: 443",
+			expectedErr: "unknown field",
+		},
+		{
+			name:        "MissingPort",
+			targetName:  "oidc",
+			data:        "endpoint: oidc.example.com",
+			expectedErr: "invalid endpoint",
+		},
+		{
+			name:        "URL",
+			targetName:  "oidc",
+			data:        "endpoint: https://oidc.example.com:443/",
+			expectedErr: "invalid endpoint",
+		},
+		{
+			name:        "UnknownProbe",
+			targetName:  "oidc",
+			data:        "endpoint: oidc.example.com:443\nprobe: icmp",
+			expectedErr: "invalid probe",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			target, err := parseUserDefinedTarget(tc.targetName, tc.data)
+			if len(tc.expectedErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedErr) {
+					t.Fatalf("expected error containing %q, got %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual := target.annotations(); !reflect.DeepEqual(actual, tc.expectedAnnotations) {
+				t.Errorf("expected annotations %v, got %v", tc.expectedAnnotations, actual)
+			}
+		})
+	}
+}
+
+func TestUserDefinedTargets(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	if err := indexer.Add(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: UserDefinedTargetsConfigMapName},
+		Data: map[string]string{
+			"oidc":    "endpoint: oidc.example.com:443\nprobe: tls",
+			"webhook": "endpoint: 10.0.0.1:8443",
+			"invalid": "endpoint: nowhere",
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	userDefinedCheck := func(name string, annotations map[string]string) *v1alpha1.PodNetworkConnectivityCheck {
+		return &v1alpha1.PodNetworkConnectivityCheck{ObjectMeta: metav1.ObjectMeta{
+			Namespace:   operatorclient.TargetNamespace,
+			Name:        "kube-apiserver-master-0-to-user-defined-" + name,
+			Labels:      map[string]string{UserDefinedTargetLabel: name},
+			Annotations: annotations,
+		}}
+	}
+	existing := []*v1alpha1.PodNetworkConnectivityCheck{
+		// up to date
+		userDefinedCheck("oidc", map[string]string{checkendpoints.ProbeAnnotation: "tls"}),
+		// probe changed
+		userDefinedCheck("webhook", map[string]string{checkendpoints.ProbeAnnotation: "https"}),
+		// target removed
+		userDefinedCheck("removed", nil),
+		// generated for another kind of target
+		{ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "kube-apiserver-master-0-to-etcd"}},
+	}
+	client := operatorcontrolplanefake.NewSimpleClientset()
+	checkIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, check := range existing {
+		if _, err := client.ControlplaneV1alpha1().PodNetworkConnectivityChecks(check.Namespace).Create(context.TODO(), check, metav1.CreateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		if err := checkIndexer.Add(check); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c := &connectivityCheckTemplateProvider{
+		configMapLister:            corev1listers.NewConfigMapLister(indexer),
+		checkLister:                operatorcontrolplanelistersv1alpha1.NewPodNetworkConnectivityCheckLister(checkIndexer).PodNetworkConnectivityChecks(operatorclient.TargetNamespace),
+		operatorcontrolplaneClient: client,
+	}
+	syncContext := factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))
+
+	templates, err := c.getTemplatesForUserDefinedTargets(syncContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var checks []*v1alpha1.PodNetworkConnectivityCheck
+	var endpoints []string
+	for _, template := range templates {
+		check := template.DeepCopy()
+		check.Name = strings.Replace(check.Name, "$(SOURCE)", "kube-apiserver-master-0", 1)
+		checks = append(checks, check)
+		endpoints = append(endpoints, check.Spec.TargetEndpoint)
+	}
+	if expected := []string{"oidc.example.com:443", "10.0.0.1:8443"}; !reflect.DeepEqual(endpoints, expected) {
+		t.Fatalf("expected checks of %v, got %v", expected, endpoints)
+	}
+
+	if err := c.pruneGeneratedChecks(context.TODO(), syncContext, UserDefinedTargetLabel, checks); err != nil {
+		t.Fatal(err)
+	}
+	remaining, err := client.ControlplaneV1alpha1().PodNetworkConnectivityChecks(operatorclient.TargetNamespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, check := range remaining.Items {
+		names = append(names, check.Name)
+	}
+	if expected := []string{"kube-apiserver-master-0-to-etcd", "kube-apiserver-master-0-to-user-defined-oidc"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected remaining checks %v, got %v", expected, names)
+	}
+}
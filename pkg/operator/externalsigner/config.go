@@ -0,0 +1,92 @@
+package externalsigner
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/operatorclient"
+)
+
+const (
+	// ConfigMapName is an optional configmap in the operator namespace that makes the operator sign the certificates it
+	// rotates with an external signer instead of the in-cluster signers. Its url key holds the https URL of the signer,
+	// its optional ca-bundle.crt key the CA bundle to verify the signer with.
+	ConfigMapName = "external-signer-config"
+	// ClientCertSecretName is an optional TLS secret in the operator namespace with the client certificate presented to
+	// the external signer.
+	ClientCertSecretName = "external-signer-client-cert"
+
+	urlConfigKey      = "url"
+	caBundleConfigKey = "ca-bundle.crt"
+)
+
+// Config is how to reach the external signer.
+type Config struct {
+	URL        string
+	CABundle   []byte
+	ClientCert []byte
+	ClientKey  []byte
+}
+
+// GetConfig reads the external signer config. It returns nil if no external signer is configured.
+func GetConfig(ctx context.Context, client kubernetes.Interface) (*Config, error) {
+	var configMap *corev1.ConfigMap
+	var secret *corev1.Secret
+	err := wait.PollImmediate(time.Second, 1*time.Minute, func() (bool, error) {
+		var err error
+		configMap, err = client.CoreV1().ConfigMaps(operatorclient.OperatorNamespace).Get(ctx, ConfigMapName, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			configMap = nil
+		} else if err != nil {
+			klog.V(2).Infof("Failed to get configmap %s/%s: %v", operatorclient.OperatorNamespace, ConfigMapName, err)
+			return false, nil
+		}
+		secret, err = client.CoreV1().Secrets(operatorclient.OperatorNamespace).Get(ctx, ClientCertSecretName, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			secret = nil
+		} else if err != nil {
+			klog.V(2).Infof("Failed to get secret %s/%s: %v", operatorclient.OperatorNamespace, ClientCertSecretName, err)
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the external signer config: %v", err)
+	}
+	return ConfigFrom(configMap, secret)
+}
+
+// ConfigFrom returns the config held by the given configmap and client certificate secret, either may be nil. It
+// returns nil if the configmap is missing and an error if a signer cannot be created for the config.
+func ConfigFrom(configMap *corev1.ConfigMap, secret *corev1.Secret) (*Config, error) {
+	if configMap == nil {
+		return nil, nil
+	}
+	config := &Config{
+		URL:      configMap.Data[urlConfigKey],
+		CABundle: []byte(configMap.Data[caBundleConfigKey]),
+	}
+	if len(config.URL) == 0 {
+		return nil, fmt.Errorf("invalid configmap %s/%s: %s is required", operatorclient.OperatorNamespace, ConfigMapName, urlConfigKey)
+	}
+	if secret != nil {
+		config.ClientCert = secret.Data[corev1.TLSCertKey]
+		config.ClientKey = secret.Data[corev1.TLSPrivateKeyKey]
+		if len(config.ClientCert) == 0 || len(config.ClientKey) == 0 {
+			return nil, fmt.Errorf("invalid secret %s/%s: %s and %s are required", operatorclient.OperatorNamespace, ClientCertSecretName, corev1.TLSCertKey, corev1.TLSPrivateKeyKey)
+		}
+	}
+	// the signer validates the url, the CA bundle and the client certificate
+	if _, err := NewHTTPSigner(config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
@@ -0,0 +1,103 @@
+package externalsigner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/operatorclient"
+)
+
+// ConfigDegradedConditionType reports an invalid external signer config. Its message tells which signers are in use.
+const ConfigDegradedConditionType = "ExternalSignerConfigDegraded"
+
+// configController restarts the operator when the external signer config changes. The signer is handed to the cert
+// rotation controllers when they are created and cannot be replaced afterwards.
+type configController struct {
+	operatorClient  v1helpers.StaticPodOperatorClient
+	configMapLister corev1listers.ConfigMapLister
+	secretLister    corev1listers.SecretLister
+
+	// running is the config the cert rotation controllers use, nil if they use the in-cluster signers.
+	running *Config
+	exit    func()
+}
+
+// NewConfigController reports an invalid external signer config in the operator status and restarts the operator when
+// the configured external signer differs from the running one.
+func NewConfigController(
+	running *Config,
+	operatorClient v1helpers.StaticPodOperatorClient,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	operatorInformers := kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace)
+	c := &configController{
+		operatorClient:  operatorClient,
+		configMapLister: operatorInformers.Core().V1().ConfigMaps().Lister(),
+		secretLister:    operatorInformers.Core().V1().Secrets().Lister(),
+		running:         running,
+		exit:            func() { os.Exit(0) },
+	}
+
+	return factory.New().WithInformers(
+		operatorInformers.Core().V1().ConfigMaps().Informer(),
+		operatorInformers.Core().V1().Secrets().Informer(),
+		operatorClient.Informer(),
+	).ResyncEvery(time.Minute).WithSync(c.sync).ToController("ExternalSignerConfigController", eventRecorder)
+}
+
+func (c *configController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	configMap, err := c.configMapLister.ConfigMaps(operatorclient.OperatorNamespace).Get(ConfigMapName)
+	if errors.IsNotFound(err) {
+		configMap = nil
+	} else if err != nil {
+		return err
+	}
+	secret, err := c.secretLister.Secrets(operatorclient.OperatorNamespace).Get(ClientCertSecretName)
+	if errors.IsNotFound(err) {
+		secret = nil
+	} else if err != nil {
+		return err
+	}
+
+	condition := operatorv1.OperatorCondition{
+		Type:    ConfigDegradedConditionType,
+		Status:  operatorv1.ConditionFalse,
+		Reason:  "AsExpected",
+		Message: fmt.Sprintf("Signing %s.", describe(c.running)),
+	}
+	configured, err := ConfigFrom(configMap, secret)
+	if err != nil {
+		// keep signing with the running config until the config is fixed
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "InvalidConfig"
+		condition.Message = fmt.Sprintf("%v. Signing %s.", err, describe(c.running))
+	} else if !reflect.DeepEqual(configured, c.running) {
+		syncCtx.Recorder().Eventf("ExternalSignerConfigChanged", "Restarting to sign %s", describe(configured))
+		klog.Infof("Restarting to sign %s", describe(configured))
+		c.exit()
+		return nil
+	}
+
+	_, _, err = v1helpers.UpdateStaticPodStatus(ctx, c.operatorClient, v1helpers.UpdateStaticPodConditionFn(condition))
+	return err
+}
+
+func describe(config *Config) string {
+	if config == nil {
+		return "with the in-cluster signers"
+	}
+	return "with the external signer at " + config.URL
+}
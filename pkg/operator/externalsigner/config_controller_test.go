@@ -0,0 +1,113 @@
+package externalsigner
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/operatorclient"
+)
+
+func TestConfigController(t *testing.T) {
+	testCases := []struct {
+		name            string
+		data            map[string]string
+		running         *Config
+		expectedStatus  operatorv1.ConditionStatus
+		expectedMessage string
+		expectedExit    bool
+	}{
+		{
+			name:            "NotConfigured",
+			expectedStatus:  operatorv1.ConditionFalse,
+			expectedMessage: "Signing with the in-cluster signers.",
+		},
+		{
+			name:            "Unchanged",
+			data:            map[string]string{"url": "https://signer.example.com/sign"},
+			running:         &Config{URL: "https://signer.example.com/sign", CABundle: []byte{}},
+			expectedStatus:  operatorv1.ConditionFalse,
+			expectedMessage: "Signing with the external signer at https://signer.example.com/sign.",
+		},
+		{
+			name:            "NotHTTPS",
+			data:            map[string]string{"url": "http://signer.example.com/sign"},
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedMessage: `invalid external signer url "http://signer.example.com/sign": must be https. Signing with the in-cluster signers.`,
+		},
+		{
+			name:            "InvalidCABundle",
+			data:            map[string]string{"url": "https://signer.example.com/sign", "ca-bundle.crt": "garbage"},
+			running:         &Config{URL: "https://signer.example.com/sign", CABundle: []byte{}},
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedMessage: "invalid external signer CA bundle: no certificates found. Signing with the external signer at https://signer.example.com/sign.",
+		},
+		{
+			name:         "Configured",
+			data:         map[string]string{"url": "https://signer.example.com/sign"},
+			expectedExit: true,
+		},
+		{
+			name:         "Removed",
+			running:      &Config{URL: "https://signer.example.com/sign", CABundle: []byte{}},
+			expectedExit: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			if tc.data != nil {
+				if err := indexer.Add(&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: ConfigMapName},
+					Data:       tc.data,
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			operatorClient := v1helpers.NewFakeStaticPodOperatorClient(&operatorv1.StaticPodOperatorSpec{}, &operatorv1.StaticPodOperatorStatus{}, nil, nil)
+			exited := false
+			c := &configController{
+				operatorClient:  operatorClient,
+				configMapLister: corev1listers.NewConfigMapLister(indexer),
+				secretLister:    corev1listers.NewSecretLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})),
+				running:         tc.running,
+				exit:            func() { exited = true },
+			}
+
+			if err := c.sync(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))); err != nil {
+				t.Fatal(err)
+			}
+			if exited != tc.expectedExit {
+				t.Fatalf("expected exit %v, got %v", tc.expectedExit, exited)
+			}
+			if tc.expectedExit {
+				return
+			}
+
+			_, status, _, err := operatorClient.GetStaticPodOperatorState()
+			if err != nil {
+				t.Fatal(err)
+			}
+			condition := v1helpers.FindOperatorCondition(status.Conditions, ConfigDegradedConditionType)
+			if condition == nil {
+				t.Fatalf("expected condition %s to be set", ConfigDegradedConditionType)
+			}
+			if condition.Status != tc.expectedStatus {
+				t.Errorf("expected status %s, got %s: %s", tc.expectedStatus, condition.Status, condition.Message)
+			}
+			if !strings.Contains(condition.Message, tc.expectedMessage) {
+				t.Errorf("expected message %q, got %q", tc.expectedMessage, condition.Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package externalsigner
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"k8s.io/client-go/util/cert"
+)
+
+// httpRequest is the body POSTed to the signer.
+type httpRequest struct {
+	CSR             string `json:"csr"`
+	Usage           Usage  `json:"usage"`
+	ValiditySeconds int64  `json:"validitySeconds"`
+}
+
+// httpResponse is the body the signer answers with.
+type httpResponse struct {
+	// Certificate is the PEM encoded issued certificate followed by its intermediates.
+	Certificate string `json:"certificate"`
+	// CABundle is the PEM encoded CA certificates to verify the issued certificate with.
+	CABundle string `json:"caBundle"`
+}
+
+// HTTPSigner speaks a simple JSON protocol with a signer over HTTPS: the CSR, usage and validity are POSTed to the URL
+// and a 200 response carries the PEM encoded certificate chain and CA bundle.
+type HTTPSigner struct {
+	url    string
+	client *http.Client
+}
+
+var _ Signer = &HTTPSigner{}
+
+// NewHTTPSigner returns a signer for the given config. The signer's serving certificate is verified with the CA bundle
+// of the config, or with the system roots if it has none.
+func NewHTTPSigner(config *Config) (*HTTPSigner, error) {
+	u, err := url.Parse(config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid external signer url %q: %v", config.URL, err)
+	}
+	if u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid external signer url %q: must be https", config.URL)
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if len(config.CABundle) > 0 {
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(config.CABundle) {
+			return nil, fmt.Errorf("invalid external signer CA bundle: no certificates found")
+		}
+	}
+	if len(config.ClientCert) > 0 || len(config.ClientKey) > 0 {
+		clientCert, err := tls.X509KeyPair(config.ClientCert, config.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid external signer client certificate: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{clientCert}
+	}
+
+	return &HTTPSigner{
+		url: config.URL,
+		client: &http.Client{
+			Transport: &http.Transport{TLSClientConfig: tlsConfig, Proxy: http.ProxyFromEnvironment},
+			Timeout:   30 * time.Second,
+		},
+	}, nil
+}
+
+func (s *HTTPSigner) Sign(ctx context.Context, request Request) (*Response, error) {
+	body, err := json.Marshal(httpRequest{
+		CSR:             string(request.CSR),
+		Usage:           request.Usage,
+		ValiditySeconds: int64(request.Validity / time.Second),
+	})
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	httpResp, err := s.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("external signer request failed: %v", err)
+	}
+	defer httpResp.Body.Close()
+	respBody, err := ioutil.ReadAll(io.LimitReader(httpResp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read external signer response: %v", err)
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("external signer returned %s: %s", httpResp.Status, bytes.TrimSpace(respBody))
+	}
+
+	resp := &httpResponse{}
+	if err := json.Unmarshal(respBody, resp); err != nil {
+		return nil, fmt.Errorf("invalid external signer response: %v", err)
+	}
+	certificates, err := cert.ParseCertsPEM([]byte(resp.Certificate))
+	if err != nil {
+		return nil, fmt.Errorf("invalid certificate in external signer response: %v", err)
+	}
+	caBundle, err := cert.ParseCertsPEM([]byte(resp.CABundle))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CA bundle in external signer response: %v", err)
+	}
+	return &Response{Certificates: certificates, CABundle: caBundle}, nil
+}
@@ -0,0 +1,167 @@
+package externalsigner
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/authentication/user"
+
+	"github.com/openshift/library-go/pkg/crypto"
+)
+
+func TestHTTPSigner(t *testing.T) {
+	signerCA, err := crypto.MakeSelfSignedCAConfigForDuration("external-signer", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientCA, err := crypto.MakeSelfSignedCAConfigForDuration("external-signer-client", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientCert, err := (&crypto.CA{Config: clientCA, SerialGenerator: &crypto.RandomSerialGenerator{}}).MakeClientCertificateForDuration(&user.DefaultInfo{Name: "system:kube-apiserver-operator"}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientCertPEM, clientKeyPEM, err := clientCert.GetPEMBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name          string
+		handler       func(t *testing.T, w http.ResponseWriter, req *httpRequest)
+		expectedError string
+	}{
+		{
+			name: "Signed",
+			handler: func(t *testing.T, w http.ResponseWriter, req *httpRequest) {
+				if req.Usage != UsageServing || req.ValiditySeconds != 3600 {
+					t.Errorf("unexpected request %+v", req)
+				}
+				block, _ := pem.Decode([]byte(req.CSR))
+				if block == nil {
+					t.Fatalf("invalid CSR %q", req.CSR)
+				}
+				csr, err := x509.ParseCertificateRequest(block.Bytes)
+				if err != nil {
+					t.Fatal(err)
+				}
+				signed, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+					SerialNumber: big.NewInt(1),
+					Subject:      csr.Subject,
+					DNSNames:     csr.DNSNames,
+					NotBefore:    time.Now(),
+					NotAfter:     time.Now().Add(time.Hour),
+					ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+				}, signerCA.Certs[0], csr.PublicKey, signerCA.Key)
+				if err != nil {
+					t.Fatal(err)
+				}
+				caBundle, err := crypto.EncodeCertificates(signerCA.Certs[0])
+				if err != nil {
+					t.Fatal(err)
+				}
+				json.NewEncoder(w).Encode(httpResponse{
+					Certificate: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: signed})),
+					CABundle:    string(caBundle),
+				})
+			},
+		},
+		{
+			name: "Rejected",
+			handler: func(t *testing.T, w http.ResponseWriter, req *httpRequest) {
+				http.Error(w, "subject not allowed", http.StatusForbidden)
+			},
+			expectedError: "external signer returned 403 Forbidden: subject not allowed",
+		},
+		{
+			name: "InvalidResponse",
+			handler: func(t *testing.T, w http.ResponseWriter, req *httpRequest) {
+				json.NewEncoder(w).Encode(httpResponse{Certificate: "not a certificate"})
+			},
+			expectedError: "invalid certificate in external signer response",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost {
+					t.Errorf("expected POST, got %s", r.Method)
+				}
+				if len(r.TLS.PeerCertificates) == 0 || r.TLS.PeerCertificates[0].Subject.CommonName != clientCert.Certs[0].Subject.CommonName {
+					t.Errorf("expected the client certificate to be presented")
+				}
+				req := &httpRequest{}
+				if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+					t.Fatal(err)
+				}
+				tc.handler(t, w, req)
+			}))
+			server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+			server.StartTLS()
+			defer server.Close()
+
+			serverCA, err := crypto.EncodeCertificates(server.Certificate())
+			if err != nil {
+				t.Fatal(err)
+			}
+			signer, err := NewHTTPSigner(&Config{URL: server.URL, CABundle: serverCA, ClientCert: clientCertPEM, ClientKey: clientKeyPEM})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			csr, err := newTestCSR("api.example.com")
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := signer.Sign(context.TODO(), Request{CSR: csr, Usage: UsageServing, Validity: time.Hour})
+			if len(tc.expectedError) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+					t.Fatalf("expected error containing %q, got %v", tc.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(resp.Certificates) != 1 || !sets.NewString(resp.Certificates[0].DNSNames...).Has("api.example.com") {
+				t.Errorf("unexpected certificates %v", resp.Certificates)
+			}
+			if len(resp.CABundle) != 1 || !resp.CABundle[0].Equal(signerCA.Certs[0]) {
+				t.Errorf("unexpected CA bundle %v", resp.CABundle)
+			}
+		})
+	}
+}
+
+func TestNewHTTPSigner(t *testing.T) {
+	if _, err := NewHTTPSigner(&Config{URL: "http://signer.example.com/sign"}); err == nil || !strings.Contains(err.Error(), "must be https") {
+		t.Errorf("expected plain http to be rejected, got %v", err)
+	}
+	if _, err := NewHTTPSigner(&Config{URL: "https://signer.example.com/sign", CABundle: []byte("garbage")}); err == nil {
+		t.Errorf("expected an invalid CA bundle to be rejected")
+	}
+}
+
+func newTestCSR(hostname string) ([]byte, error) {
+	_, key, err := crypto.NewKeyPair()
+	if err != nil {
+		return nil, err
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{DNSNames: []string{hostname}}, key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csr}), nil
+}
@@ -0,0 +1,39 @@
+package externalsigner
+
+import (
+	"context"
+	"crypto/x509"
+	"time"
+)
+
+// Usage is what an issued certificate may be used for.
+type Usage string
+
+const (
+	UsageClient  Usage = "client"
+	UsageServing Usage = "serving"
+)
+
+// Request asks a signer for a certificate.
+type Request struct {
+	// CSR is the PEM encoded certificate signing request. The signer takes the subject and the subject alternative
+	// names from it.
+	CSR []byte
+	// Usage is the extended key usage of the certificate.
+	Usage Usage
+	// Validity is the requested lifetime of the certificate. A signer may issue a shorter lived certificate.
+	Validity time.Duration
+}
+
+// Response holds a certificate issued by a signer.
+type Response struct {
+	// Certificates is the issued certificate followed by the intermediates needed to verify it.
+	Certificates []*x509.Certificate
+	// CABundle holds the CA certificates the issued certificate must be verified with.
+	CABundle []*x509.Certificate
+}
+
+// Signer signs certificate signing requests with a key that is held outside of the cluster.
+type Signer interface {
+	Sign(ctx context.Context, request Request) (*Response, error)
+}
@@ -0,0 +1,158 @@
+package namedcertificatescontroller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	coreclientv1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	configv1informers "github.com/openshift/client-go/config/informers/externalversions/config/v1"
+	configv1listers "github.com/openshift/client-go/config/listers/config/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/operatorclient"
+)
+
+// BundleSecretName is the secret in the target namespace bundling the certificates and keys of all user named serving
+// certificates. It is synced to the nodes as a single unrevisioned secret, so any number of named certificates can be
+// configured and their content changes are picked up by the kube-apiserver without a new revision. Like any secret, the
+// certificates and keys in it must not exceed corev1.MaxSecretSize (1 MiB) in total, otherwise the bundle is not updated.
+const BundleSecretName = "user-named-serving-certs"
+
+// CertFileName is the key of the certificate of the given user secret in the bundle secret.
+func CertFileName(sourceSecretName string) string {
+	return sourceSecretName + ".crt"
+}
+
+// KeyFileName is the key of the private key of the given user secret in the bundle secret.
+func KeyFileName(sourceSecretName string) string {
+	return sourceSecretName + ".key"
+}
+
+// NamedCertificatesController copies the user secrets referenced by spec.servingCerts.namedCertificates of the
+// APIServer config into the bundle secret.
+type NamedCertificatesController struct {
+	operatorClient v1helpers.StaticPodOperatorClient
+
+	kubeClient      kubernetes.Interface
+	secretLister    corev1listers.SecretLister
+	apiServerLister configv1listers.APIServerLister
+}
+
+func NewNamedCertificatesController(
+	operatorClient v1helpers.StaticPodOperatorClient,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	kubeClient kubernetes.Interface,
+	apiServerInformer configv1informers.APIServerInformer,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &NamedCertificatesController{
+		operatorClient:  operatorClient,
+		kubeClient:      kubeClient,
+		secretLister:    kubeInformersForNamespaces.SecretLister(),
+		apiServerLister: apiServerInformer.Lister(),
+	}
+
+	return factory.New().WithInformers(
+		operatorClient.Informer(),
+		kubeInformersForNamespaces.InformersFor(operatorclient.GlobalUserSpecifiedConfigNamespace).Core().V1().Secrets().Informer(),
+		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().Secrets().Informer(),
+		apiServerInformer.Informer(),
+	).WithSync(c.sync).WithSyncDegradedOnError(c.operatorClient).ResyncEvery(5*time.Minute).ToController("NamedCertificatesController", eventRecorder.WithComponentSuffix("named-certificates-controller"))
+}
+
+func (c NamedCertificatesController) sync(ctx context.Context, syncContext factory.SyncContext) error {
+	operatorSpec, _, _, err := c.operatorClient.GetStaticPodOperatorState()
+	if err != nil {
+		return err
+	}
+
+	switch operatorSpec.ManagementState {
+	case operatorv1.Managed:
+	case operatorv1.Unmanaged:
+		return nil
+	case operatorv1.Removed:
+		// TODO probably just fail
+		return nil
+	default:
+		syncContext.Recorder().Warningf("ManagementStateUnknown", "Unrecognized operator management state %q", operatorSpec.ManagementState)
+		return nil
+	}
+
+	return ensureNamedCertificatesBundle(ctx, c.kubeClient.CoreV1(), c.secretLister, c.apiServerLister, syncContext.Recorder())
+}
+
+// ensureNamedCertificatesBundle writes the certificates and keys of the referenced user secrets into the bundle secret.
+// Certificates that cannot be read are left out and reported, the others are still bundled.
+func ensureNamedCertificatesBundle(ctx context.Context, client coreclientv1.SecretsGetter, secretLister corev1listers.SecretLister, apiServerLister configv1listers.APIServerLister, recorder events.Recorder) error {
+	apiServer, err := apiServerLister.Get("cluster")
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	requiredSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: BundleSecretName},
+		Type:       corev1.SecretTypeOpaque,
+		Data:       map[string][]byte{},
+	}
+
+	var errs []error
+	if apiServer != nil {
+		for index, namedCertificate := range apiServer.Spec.ServingCerts.NamedCertificates {
+			sourceSecretName := namedCertificate.ServingCertificate.Name
+			if len(sourceSecretName) == 0 {
+				// reported by the config observer
+				continue
+			}
+			sourceSecret, err := secretLister.Secrets(operatorclient.GlobalUserSpecifiedConfigNamespace).Get(sourceSecretName)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("spec.servingCerts.namedCertificates[%d]: %v", index, err))
+				continue
+			}
+			cert, key := sourceSecret.Data[corev1.TLSCertKey], sourceSecret.Data[corev1.TLSPrivateKeyKey]
+			if len(cert) == 0 || len(key) == 0 {
+				errs = append(errs, fmt.Errorf("spec.servingCerts.namedCertificates[%d]: secret %s/%s must contain %s and %s",
+					index, operatorclient.GlobalUserSpecifiedConfigNamespace, sourceSecretName, corev1.TLSCertKey, corev1.TLSPrivateKeyKey))
+				continue
+			}
+			requiredSecret.Data[CertFileName(sourceSecretName)] = cert
+			requiredSecret.Data[KeyFileName(sourceSecretName)] = key
+		}
+	}
+
+	if size := dataSize(requiredSecret.Data); size > corev1.MaxSecretSize {
+		errs = append(errs, fmt.Errorf("the certificates and keys of spec.servingCerts.namedCertificates take %d bytes, secret %s/%s is limited to %d bytes (1 MiB): keeping the current named certificates",
+			size, operatorclient.TargetNamespace, BundleSecretName, corev1.MaxSecretSize))
+	} else if len(requiredSecret.Data) > 0 {
+		if _, _, err := resourceapply.ApplySecret(ctx, client, recorder, requiredSecret); err != nil {
+			errs = append(errs, err)
+		}
+	} else if _, err := secretLister.Secrets(operatorclient.TargetNamespace).Get(BundleSecretName); err == nil {
+		if _, _, err := resourceapply.DeleteSecret(ctx, client, recorder, requiredSecret); err != nil {
+			errs = append(errs, err)
+		}
+	} else if !errors.IsNotFound(err) {
+		errs = append(errs, err)
+	}
+
+	return v1helpers.NewMultiLineAggregate(errs)
+}
+
+// dataSize returns the size of the given secret data as counted by the apiserver against corev1.MaxSecretSize.
+func dataSize(data map[string][]byte) int {
+	size := 0
+	for _, value := range data {
+		size += len(value)
+	}
+	return size
+}
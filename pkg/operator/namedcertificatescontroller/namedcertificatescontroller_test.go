@@ -0,0 +1,129 @@
+package namedcertificatescontroller
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configlistersv1 "github.com/openshift/client-go/config/listers/config/v1"
+	"github.com/openshift/library-go/pkg/operator/events"
+)
+
+func TestEnsureNamedCertificatesBundle(t *testing.T) {
+	userSecret := func(name string, data map[string][]byte) *corev1.Secret {
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-config", Name: name}, Data: data}
+	}
+	existingBundle := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-kube-apiserver", Name: BundleSecretName},
+		Data:       map[string][]byte{"old.crt": []byte("old-cert"), "old.key": []byte("old-key")},
+	}
+
+	testCases := []struct {
+		name              string
+		secretNames       []string
+		objects           []runtime.Object
+		expectedBundle    map[string][]byte
+		expectedError     string
+		expectedNoActions bool
+	}{
+		{
+			name:        "NamedCertificates",
+			secretNames: []string{"one", "two", "one"},
+			objects: []runtime.Object{
+				userSecret("one", map[string][]byte{"tls.crt": []byte("one-cert"), "tls.key": []byte("one-key")}),
+				userSecret("two", map[string][]byte{"tls.crt": []byte("two-cert"), "tls.key": []byte("two-key")}),
+				existingBundle,
+			},
+			expectedBundle: map[string][]byte{
+				"one.crt": []byte("one-cert"), "one.key": []byte("one-key"),
+				"two.crt": []byte("two-cert"), "two.key": []byte("two-key"),
+			},
+		},
+		{
+			name:        "MissingAndInvalidSecrets",
+			secretNames: []string{"missing", "one", "nokey"},
+			objects: []runtime.Object{
+				userSecret("one", map[string][]byte{"tls.crt": []byte("one-cert"), "tls.key": []byte("one-key")}),
+				userSecret("nokey", map[string][]byte{"tls.crt": []byte("nokey-cert")}),
+			},
+			expectedBundle: map[string][]byte{"one.crt": []byte("one-cert"), "one.key": []byte("one-key")},
+			expectedError:  `spec.servingCerts.namedCertificates[0]: secret "missing" not found` + "\n" + "spec.servingCerts.namedCertificates[2]: secret openshift-config/nokey must contain tls.crt and tls.key",
+		},
+		{
+			name:        "BundleTooLarge",
+			secretNames: []string{"one", "two"},
+			objects: []runtime.Object{
+				userSecret("one", map[string][]byte{"tls.crt": make([]byte, 512*1024), "tls.key": []byte("one-key")}),
+				userSecret("two", map[string][]byte{"tls.crt": make([]byte, 512*1024), "tls.key": []byte("two-key")}),
+				existingBundle,
+			},
+			expectedBundle: existingBundle.Data,
+			expectedError:  "secret openshift-kube-apiserver/user-named-serving-certs is limited to 1048576 bytes (1 MiB)",
+		},
+		{
+			name:    "NoNamedCertificates",
+			objects: []runtime.Object{existingBundle},
+		},
+		{
+			name:              "NoNamedCertificatesNoBundle",
+			expectedNoActions: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			apiServer := &configv1.APIServer{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}
+			for _, name := range tc.secretNames {
+				apiServer.Spec.ServingCerts.NamedCertificates = append(apiServer.Spec.ServingCerts.NamedCertificates, configv1.APIServerNamedServingCert{
+					ServingCertificate: configv1.SecretNameReference{Name: name},
+				})
+			}
+			apiServerIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if err := apiServerIndexer.Add(apiServer); err != nil {
+				t.Fatal(err)
+			}
+			secretIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			for _, obj := range tc.objects {
+				if err := secretIndexer.Add(obj); err != nil {
+					t.Fatal(err)
+				}
+			}
+			client := fake.NewSimpleClientset(tc.objects...)
+
+			err := ensureNamedCertificatesBundle(context.TODO(), client.CoreV1(), corev1listers.NewSecretLister(secretIndexer), configlistersv1.NewAPIServerLister(apiServerIndexer), events.NewInMemoryRecorder(t.Name()))
+			if len(tc.expectedError) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+					t.Fatalf("expected error containing %q, got %v", tc.expectedError, err)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if tc.expectedNoActions && len(client.Actions()) > 0 {
+				t.Errorf("expected no actions, got %v", client.Actions())
+			}
+
+			bundle, err := client.CoreV1().Secrets("openshift-kube-apiserver").Get(context.TODO(), BundleSecretName, metav1.GetOptions{})
+			if tc.expectedBundle == nil {
+				if !errors.IsNotFound(err) {
+					t.Fatalf("expected the bundle to be deleted, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(bundle.Data, tc.expectedBundle) {
+				t.Errorf("expected bundle %v, got %v", tc.expectedBundle, bundle.Data)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package operatorclient
+
+import (
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+// CurrentRevisions returns the unique set of current revisions of the apiserver nodes.
+func CurrentRevisions(operatorClient v1helpers.StaticPodOperatorClient) ([]int32, error) {
+	_, operatorStatus, _, err := operatorClient.GetStaticPodOperatorState()
+	if err != nil {
+		return nil, err
+	}
+
+	revisionMap := map[int32]struct{}{}
+	uniqueRevisions := []int32{}
+	for _, nodeStatus := range operatorStatus.NodeStatuses {
+		revision := nodeStatus.CurrentRevision
+		if _, ok := revisionMap[revision]; !ok {
+			revisionMap[revision] = struct{}{}
+			uniqueRevisions = append(uniqueRevisions, revision)
+		}
+	}
+	return uniqueRevisions, nil
+}
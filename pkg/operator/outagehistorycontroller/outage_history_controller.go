@@ -0,0 +1,83 @@
+package outagehistorycontroller
+
+import (
+	"context"
+	"time"
+
+	alpha1 "github.com/openshift/client-go/operatorcontrolplane/informers/externalversions/operatorcontrolplane/v1alpha1"
+	"github.com/openshift/client-go/operatorcontrolplane/listers/operatorcontrolplane/v1alpha1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/management"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/cmd/checkendpoints/outagehistory"
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/operatorclient"
+)
+
+// outageHistoryController records the outages in the status of the PodNetworkConnectivityChecks of the
+// target namespace in their outage history configmaps. It runs in the operator so that the check-endpoints
+// sidecars, which update the check status, need no write access to the configmaps of the target namespace.
+type outageHistoryController struct {
+	operatorClient v1helpers.StaticPodOperatorClient
+	checkLister    v1alpha1.PodNetworkConnectivityCheckNamespaceLister
+	outageRecorder *outagehistory.Recorder
+
+	// recorded are the checks the recorder holds state for
+	recorded sets.String
+}
+
+// NewOutageHistoryController returns a controller recording the outage history of the
+// PodNetworkConnectivityChecks in the target namespace.
+func NewOutageHistoryController(
+	operatorClient v1helpers.StaticPodOperatorClient,
+	checkInformer alpha1.PodNetworkConnectivityCheckInformer,
+	configMapsGetter corev1client.ConfigMapsGetter,
+	recorder events.Recorder,
+) factory.Controller {
+	c := &outageHistoryController{
+		operatorClient: operatorClient,
+		checkLister:    checkInformer.Lister().PodNetworkConnectivityChecks(operatorclient.TargetNamespace),
+		outageRecorder: outagehistory.NewRecorder(configMapsGetter),
+		recorded:       sets.NewString(),
+	}
+	return factory.New().
+		WithInformers(operatorClient.Informer(), checkInformer.Informer()).
+		ResyncEvery(10*time.Minute).
+		WithSync(c.sync).
+		ToController("OutageHistoryController", recorder)
+}
+
+func (c *outageHistoryController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	operatorSpec, _, _, err := c.operatorClient.GetStaticPodOperatorState()
+	if err != nil {
+		return err
+	}
+	if !management.IsOperatorManaged(operatorSpec.ManagementState) {
+		return nil
+	}
+
+	checks, err := c.checkLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	current := sets.NewString()
+	for _, check := range checks {
+		current.Insert(check.Name)
+		if err := c.outageRecorder.Record(ctx, check); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	// the history of a deleted check is garbage collected with the check
+	for _, name := range c.recorded.Difference(current).List() {
+		c.outageRecorder.Forget(name)
+	}
+	c.recorded = current
+
+	return v1helpers.NewMultiLineAggregate(errs)
+}
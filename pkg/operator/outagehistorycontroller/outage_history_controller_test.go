@@ -0,0 +1,75 @@
+package outagehistorycontroller
+
+import (
+	"context"
+	"testing"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	operatorcontrolplanev1alpha1 "github.com/openshift/api/operatorcontrolplane/v1alpha1"
+	"github.com/openshift/client-go/operatorcontrolplane/listers/operatorcontrolplane/v1alpha1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/cmd/checkendpoints/outagehistory"
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/operatorclient"
+)
+
+func TestOutageHistoryController(t *testing.T) {
+	newCheck := func(name string) *operatorcontrolplanev1alpha1.PodNetworkConnectivityCheck {
+		return &operatorcontrolplanev1alpha1.PodNetworkConnectivityCheck{
+			ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: name},
+			Spec:       operatorcontrolplanev1alpha1.PodNetworkConnectivityCheckSpec{SourcePod: "source", TargetEndpoint: "host:port"},
+			Status: operatorcontrolplanev1alpha1.PodNetworkConnectivityCheckStatus{
+				Outages: []operatorcontrolplanev1alpha1.OutageEntry{{Start: metav1.Now()}},
+			},
+		}
+	}
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, check := range []*operatorcontrolplanev1alpha1.PodNetworkConnectivityCheck{newCheck("a"), newCheck("b")} {
+		if err := indexer.Add(check); err != nil {
+			t.Fatal(err)
+		}
+	}
+	kubeClient := fake.NewSimpleClientset()
+	c := &outageHistoryController{
+		operatorClient: v1helpers.NewFakeStaticPodOperatorClient(
+			&operatorv1.StaticPodOperatorSpec{OperatorSpec: operatorv1.OperatorSpec{ManagementState: operatorv1.Managed}},
+			&operatorv1.StaticPodOperatorStatus{}, nil, nil),
+		checkLister:    v1alpha1.NewPodNetworkConnectivityCheckLister(indexer).PodNetworkConnectivityChecks(operatorclient.TargetNamespace),
+		outageRecorder: outagehistory.NewRecorder(kubeClient.CoreV1()),
+		recorded:       sets.NewString(),
+	}
+	syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))
+
+	if err := c.sync(context.TODO(), syncCtx); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a", "b"} {
+		configMap, err := kubeClient.CoreV1().ConfigMaps(operatorclient.TargetNamespace).Get(context.TODO(), outagehistory.Name(name), metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		history, err := outagehistory.Decode(configMap)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if history.CheckName != name || len(history.Outages) != 1 {
+			t.Errorf("unexpected history %#v", history)
+		}
+	}
+
+	if err := indexer.Delete(newCheck("b")); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.sync(context.TODO(), syncCtx); err != nil {
+		t.Fatal(err)
+	}
+	if !c.recorded.Equal(sets.NewString("a")) {
+		t.Errorf("expected the deleted check to be forgotten, got %v", c.recorded.List())
+	}
+}
@@ -0,0 +1,509 @@
+package signerrotationcontroller
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	errorsutil "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/util/cert"
+	"k8s.io/klog/v2"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/crypto"
+	"github.com/openshift/library-go/pkg/operator/certrotation"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/management"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/operatorclient"
+)
+
+const (
+	// RotationRequestedAnnotation requests the rotation of the signer secret it is set on. The value is arbitrary, setting
+	// a value that differs from RotationCompletedAnnotation requests another rotation, e.g.
+	//
+	//   oc annotate -n openshift-kube-apiserver-operator secret/localhost-serving-signer --overwrite \
+	//     kubeapiserver.operator.openshift.io/signer-rotation-requested="$(date -u +%FT%TZ)"
+	RotationRequestedAnnotation = "kubeapiserver.operator.openshift.io/signer-rotation-requested"
+	// RotationCompletedAnnotation records the value of RotationRequestedAnnotation once the rotation is completed.
+	RotationCompletedAnnotation = "kubeapiserver.operator.openshift.io/signer-rotation-completed"
+	// RotationPhaseAnnotation records the phase of the rotation in progress.
+	RotationPhaseAnnotation = "kubeapiserver.operator.openshift.io/signer-rotation-phase"
+	// PreviousSignerAnnotation records the common name of the signer replaced by the rotation in progress.
+	PreviousSignerAnnotation = "kubeapiserver.operator.openshift.io/signer-rotation-previous-signer"
+	// ConsumersTrustSignerAnnotation confirms that the consumers the operator cannot observe trust the new signer of a
+	// rotation in progress, e.g. the kubelets, which get the published CA bundles through the machine config operator,
+	// or the pods, which get them through the kube-root-ca.crt configmaps of kube-controller-manager. The rotation of
+	// signers with such consumers waits for the admin to set the common name of the new signer once they trust it, e.g.
+	//
+	//   oc annotate -n openshift-kube-apiserver-operator secret/kube-apiserver-to-kubelet-signer --overwrite \
+	//     kubeapiserver.operator.openshift.io/signer-rotation-consumers-trust="<new signer common name>"
+	ConsumersTrustSignerAnnotation = "kubeapiserver.operator.openshift.io/signer-rotation-consumers-trust"
+)
+
+// The phases of a signer rotation, in order.
+const (
+	// PhaseDistributingCABundle waits for the new signer to be generated and trusted by all consumers of the CA bundle,
+	// including the kubelets if they trust the signer.
+	PhaseDistributingCABundle = "DistributingCABundle"
+	// PhaseReissuingTargets waits for the target certificates to be re-issued by the new signer and rolled out.
+	PhaseReissuingTargets = "ReissuingTargets"
+	// PhaseRemovingPreviousSigner removes the previous signer from the CA bundle.
+	PhaseRemovingPreviousSigner = "RemovingPreviousSigner"
+)
+
+// resourceLocation is a config map or secret checked during a rotation. Revisioned resources are copied into every
+// revision of the target namespace and only reach a node once the node runs a revision containing them.
+type resourceLocation struct {
+	namespace  string
+	name       string
+	revisioned bool
+}
+
+// rotatableSigner is a signer in the operator namespace that can be rotated on demand.
+type rotatableSigner struct {
+	// conditionName names the condition reporting the rotation of the signer.
+	conditionName string
+	signer        string
+	caBundle      string
+	// trustedBy are the CA bundles the CA bundle of the signer is combined into and that must contain a new signer
+	// before target certificates are re-issued.
+	trustedBy []resourceLocation
+	// unobservedConsumers verify the targets with CA bundles the operator cannot observe, a new signer must then be
+	// confirmed with ConsumersTrustSignerAnnotation before the targets are re-issued.
+	unobservedConsumers []string
+	// targets are the secrets holding the certificates issued by the signer.
+	targets []resourceLocation
+}
+
+// rotatableSigners are the long-lived signers of the kube-apiserver that are otherwise practically never rotated.
+var rotatableSigners = []rotatableSigner{
+	{
+		conditionName:       "LocalhostServing",
+		signer:              "localhost-serving-signer",
+		caBundle:            "localhost-serving-ca",
+		trustedBy:           servingCATrustedBy,
+		unobservedConsumers: localhostConsumers,
+		targets: []resourceLocation{
+			{namespace: operatorclient.TargetNamespace, name: "localhost-serving-cert-certkey"},
+		},
+	},
+	{
+		// the pods reach the kube-apiserver through the kubernetes service
+		conditionName:       "ServiceNetworkServing",
+		signer:              "service-network-serving-signer",
+		caBundle:            "service-network-serving-ca",
+		trustedBy:           servingCATrustedBy,
+		unobservedConsumers: podConsumers,
+		targets: []resourceLocation{
+			{namespace: operatorclient.TargetNamespace, name: "service-network-serving-certkey"},
+		},
+	},
+	{
+		// the kubelets reach the kube-apiserver through the internal load balancer
+		conditionName:       "LoadBalancerServing",
+		signer:              "loadbalancer-serving-signer",
+		caBundle:            "loadbalancer-serving-ca",
+		trustedBy:           servingCATrustedBy,
+		unobservedConsumers: kubeletConsumers,
+		targets: []resourceLocation{
+			{namespace: operatorclient.TargetNamespace, name: "external-loadbalancer-serving-certkey"},
+			{namespace: operatorclient.TargetNamespace, name: "internal-loadbalancer-serving-certkey"},
+		},
+	},
+	{
+		conditionName: "LocalhostRecoveryServing",
+		signer:        "localhost-recovery-serving-signer",
+		caBundle:      "localhost-recovery-serving-ca",
+		trustedBy:     servingCATrustedBy,
+		targets: []resourceLocation{
+			{namespace: operatorclient.TargetNamespace, name: "localhost-recovery-serving-certkey", revisioned: true},
+		},
+	},
+	{
+		// the kubelets verify the client certificate of the kube-apiserver with the published client CA
+		conditionName: "KubeAPIServerToKubelet",
+		signer:        "kube-apiserver-to-kubelet-signer",
+		caBundle:      "kube-apiserver-to-kubelet-client-ca",
+		trustedBy: []resourceLocation{
+			{namespace: operatorclient.TargetNamespace, name: "client-ca"},
+			{namespace: operatorclient.GlobalMachineSpecifiedConfigNamespace, name: "kube-apiserver-client-ca"},
+		},
+		unobservedConsumers: kubeletConsumers,
+		targets: []resourceLocation{
+			{namespace: operatorclient.TargetNamespace, name: "kubelet-client"},
+		},
+	},
+}
+
+// kubeletConsumers get the published CA bundles through the machine config operator.
+var kubeletConsumers = []string{
+	"the kubelets, once all machine config pools are updated",
+}
+
+// localhostConsumers get the published serving CA bundle through the localhost kubeconfigs of the nodes.
+var localhostConsumers = []string{
+	"the clients on the control plane nodes using the localhost kubeconfigs of secret openshift-kube-apiserver/node-kubeconfigs",
+}
+
+// podConsumers get the published serving CA bundle through kube-controller-manager.
+var podConsumers = []string{
+	"configmap openshift-kube-controller-manager/serviceaccount-ca in the revision of kube-controller-manager running on all nodes",
+	"the kube-root-ca.crt configmap in every namespace",
+	"the pods reading it",
+}
+
+// servingCATrustedBy are the CA bundles the serving CAs are combined into.
+var servingCATrustedBy = []resourceLocation{
+	{namespace: operatorclient.TargetNamespace, name: "kube-apiserver-server-ca", revisioned: true},
+	{namespace: operatorclient.GlobalMachineSpecifiedConfigNamespace, name: "kube-apiserver-server-ca"},
+}
+
+// SignerRotationController rotates signers on request. Unlike the cert rotation controller, which keeps the previous
+// signer in the CA bundle until it expires, it removes the previous signer once all target certificates have been
+// re-issued, e.g. after a suspected key compromise. A rotation:
+//
+//  1. removes the not-after annotation of the signer secret so that the cert rotation controller generates a new
+//     signer and adds it to the CA bundle,
+//  2. waits until the CA bundles combining the CA bundle contain the new signer, for revisioned CA bundles in the
+//     revisions all nodes are running, and for signers with consumers the operator cannot observe, like the kubelets
+//     or the pods, until the admin confirmed with ConsumersTrustSignerAnnotation that they trust the new signer,
+//  3. removes the not-after annotation of the targets issued by the previous signer so that the cert rotation
+//     controller re-issues them, and waits until they are, for revisioned targets on all nodes,
+//  4. removes the previous signer from the CA bundle.
+//
+// The progress of each signer is reported by a SignerRotation_<name>_Progressing condition.
+type SignerRotationController struct {
+	operatorClient  v1helpers.StaticPodOperatorClient
+	secretClient    corev1client.SecretsGetter
+	configMapClient corev1client.ConfigMapsGetter
+
+	signers []rotatableSigner
+}
+
+func NewSignerRotationController(
+	operatorClient v1helpers.StaticPodOperatorClient,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	kubeClient kubernetes.Interface,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &SignerRotationController{
+		operatorClient:  operatorClient,
+		secretClient:    v1helpers.CachedSecretGetter(kubeClient.CoreV1(), kubeInformersForNamespaces),
+		configMapClient: v1helpers.CachedConfigMapGetter(kubeClient.CoreV1(), kubeInformersForNamespaces),
+		signers:         rotatableSigners,
+	}
+
+	return factory.New().WithInformers(
+		kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().Secrets().Informer(),
+		kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().ConfigMaps().Informer(),
+		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().Secrets().Informer(),
+		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().ConfigMaps().Informer(),
+		kubeInformersForNamespaces.InformersFor(operatorclient.GlobalMachineSpecifiedConfigNamespace).Core().V1().ConfigMaps().Informer(),
+		operatorClient.Informer(),
+	).ResyncEvery(time.Minute).WithSync(c.sync).ToController("SignerRotationController", eventRecorder)
+}
+
+func (c *SignerRotationController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	operatorSpec, _, _, err := c.operatorClient.GetStaticPodOperatorState()
+	if err != nil {
+		return err
+	}
+	if !management.IsOperatorManaged(operatorSpec.ManagementState) {
+		return nil
+	}
+
+	errs := []error{}
+	conditions := []v1helpers.UpdateStaticPodStatusFunc{}
+	for _, s := range c.signers {
+		condition, err := c.syncSigner(ctx, syncCtx.Recorder(), s)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", s.signer, err))
+		}
+		if condition != nil {
+			conditions = append(conditions, v1helpers.UpdateStaticPodConditionFn(*condition))
+		}
+	}
+	if _, _, err := v1helpers.UpdateStaticPodStatus(ctx, c.operatorClient, conditions...); err != nil {
+		errs = append(errs, err)
+	}
+	return errorsutil.NewAggregate(errs)
+}
+
+// syncSigner advances the rotation of the signer by at most one phase and returns the condition reporting its progress.
+// The condition is nil if the progress is unknown.
+func (c *SignerRotationController) syncSigner(ctx context.Context, recorder events.Recorder, s rotatableSigner) (*operatorv1.OperatorCondition, error) {
+	condition := &operatorv1.OperatorCondition{
+		Type:   fmt.Sprintf("SignerRotation_%s_Progressing", s.conditionName),
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+
+	secret, err := c.secretClient.Secrets(operatorclient.OperatorNamespace).Get(ctx, s.signer, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		// nothing to rotate until the cert rotation controller created the signer
+		return condition, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	requested := secret.Annotations[RotationRequestedAnnotation]
+	if len(requested) == 0 || requested == secret.Annotations[RotationCompletedAnnotation] {
+		return condition, nil
+	}
+
+	signer, err := signerCertificate(secret)
+	if err != nil {
+		return nil, err
+	}
+	previous := secret.Annotations[PreviousSignerAnnotation]
+	phase := secret.Annotations[RotationPhaseAnnotation]
+
+	condition.Status = operatorv1.ConditionTrue
+	condition.Reason = phase
+	switch phase {
+	case "":
+		condition.Reason = PhaseDistributingCABundle
+		condition.Message = fmt.Sprintf("Generating a new signer to replace %q.", signer.Subject.CommonName)
+		return condition, c.startRotation(ctx, recorder, secret, signer)
+
+	case PhaseDistributingCABundle:
+		if signer.Subject.CommonName == previous {
+			condition.Message = fmt.Sprintf("Waiting for a new signer to replace %q.", previous)
+			return condition, nil
+		}
+		untrusted, err := c.untrustedBy(ctx, s, signer)
+		if err != nil {
+			return nil, err
+		}
+		if len(untrusted) > 0 {
+			condition.Message = fmt.Sprintf("Waiting for the new signer %q to be trusted by %s.", signer.Subject.CommonName, strings.Join(untrusted, ", "))
+			return condition, nil
+		}
+		if len(s.unobservedConsumers) > 0 && secret.Annotations[ConsumersTrustSignerAnnotation] != signer.Subject.CommonName {
+			condition.Message = fmt.Sprintf("Waiting for the confirmation that the new signer %q is trusted by %s: once it is, annotate secret %s/%s with %s=%q.",
+				signer.Subject.CommonName, strings.Join(s.unobservedConsumers, ", "), secret.Namespace, secret.Name, ConsumersTrustSignerAnnotation, signer.Subject.CommonName)
+			return condition, nil
+		}
+		condition.Reason = PhaseReissuingTargets
+		condition.Message = fmt.Sprintf("Re-issuing the certificates signed by %q.", previous)
+		return condition, c.setPhase(ctx, recorder, secret, PhaseReissuingTargets)
+
+	case PhaseReissuingTargets:
+		pending, err := c.reissueTargets(ctx, recorder, s, signer.Subject.CommonName, previous)
+		if err != nil {
+			return nil, err
+		}
+		if len(pending) > 0 {
+			condition.Message = fmt.Sprintf("Waiting for %s to be re-issued by the new signer %q.", strings.Join(pending, ", "), signer.Subject.CommonName)
+			return condition, nil
+		}
+		condition.Reason = PhaseRemovingPreviousSigner
+		condition.Message = fmt.Sprintf("Removing the previous signer %q from the CA bundle.", previous)
+		return condition, c.setPhase(ctx, recorder, secret, PhaseRemovingPreviousSigner)
+
+	case PhaseRemovingPreviousSigner:
+		if err := c.removeFromCABundle(ctx, recorder, s.caBundle, previous); err != nil {
+			return nil, err
+		}
+		condition.Status = operatorv1.ConditionFalse
+		condition.Reason = "AsExpected"
+		return condition, c.completeRotation(ctx, recorder, secret, previous)
+
+	default:
+		return nil, fmt.Errorf("unknown signer rotation phase %q", phase)
+	}
+}
+
+// startRotation records the current signer and removes its not-after annotation so that it is replaced by the cert
+// rotation controller.
+func (c *SignerRotationController) startRotation(ctx context.Context, recorder events.Recorder, secret *corev1.Secret, signer *x509.Certificate) error {
+	secret = secret.DeepCopy()
+	secret.Annotations[PreviousSignerAnnotation] = signer.Subject.CommonName
+	secret.Annotations[RotationPhaseAnnotation] = PhaseDistributingCABundle
+	delete(secret.Annotations, certrotation.CertificateNotAfterAnnotation)
+	if _, err := c.secretClient.Secrets(secret.Namespace).Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+	recorder.Eventf("SignerRotationStarted", "Rotating signer %s/%s, replacing %q", secret.Namespace, secret.Name, signer.Subject.CommonName)
+	return nil
+}
+
+func (c *SignerRotationController) setPhase(ctx context.Context, recorder events.Recorder, secret *corev1.Secret, phase string) error {
+	secret = secret.DeepCopy()
+	secret.Annotations[RotationPhaseAnnotation] = phase
+	if _, err := c.secretClient.Secrets(secret.Namespace).Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+	recorder.Eventf("SignerRotationProgressing", "Rotation of signer %s/%s entered phase %s", secret.Namespace, secret.Name, phase)
+	return nil
+}
+
+func (c *SignerRotationController) completeRotation(ctx context.Context, recorder events.Recorder, secret *corev1.Secret, previous string) error {
+	secret = secret.DeepCopy()
+	secret.Annotations[RotationCompletedAnnotation] = secret.Annotations[RotationRequestedAnnotation]
+	delete(secret.Annotations, RotationPhaseAnnotation)
+	delete(secret.Annotations, PreviousSignerAnnotation)
+	delete(secret.Annotations, ConsumersTrustSignerAnnotation)
+	if _, err := c.secretClient.Secrets(secret.Namespace).Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+	recorder.Eventf("SignerRotationCompleted", "Rotated signer %s/%s, %q is not trusted anymore", secret.Namespace, secret.Name, previous)
+	return nil
+}
+
+// untrustedBy returns the CA bundles that do not contain the signer yet.
+func (c *SignerRotationController) untrustedBy(ctx context.Context, s rotatableSigner, signer *x509.Certificate) ([]string, error) {
+	untrusted := []string{}
+	for _, location := range s.trustedBy {
+		names, err := c.nodeCopies(location)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			configMap, err := c.configMapClient.ConfigMaps(location.namespace).Get(ctx, name, metav1.GetOptions{})
+			if apierrors.IsNotFound(err) {
+				untrusted = append(untrusted, fmt.Sprintf("%s/%s", location.namespace, name))
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			certs, err := cert.ParseCertsPEM([]byte(configMap.Data["ca-bundle.crt"]))
+			if err != nil {
+				return nil, fmt.Errorf("configmap %s/%s: %v", location.namespace, name, err)
+			}
+			if !containsCertificate(certs, signer) {
+				untrusted = append(untrusted, fmt.Sprintf("%s/%s", location.namespace, name))
+			}
+		}
+	}
+	return untrusted, nil
+}
+
+// reissueTargets removes the not-after annotation of the targets issued by the previous signer so that the cert rotation
+// controller re-issues them. It returns the targets that are not issued by the new signer yet.
+func (c *SignerRotationController) reissueTargets(ctx context.Context, recorder events.Recorder, s rotatableSigner, issuer, previous string) ([]string, error) {
+	pending := []string{}
+	for _, location := range s.targets {
+		secret, err := c.secretClient.Secrets(location.namespace).Get(ctx, location.name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			pending = append(pending, fmt.Sprintf("%s/%s", location.namespace, location.name))
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if secret.Annotations[certrotation.CertificateIssuer] == previous {
+			if _, ok := secret.Annotations[certrotation.CertificateNotAfterAnnotation]; ok {
+				secret = secret.DeepCopy()
+				delete(secret.Annotations, certrotation.CertificateNotAfterAnnotation)
+				if _, err := c.secretClient.Secrets(secret.Namespace).Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+					return nil, err
+				}
+				recorder.Eventf("SignerRotationTargetReissued", "Requested re-issuing %s/%s signed by %q", secret.Namespace, secret.Name, previous)
+			}
+		}
+
+		// the secret itself is the first copy, followed by those of the revisions running on the nodes
+		names, err := c.nodeCopies(location)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			copied, err := c.secretClient.Secrets(location.namespace).Get(ctx, name, metav1.GetOptions{})
+			if apierrors.IsNotFound(err) {
+				pending = append(pending, fmt.Sprintf("%s/%s", location.namespace, name))
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			if copied.Annotations[certrotation.CertificateIssuer] != issuer {
+				pending = append(pending, fmt.Sprintf("%s/%s", location.namespace, name))
+			}
+		}
+	}
+	return pending, nil
+}
+
+// removeFromCABundle removes the certificates with the given common name from the CA bundle in the operator namespace.
+// The CA bundles combining it are updated by the target config controller.
+func (c *SignerRotationController) removeFromCABundle(ctx context.Context, recorder events.Recorder, name, commonName string) error {
+	configMap, err := c.configMapClient.ConfigMaps(operatorclient.OperatorNamespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	certs, err := cert.ParseCertsPEM([]byte(configMap.Data["ca-bundle.crt"]))
+	if err != nil {
+		return fmt.Errorf("configmap %s/%s: %v", configMap.Namespace, configMap.Name, err)
+	}
+	kept := []*x509.Certificate{}
+	for _, certificate := range certs {
+		if certificate.Subject.CommonName != commonName {
+			kept = append(kept, certificate)
+		}
+	}
+	if len(kept) == len(certs) {
+		return nil
+	}
+	if len(kept) == 0 {
+		return fmt.Errorf("configmap %s/%s contains only %q", configMap.Namespace, configMap.Name, commonName)
+	}
+	caBytes, err := crypto.EncodeCertificates(kept...)
+	if err != nil {
+		return err
+	}
+
+	configMap = configMap.DeepCopy()
+	configMap.Data["ca-bundle.crt"] = string(caBytes)
+	if _, err := c.configMapClient.ConfigMaps(configMap.Namespace).Update(ctx, configMap, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+	klog.V(2).Infof("Removed %q from configmap %s/%s", commonName, configMap.Namespace, configMap.Name)
+	recorder.Eventf("SignerRotationCABundleUpdated", "Removed %q from configmap %s/%s", commonName, configMap.Namespace, configMap.Name)
+	return nil
+}
+
+// nodeCopies returns the names of the copies of the resource the nodes are using: the resource itself and, if it is
+// revisioned, its copies in the revisions the nodes are running.
+func (c *SignerRotationController) nodeCopies(location resourceLocation) ([]string, error) {
+	names := []string{location.name}
+	if !location.revisioned {
+		return names, nil
+	}
+	revisions, err := operatorclient.CurrentRevisions(c.operatorClient)
+	if err != nil {
+		return nil, err
+	}
+	for _, revision := range revisions {
+		names = append(names, fmt.Sprintf("%s-%d", location.name, revision))
+	}
+	return names, nil
+}
+
+func signerCertificate(secret *corev1.Secret) (*x509.Certificate, error) {
+	certs, err := cert.ParseCertsPEM(secret.Data[corev1.TLSCertKey])
+	if err != nil {
+		return nil, fmt.Errorf("secret %s/%s: %v", secret.Namespace, secret.Name, err)
+	}
+	return certs[0], nil
+}
+
+func containsCertificate(certs []*x509.Certificate, c *x509.Certificate) bool {
+	for _, existing := range certs {
+		if existing.Equal(c) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,230 @@
+package signerrotationcontroller
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/util/cert"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/certrotation"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/operatorclient"
+)
+
+func newCertPEM(t *testing.T, commonName string) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func TestSignerRotation(t *testing.T) {
+	const (
+		operatorNamespace = operatorclient.OperatorNamespace
+		targetNamespace   = operatorclient.TargetNamespace
+		managedNamespace  = operatorclient.GlobalMachineSpecifiedConfigNamespace
+	)
+	oldSigner := newCertPEM(t, "old-signer")
+	newSigner := newCertPEM(t, "new-signer")
+
+	caBundle := func(namespace, name, bundle string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Data:       map[string]string{"ca-bundle.crt": bundle},
+		}
+	}
+	kubeClient := fake.NewSimpleClientset(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: operatorNamespace,
+				Name:      "signer",
+				Annotations: map[string]string{
+					RotationRequestedAnnotation:                "1",
+					certrotation.CertificateNotAfterAnnotation: "2030-01-01T00:00:00Z",
+				},
+			},
+			Data: map[string][]byte{corev1.TLSCertKey: []byte(oldSigner)},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: targetNamespace,
+				Name:      "target",
+				Annotations: map[string]string{
+					certrotation.CertificateIssuer:             "old-signer",
+					certrotation.CertificateNotAfterAnnotation: "2030-01-01T00:00:00Z",
+				},
+			},
+		},
+		caBundle(operatorNamespace, "signer-ca", oldSigner),
+		caBundle(targetNamespace, "server-ca", oldSigner),
+		caBundle(targetNamespace, "server-ca-1", oldSigner),
+		caBundle(managedNamespace, "server-ca", oldSigner),
+	)
+	operatorClient := v1helpers.NewFakeStaticPodOperatorClient(
+		&operatorv1.StaticPodOperatorSpec{OperatorSpec: operatorv1.OperatorSpec{ManagementState: operatorv1.Managed}},
+		&operatorv1.StaticPodOperatorStatus{NodeStatuses: []operatorv1.NodeStatus{{NodeName: "master-0", CurrentRevision: 1}}},
+		nil,
+		nil,
+	)
+	c := &SignerRotationController{
+		operatorClient:  operatorClient,
+		secretClient:    kubeClient.CoreV1(),
+		configMapClient: kubeClient.CoreV1(),
+		signers: []rotatableSigner{{
+			conditionName: "Test",
+			signer:        "signer",
+			caBundle:      "signer-ca",
+			trustedBy: []resourceLocation{
+				{namespace: targetNamespace, name: "server-ca", revisioned: true},
+				{namespace: managedNamespace, name: "server-ca"},
+			},
+			targets:             []resourceLocation{{namespace: targetNamespace, name: "target"}},
+			unobservedConsumers: []string{"the test consumers"},
+		}},
+	}
+	syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))
+
+	getSecret := func(namespace, name string) *corev1.Secret {
+		secret, err := kubeClient.CoreV1().Secrets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return secret
+	}
+	updateSecret := func(secret *corev1.Secret) {
+		if _, err := kubeClient.CoreV1().Secrets(secret.Namespace).Update(context.TODO(), secret, metav1.UpdateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	applyConfigMap := func(configMap *corev1.ConfigMap) {
+		if _, err := kubeClient.CoreV1().ConfigMaps(configMap.Namespace).Update(context.TODO(), configMap, metav1.UpdateOptions{}); err != nil {
+			if _, err := kubeClient.CoreV1().ConfigMaps(configMap.Namespace).Create(context.TODO(), configMap, metav1.CreateOptions{}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	syncAndExpect := func(status operatorv1.ConditionStatus, reason, phase string) {
+		t.Helper()
+		if err := c.sync(context.TODO(), syncCtx); err != nil {
+			t.Fatal(err)
+		}
+		_, operatorStatus, _, err := operatorClient.GetStaticPodOperatorState()
+		if err != nil {
+			t.Fatal(err)
+		}
+		condition := v1helpers.FindOperatorCondition(operatorStatus.Conditions, "SignerRotation_Test_Progressing")
+		if condition == nil {
+			t.Fatal("expected condition SignerRotation_Test_Progressing to be set")
+		}
+		if condition.Status != status || condition.Reason != reason {
+			t.Errorf("expected %s %s, got %s %s: %s", status, reason, condition.Status, condition.Reason, condition.Message)
+		}
+		if actual := getSecret(operatorNamespace, "signer").Annotations[RotationPhaseAnnotation]; actual != phase {
+			t.Errorf("expected phase %q, got %q", phase, actual)
+		}
+	}
+
+	// the rotation starts by dropping the not-after annotation of the signer
+	syncAndExpect(operatorv1.ConditionTrue, PhaseDistributingCABundle, PhaseDistributingCABundle)
+	signer := getSecret(operatorNamespace, "signer")
+	if _, ok := signer.Annotations[certrotation.CertificateNotAfterAnnotation]; ok {
+		t.Error("expected the not-after annotation of the signer to be removed")
+	}
+	if signer.Annotations[PreviousSignerAnnotation] != "old-signer" {
+		t.Errorf("expected the previous signer to be recorded, got %q", signer.Annotations[PreviousSignerAnnotation])
+	}
+	syncAndExpect(operatorv1.ConditionTrue, PhaseDistributingCABundle, PhaseDistributingCABundle)
+
+	// the cert rotation controller generates a new signer, the target config controller combines the CA bundle
+	signer.Data[corev1.TLSCertKey] = []byte(newSigner)
+	updateSecret(signer)
+	applyConfigMap(caBundle(operatorNamespace, "signer-ca", newSigner+oldSigner))
+	applyConfigMap(caBundle(targetNamespace, "server-ca", newSigner+oldSigner))
+	applyConfigMap(caBundle(managedNamespace, "server-ca", newSigner+oldSigner))
+	syncAndExpect(operatorv1.ConditionTrue, PhaseDistributingCABundle, PhaseDistributingCABundle)
+
+	// the node runs a revision trusting the new signer
+	applyConfigMap(caBundle(targetNamespace, "server-ca-2", newSigner+oldSigner))
+	if _, _, err := v1helpers.UpdateStaticPodStatus(context.TODO(), operatorClient, func(status *operatorv1.StaticPodOperatorStatus) error {
+		status.NodeStatuses[0].CurrentRevision = 2
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	syncAndExpect(operatorv1.ConditionTrue, PhaseDistributingCABundle, PhaseDistributingCABundle)
+
+	// the consumers trust the new signer once the admin confirmed it, a confirmation of another signer does not count
+	signer = getSecret(operatorNamespace, "signer")
+	signer.Annotations[ConsumersTrustSignerAnnotation] = "old-signer"
+	updateSecret(signer)
+	syncAndExpect(operatorv1.ConditionTrue, PhaseDistributingCABundle, PhaseDistributingCABundle)
+	signer = getSecret(operatorNamespace, "signer")
+	signer.Annotations[ConsumersTrustSignerAnnotation] = "new-signer"
+	updateSecret(signer)
+	syncAndExpect(operatorv1.ConditionTrue, PhaseReissuingTargets, PhaseReissuingTargets)
+
+	// the target issued by the previous signer is re-issued
+	syncAndExpect(operatorv1.ConditionTrue, PhaseReissuingTargets, PhaseReissuingTargets)
+	target := getSecret(targetNamespace, "target")
+	if _, ok := target.Annotations[certrotation.CertificateNotAfterAnnotation]; ok {
+		t.Error("expected the not-after annotation of the target to be removed")
+	}
+	target.Annotations[certrotation.CertificateIssuer] = "new-signer"
+	updateSecret(target)
+	syncAndExpect(operatorv1.ConditionTrue, PhaseRemovingPreviousSigner, PhaseRemovingPreviousSigner)
+
+	// the previous signer is removed from the CA bundle
+	syncAndExpect(operatorv1.ConditionFalse, "AsExpected", "")
+	configMap, err := kubeClient.CoreV1().ConfigMaps(operatorNamespace).Get(context.TODO(), "signer-ca", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	certs, err := cert.ParseCertsPEM([]byte(configMap.Data["ca-bundle.crt"]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(certs) != 1 || certs[0].Subject.CommonName != "new-signer" {
+		t.Errorf("expected the CA bundle to contain only the new signer, got %d certificates", len(certs))
+	}
+	signer = getSecret(operatorNamespace, "signer")
+	if signer.Annotations[RotationCompletedAnnotation] != "1" {
+		t.Errorf("expected the rotation to be completed, got %q", signer.Annotations[RotationCompletedAnnotation])
+	}
+	if _, ok := signer.Annotations[PreviousSignerAnnotation]; ok {
+		t.Error("expected the previous signer annotation to be removed")
+	}
+	if _, ok := signer.Annotations[ConsumersTrustSignerAnnotation]; ok {
+		t.Error("expected the consumers trust annotation to be removed")
+	}
+
+	// nothing happens until another rotation is requested
+	syncAndExpect(operatorv1.ConditionFalse, "AsExpected", "")
+}
@@ -12,7 +12,9 @@ import (
 	configv1client "github.com/openshift/client-go/config/clientset/versioned"
 	configv1informers "github.com/openshift/client-go/config/informers/externalversions"
 	operatorcontrolplaneclient "github.com/openshift/client-go/operatorcontrolplane/clientset/versioned"
+	operatorcontrolplaneinformers "github.com/openshift/client-go/operatorcontrolplane/informers/externalversions"
 	"github.com/openshift/cluster-kube-apiserver-operator/bindata"
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/apiserviceavailabilitycontroller"
 	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/boundsatokensignercontroller"
 	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/certrotationcontroller"
 	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/certrotationtimeupgradeablecontroller"
@@ -20,11 +22,15 @@ import (
 	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/configobservation/configobservercontroller"
 	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/configobservation/node"
 	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/connectivitycheckcontroller"
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/externalsigner"
 	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/featureupgradablecontroller"
 	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/kubeletversionskewcontroller"
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/namedcertificatescontroller"
 	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/nodekubeconfigcontroller"
 	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/outagehistorycontroller"
 	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/resourcesynccontroller"
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/signerrotationcontroller"
 	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/startupmonitorreadiness"
 	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/targetconfigcontroller"
 	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/terminationobserver"
@@ -58,8 +64,11 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
+	apiregistrationclient "k8s.io/kube-aggregator/pkg/client/clientset_generated/clientset"
+	apiregistrationinformers "k8s.io/kube-aggregator/pkg/client/informers/externalversions"
 	kubemigratorclient "sigs.k8s.io/kube-storage-version-migrator/pkg/clients/clientset"
 	migrationv1alpha1informer "sigs.k8s.io/kube-storage-version-migrator/pkg/clients/informer"
 )
@@ -90,6 +99,10 @@ func RunOperator(ctx context.Context, controllerContext *controllercmd.Controlle
 	if err != nil {
 		return err
 	}
+	apiregistrationClient, err := apiregistrationclient.NewForConfig(controllerContext.KubeConfig)
+	if err != nil {
+		return err
+	}
 	kubeInformersForNamespaces := v1helpers.NewKubeInformersForNamespaces(
 		kubeClient,
 		"",
@@ -102,6 +115,7 @@ func RunOperator(ctx context.Context, controllerContext *controllercmd.Controlle
 		"openshift-apiserver",
 	)
 	configInformers := configv1informers.NewSharedInformerFactory(configClient, 10*time.Minute)
+	dnsInformers := dynamicinformer.NewDynamicSharedInformerFactory(dynamicClient, 10*time.Minute)
 	operatorClient, dynamicInformers, err := genericoperatorclient.NewStaticPodOperatorClient(controllerContext.KubeConfig, operatorv1.GroupVersion.WithResource("kubeapiservers"))
 	if err != nil {
 		return err
@@ -152,6 +166,7 @@ func RunOperator(ctx context.Context, controllerContext *controllercmd.Controlle
 		"assets/kube-apiserver/storage-version-migration-prioritylevelconfiguration.yaml",
 		"assets/alerts/api-usage.yaml",
 		"assets/alerts/audit-errors.yaml",
+		"assets/alerts/certificates.yaml",
 		"assets/alerts/cpu-utilization.yaml",
 		"assets/alerts/kube-apiserver-requests.yaml",
 		"assets/alerts/kube-apiserver-slos-basic.yaml",
@@ -195,15 +210,34 @@ func RunOperator(ctx context.Context, controllerContext *controllercmd.Controlle
 		controllerContext.EventRecorder,
 	)
 
+	namedCertificatesController := namedcertificatescontroller.NewNamedCertificatesController(
+		operatorClient,
+		kubeInformersForNamespaces,
+		kubeClient,
+		configInformers.Config().V1().APIServers(),
+		controllerContext.EventRecorder,
+	)
+
 	apiextensionsInformers := apiextensionsinformers.NewSharedInformerFactory(apiextensionsClient, 10*time.Minute)
+	apiregistrationInformers := apiregistrationinformers.NewSharedInformerFactory(apiregistrationClient, 10*time.Minute)
+	operatorcontrolplaneInformers := operatorcontrolplaneinformers.NewSharedInformerFactoryWithOptions(operatorcontrolplaneClient, 10*time.Minute,
+		operatorcontrolplaneinformers.WithNamespace(operatorclient.TargetNamespace))
 	connectivityCheckController := connectivitycheckcontroller.NewKubeAPIServerConnectivityCheckController(
-		kubeClient,
 		operatorClient,
 		apiextensionsClient,
 		kubeInformersForNamespaces,
 		operatorcontrolplaneClient,
+		operatorcontrolplaneInformers.Controlplane().V1alpha1().PodNetworkConnectivityChecks(),
 		configInformers,
 		apiextensionsInformers,
+		apiregistrationInformers,
+		controllerContext.EventRecorder,
+	)
+
+	outageHistoryController := outagehistorycontroller.NewOutageHistoryController(
+		operatorClient,
+		operatorcontrolplaneInformers.Controlplane().V1alpha1().PodNetworkConnectivityChecks(),
+		kubeClient.CoreV1(),
 		controllerContext.EventRecorder,
 	)
 
@@ -272,18 +306,63 @@ func RunOperator(ctx context.Context, controllerContext *controllercmd.Controlle
 		return err
 	}
 
+	defaultCertRotationConfig := certrotationcontroller.DefaultRotationConfig(certRotationScale)
+	certRotationConfig, err := certrotationcontroller.GetRotationConfig(ctx, kubeClient, defaultCertRotationConfig)
+	if err != nil {
+		// the cert rotation config controller reports the invalid config
+		klog.Warningf("Using the default certificate rotation periods: %v", err)
+		certRotationConfig = defaultCertRotationConfig
+	}
+
+	var externalSigner externalsigner.Signer
+	externalSignerConfig, err := externalsigner.GetConfig(ctx, kubeClient)
+	if err != nil {
+		// the external signer config controller reports the invalid config
+		klog.Warningf("Signing the rotated certificates with the in-cluster signers: %v", err)
+		externalSignerConfig = nil
+	}
+	if externalSignerConfig != nil {
+		if externalSigner, err = externalsigner.NewHTTPSigner(externalSignerConfig); err != nil {
+			return err
+		}
+		klog.Infof("Signing the rotated certificates with the external signer at %s", externalSignerConfig.URL)
+	}
+
 	certRotationController, err := certrotationcontroller.NewCertRotationController(
 		kubeClient,
 		operatorClient,
 		configInformers,
+		dnsInformers.ForResource(certrotationcontroller.DNSResource),
 		kubeInformersForNamespaces,
 		controllerContext.EventRecorder.WithComponentSuffix("cert-rotation-controller"),
-		certRotationScale,
+		certRotationConfig,
+		externalSigner,
 	)
 	if err != nil {
 		return err
 	}
 
+	externalSignerConfigController := externalsigner.NewConfigController(
+		externalSignerConfig,
+		operatorClient,
+		kubeInformersForNamespaces,
+		controllerContext.EventRecorder,
+	)
+
+	certRotationConfigController := certrotationcontroller.NewRotationConfigController(
+		certRotationConfig,
+		defaultCertRotationConfig,
+		operatorClient,
+		kubeInformersForNamespaces,
+		controllerContext.EventRecorder,
+	)
+
+	loadBalancerServingConfigController := certrotationcontroller.NewLoadBalancerServingConfigController(
+		operatorClient,
+		kubeInformersForNamespaces,
+		controllerContext.EventRecorder,
+	)
+
 	staticPodNodeProvider := encryptiondeployer.StaticPodNodeProvider{OperatorClient: operatorClient}
 	deployer, err := encryptiondeployer.NewRevisionLabelPodDeployer("revision", operatorclient.TargetNamespace, kubeInformersForNamespaces, kubeClient.CoreV1(), kubeClient.CoreV1(), staticPodNodeProvider)
 	if err != nil {
@@ -340,6 +419,13 @@ func RunOperator(ctx context.Context, controllerContext *controllercmd.Controlle
 		controllerContext.EventRecorder,
 	)
 
+	signerRotationController := signerrotationcontroller.NewSignerRotationController(
+		operatorClient,
+		kubeInformersForNamespaces,
+		kubeClient,
+		controllerContext.EventRecorder,
+	)
+
 	auditPolicyController := auditpolicy.NewAuditPolicyController(
 		operatorclient.TargetNamespace,
 		"kube-apiserver-audit-policies",
@@ -392,38 +478,64 @@ func RunOperator(ctx context.Context, controllerContext *controllercmd.Controlle
 		controllerContext.EventRecorder,
 	)
 
+	apiServiceAvailabilityController := apiserviceavailabilitycontroller.NewAPIServiceAvailabilityController(
+		operatorClient,
+		kubeInformersForNamespaces,
+		apiregistrationInformers,
+		controllerContext.EventRecorder,
+	)
+
 	// register termination metrics
 	terminationobserver.RegisterMetrics()
 
 	// register config metrics
 	configmetrics.Register(configInformers)
 
+	// register bound service account token signing key metrics
+	boundsatokensignercontroller.RegisterMetrics(kubeInformersForNamespaces)
+
+	// register aggregated apiservice availability metrics
+	apiserviceavailabilitycontroller.RegisterMetrics()
+
+	// register certificate expiry metrics
+	certrotationcontroller.RegisterMetrics(certRotationController.Inventory(), kubeInformersForNamespaces)
+
 	kubeInformersForNamespaces.Start(ctx.Done())
 	configInformers.Start(ctx.Done())
 	dynamicInformers.Start(ctx.Done())
 	migrationInformer.Start(ctx.Done())
 	apiextensionsInformers.Start(ctx.Done())
+	apiregistrationInformers.Start(ctx.Done())
+	operatorcontrolplaneInformers.Start(ctx.Done())
+	dnsInformers.Start(ctx.Done())
 
 	go staticPodControllers.Start(ctx)
 	go resourceSyncController.Run(ctx, 1)
 	go staticResourceController.Run(ctx, 1)
 	go targetConfigReconciler.Run(ctx, 1)
 	go nodeKubeconfigController.Run(ctx, 1)
+	go namedCertificatesController.Run(ctx, 1)
 	go configObserver.Run(ctx, 1)
 	go clusterOperatorStatus.Run(ctx, 1)
 	go certRotationController.Run(ctx, 1)
+	go certRotationConfigController.Run(ctx, 1)
+	go loadBalancerServingConfigController.Run(ctx, 1)
+	go externalSignerConfigController.Run(ctx, 1)
 	go encryptionControllers.Run(ctx, 1)
 	go featureUpgradeableController.Run(ctx, 1)
 	go certRotationTimeUpgradeableController.Run(ctx, 1)
 	go terminationObserver.Run(ctx, 1)
 	go eventWatcher.Run(ctx, 1)
 	go boundSATokenSignerController.Run(ctx, 1)
+	go signerRotationController.Run(ctx, 1)
 	go auditPolicyController.Run(ctx, 1)
 	go staleConditionsController.Run(ctx, 1)
 	go connectivityCheckController.Run(ctx, 1)
+	go outageHistoryController.Run(ctx, 1)
 	go kubeletVersionSkewController.Run(ctx, 1)
 	go latencyProfileController.Run(ctx, 1)
 	go webhookSupportabilityController.Run(ctx, 1)
+	go apiServiceAvailabilityController.Run(ctx, 1)
 
 	<-ctx.Done()
 	return nil
@@ -552,14 +664,6 @@ var CertSecrets = []installer.UnrevisionedResource{
 	{Name: "node-kubeconfigs"},
 
 	{Name: "user-serving-cert", Optional: true},
-	{Name: "user-serving-cert-000", Optional: true},
-	{Name: "user-serving-cert-001", Optional: true},
-	{Name: "user-serving-cert-002", Optional: true},
-	{Name: "user-serving-cert-003", Optional: true},
-	{Name: "user-serving-cert-004", Optional: true},
-	{Name: "user-serving-cert-005", Optional: true},
-	{Name: "user-serving-cert-006", Optional: true},
-	{Name: "user-serving-cert-007", Optional: true},
-	{Name: "user-serving-cert-008", Optional: true},
-	{Name: "user-serving-cert-009", Optional: true},
+	// all user named serving certificates, keyed by the name of the user secret
+	{Name: namedcertificatescontroller.BundleSecretName, Optional: true},
 }
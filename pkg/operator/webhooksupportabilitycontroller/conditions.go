@@ -16,6 +16,10 @@ const (
 	// VirtualResourceAdmissionDegradedType is true when a dynamic admission webhook matches
 	// a virtual resource.
 	VirtualResourceAdmissionDegradedType = "VirtualResourceAdmissionError"
+
+	// AdmissionWebhookMayBlockControlPlaneDegradedType is true when a dynamic admission webhook failing
+	// closed intercepts resources the control plane depends on.
+	AdmissionWebhookMayBlockControlPlaneDegradedType = "AdmissionWebhookMayBlockControlPlaneError"
 )
 
 const (
@@ -26,6 +30,26 @@ const (
 	// could not be established.
 	WebhookServiceConnectionErrorReason = "WebhookServiceConnectionError"
 
+	// WebhookCABundleExpiredReason indicates that all certificates of a webhook caBundle expired.
+	WebhookCABundleExpiredReason = "WebhookCABundleExpired"
+
+	// WebhookCABundleExpiringReason indicates that all certificates of a webhook caBundle expire soon.
+	WebhookCABundleExpiringReason = "WebhookCABundleExpiring"
+
+	// WebhookServingCertNotSignedByCABundleReason indicates that the serving certificate of a webhook
+	// is not signed by the webhook caBundle.
+	WebhookServingCertNotSignedByCABundleReason = "WebhookServingCertNotSignedByCABundle"
+
+	// WebhookServingCertHostnameMismatchReason indicates that the serving certificate of a webhook is not
+	// valid for the host name of the webhook.
+	WebhookServingCertHostnameMismatchReason = "WebhookServingCertHostnameMismatch"
+
+	// WebhookServingCertExpiredReason indicates that the serving certificate of a webhook expired.
+	WebhookServingCertExpiredReason = "WebhookServingCertExpired"
+
+	// WebhookServingCertExpiringReason indicates that the serving certificate of a webhook expires soon.
+	WebhookServingCertExpiringReason = "WebhookServingCertExpiring"
+
 	// WebhookServiceNotReadyReason indicates that webhook services are having a variety of
 	// problems.
 	WebhookServiceNotReadyReason = "WebhookServiceNotReady"
@@ -33,4 +57,8 @@ const (
 	// AdmissionWebhookMatchesVirtualResourceReason indicates that an admission webhook matches
 	// a virtual resource.
 	AdmissionWebhookMatchesVirtualResourceReason = "AdmissionWebhookMatchesVirtualResource"
+
+	// AdmissionWebhookMayBlockControlPlaneReason indicates that an admission webhook with failurePolicy=Fail
+	// intercepts resources the control plane depends on.
+	AdmissionWebhookMayBlockControlPlaneReason = "AdmissionWebhookMayBlockControlPlane"
 )
@@ -4,8 +4,10 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"errors"
 	"fmt"
 	"net"
+	"net/url"
 	"sort"
 	"strings"
 	"time"
@@ -13,6 +15,8 @@ import (
 	operatorv1 "github.com/openshift/api/operator/v1"
 	"github.com/openshift/library-go/pkg/operator/v1helpers"
 	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/cert"
 	"k8s.io/klog/v2"
 )
 
@@ -20,6 +24,7 @@ import (
 type webhookInfo struct {
 	Name                  string
 	Service               *serviceReference
+	URL                   string
 	CABundle              []byte
 	FailurePolicyIsIgnore bool
 }
@@ -31,59 +36,159 @@ type serviceReference struct {
 	Port      *int32
 }
 
+const (
+	// caBundleExpiryWarningPeriod is how long before the expiry of a caBundle it is reported.
+	caBundleExpiryWarningPeriod = 30 * 24 * time.Hour
+	// servingCertExpiryWarningPeriod is how long before the expiry of a serving certificate it is reported.
+	servingCertExpiryWarningPeriod = 7 * 24 * time.Hour
+)
+
+// webhookError is a problem with a webhook and the reason reported for it.
+type webhookError struct {
+	reason string
+	err    error
+}
+
+func (e *webhookError) Error() string {
+	return e.err.Error()
+}
+
 // updateWebhookConfigurationDegraded updates the condition specified after
-// checking that the services associated with the specified webhooks exist
-// and have at least one ready endpoint.
+// checking that the services associated with the specified webhooks exist,
+// can be connected to, and serve a certificate valid for the webhook caBundle.
 func (c *webhookSupportabilityController) updateWebhookConfigurationDegraded(ctx context.Context, condition operatorv1.OperatorCondition, webhookInfos []webhookInfo) v1helpers.UpdateStatusFunc {
-	var serviceMsgs []string
-	var tlsMsgs []string
+	var msgs []string
+	reasons := sets.NewString()
 	for _, webhook := range webhookInfos {
-		if webhook.Service != nil {
-			err := c.assertService(webhook.Service)
-			if err != nil {
-				msg := fmt.Sprintf("%s: %s", webhook.Name, err)
-				if webhook.FailurePolicyIsIgnore {
-					klog.Error(msg)
-					continue
-				}
-				serviceMsgs = append(serviceMsgs, msg)
-				continue
-			}
-			err = c.assertConnect(ctx, webhook.Service, webhook.CABundle)
-			if err != nil {
-				msg := fmt.Sprintf("%s: %s", webhook.Name, err)
-				if webhook.FailurePolicyIsIgnore {
-					klog.Error(msg)
-					continue
-				}
-				tlsMsgs = append(tlsMsgs, msg)
-				continue
-			}
+		err := c.assertWebhook(ctx, webhook)
+		if err == nil {
+			continue
+		}
+		msg := fmt.Sprintf("%s: %s", webhook.Name, err)
+		if webhook.FailurePolicyIsIgnore {
+			klog.Error(msg)
+			continue
 		}
+		msgs = append(msgs, msg)
+		reasons.Insert(err.reason)
 	}
 
-	svc, tls := len(serviceMsgs) > 0, len(tlsMsgs) > 0
-	switch {
-	case svc && tls:
-		condition.Reason = WebhookServiceNotReadyReason
-		condition.Status = operatorv1.ConditionTrue
-	case svc:
-		condition.Reason = WebhookServiceNotFoundReason
-		condition.Status = operatorv1.ConditionTrue
-	case tls:
-		condition.Reason = WebhookServiceConnectionErrorReason
-		condition.Status = operatorv1.ConditionTrue
-	default:
+	switch reasons.Len() {
+	case 0:
 		condition.Reason = ""
 		condition.Status = operatorv1.ConditionFalse
+	case 1:
+		condition.Reason = reasons.List()[0]
+		condition.Status = operatorv1.ConditionTrue
+	default:
+		condition.Reason = WebhookServiceNotReadyReason
+		condition.Status = operatorv1.ConditionTrue
 	}
-	msgs := append(serviceMsgs, tlsMsgs...)
 	sort.Strings(msgs)
 	condition.Message = strings.Join(msgs, "\n")
 
 	return v1helpers.UpdateConditionFn(condition)
 }
 
+// assertWebhook checks that the webhook can be connected to and that its certificates are not about to expire.
+// URL webhooks on loopback or link-local hosts are only reachable from the host network of kube-apiserver,
+// only their caBundle is checked.
+func (c *webhookSupportabilityController) assertWebhook(ctx context.Context, webhook webhookInfo) *webhookError {
+	var host, port string
+	var unverifiable bool
+	switch {
+	case webhook.Service != nil:
+		if err := c.assertService(webhook.Service); err != nil {
+			return &webhookError{reason: WebhookServiceNotFoundReason, err: err}
+		}
+		host = webhook.Service.Name + "." + webhook.Service.Namespace + ".svc"
+		port = "443"
+		if webhook.Service.Port != nil {
+			port = fmt.Sprintf("%d", *webhook.Service.Port)
+		}
+	case len(webhook.URL) > 0:
+		u, err := url.Parse(webhook.URL)
+		if err != nil {
+			return &webhookError{reason: WebhookServiceConnectionErrorReason, err: err}
+		}
+		host, port = u.Hostname(), u.Port()
+		if len(port) == 0 {
+			port = "443"
+		}
+		unverifiable = isHostLocal(host)
+	default:
+		return nil
+	}
+
+	now := time.Now()
+	caNotAfter, err := caBundleNotAfter(webhook.CABundle)
+	if err != nil {
+		return &webhookError{reason: WebhookServiceConnectionErrorReason, err: err}
+	}
+	if !caNotAfter.IsZero() && now.After(caNotAfter) {
+		return &webhookError{reason: WebhookCABundleExpiredReason, err: fmt.Errorf("caBundle expired at %s", caNotAfter.UTC().Format(time.RFC3339))}
+	}
+
+	var servingCert *x509.Certificate
+	if unverifiable {
+		klog.V(4).Infof("%s: not connecting to %s, it is not reachable from the operator", webhook.Name, host)
+	} else if servingCert, err = c.assertConnect(ctx, host, port, webhook.CABundle); err != nil {
+		return &webhookError{reason: connectErrorReason(err), err: err}
+	}
+
+	if !caNotAfter.IsZero() && caNotAfter.Sub(now) < caBundleExpiryWarningPeriod {
+		return &webhookError{reason: WebhookCABundleExpiringReason, err: fmt.Errorf("caBundle expires at %s", caNotAfter.UTC().Format(time.RFC3339))}
+	}
+	if servingCert != nil && servingCert.NotAfter.Sub(now) < servingCertExpiryWarningPeriod {
+		return &webhookError{reason: WebhookServingCertExpiringReason, err: fmt.Errorf("serving certificate expires at %s", servingCert.NotAfter.UTC().Format(time.RFC3339))}
+	}
+	return nil
+}
+
+// isHostLocal returns true if host is localhost or a loopback or link-local IP address.
+func isHostLocal(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && (ip.IsLoopback() || ip.IsLinkLocalUnicast())
+}
+
+// caBundleNotAfter returns the latest expiry of the certificates in the caBundle, or the zero time if it is empty.
+func caBundleNotAfter(caBundle []byte) (time.Time, error) {
+	if len(caBundle) == 0 {
+		return time.Time{}, nil
+	}
+	certs, err := cert.ParseCertsPEM(caBundle)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid caBundle: %w", err)
+	}
+	var notAfter time.Time
+	for _, c := range certs {
+		if c.NotAfter.After(notAfter) {
+			notAfter = c.NotAfter
+		}
+	}
+	return notAfter, nil
+}
+
+// connectErrorReason returns the reason reported for an error returned by assertConnect.
+func connectErrorReason(err error) string {
+	var unknownAuthorityErr x509.UnknownAuthorityError
+	if errors.As(err, &unknownAuthorityErr) {
+		return WebhookServingCertNotSignedByCABundleReason
+	}
+	var hostnameErr x509.HostnameError
+	if errors.As(err, &hostnameErr) {
+		return WebhookServingCertHostnameMismatchReason
+	}
+	var invalidErr x509.CertificateInvalidError
+	if errors.As(err, &invalidErr) && invalidErr.Reason == x509.Expired {
+		return WebhookServingCertExpiredReason
+	}
+	return WebhookServiceConnectionErrorReason
+}
+
 // assertService checks that the referenced service resource exists.
 func (c *webhookSupportabilityController) assertService(reference *serviceReference) error {
 	_, err := c.serviceLister.Services(reference.Namespace).Get(reference.Name)
@@ -93,15 +198,12 @@ func (c *webhookSupportabilityController) assertService(reference *serviceRefere
 	return nil
 }
 
-// assertConnect performs a dns lookup of service, opens a tcp connection, and performs a tls handshake.
-func (c *webhookSupportabilityController) assertConnect(ctx context.Context, reference *serviceReference, caBundle []byte) error {
-	host := reference.Name + "." + reference.Namespace + ".svc"
-	port := "443"
-	if reference.Port != nil {
-		port = fmt.Sprintf("%d", *reference.Port)
-	}
-	rootCAs := x509.NewCertPool()
+// assertConnect performs a dns lookup of host, opens a tcp connection, and performs a tls handshake. It returns
+// the serving certificate. Like kube-apiserver, the system trust roots are used if the caBundle is empty.
+func (c *webhookSupportabilityController) assertConnect(ctx context.Context, host, port string, caBundle []byte) (*x509.Certificate, error) {
+	var rootCAs *x509.CertPool
 	if len(caBundle) > 0 {
+		rootCAs = x509.NewCertPool()
 		rootCAs.AppendCertsFromPEM(caBundle)
 	}
 	// the last error that occurred in the loop below
@@ -110,7 +212,7 @@ func (c *webhookSupportabilityController) assertConnect(ctx context.Context, ref
 	for i := 0; i < 3; i++ {
 		select {
 		case <-ctx.Done():
-			return nil
+			return nil, nil
 		case <-time.After(time.Duration(i) * time.Second):
 		}
 		dialer := &tls.Dialer{
@@ -129,9 +231,13 @@ func (c *webhookSupportabilityController) assertConnect(ctx context.Context, ref
 			}
 			continue
 		}
+		var servingCert *x509.Certificate
+		if peerCertificates := conn.(*tls.Conn).ConnectionState().PeerCertificates; len(peerCertificates) > 0 {
+			servingCert = peerCertificates[0]
+		}
 		// error from closing connection should not affect Degraded condition
 		runtime.HandleError(conn.Close())
-		break
+		return servingCert, nil
 	}
-	return err
+	return nil, err
 }
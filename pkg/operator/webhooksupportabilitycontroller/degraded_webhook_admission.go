@@ -35,6 +35,9 @@ func (c *webhookSupportabilityController) updateMutatingAdmissionWebhookConfigur
 					Port:      webhook.ClientConfig.Service.Port,
 				}
 			}
+			if webhook.ClientConfig.URL != nil {
+				info.URL = *webhook.ClientConfig.URL
+			}
 			webhookInfos = append(webhookInfos, info)
 		}
 	}
@@ -67,6 +70,9 @@ func (c *webhookSupportabilityController) updateValidatingAdmissionWebhookConfig
 					Port:      webhook.ClientConfig.Service.Port,
 				}
 			}
+			if webhook.ClientConfig.URL != nil {
+				info.URL = *webhook.ClientConfig.URL
+			}
 			webhookInfos = append(webhookInfos, info)
 		}
 	}
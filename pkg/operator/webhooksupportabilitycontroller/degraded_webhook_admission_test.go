@@ -175,7 +175,7 @@ func TestUpdateMutatingAdmissionWebhookConfigurationDegraded(t *testing.T) {
 			expected: operatorv1.OperatorCondition{
 				Type:    MutatingAdmissionWebhookConfigurationDegradedType,
 				Status:  operatorv1.ConditionTrue,
-				Reason:  WebhookServiceConnectionErrorReason,
+				Reason:  WebhookServingCertNotSignedByCABundleReason,
 				Message: `mw10: (?:.*)?x509: certificate signed by unknown authority`,
 			},
 		},
@@ -381,7 +381,7 @@ func TestUpdateValidatingAdmissionWebhookConfigurationDegradedStatus(t *testing.
 			expected: operatorv1.OperatorCondition{
 				Type:    ValidatingAdmissionWebhookConfigurationDegradedType,
 				Status:  operatorv1.ConditionTrue,
-				Reason:  WebhookServiceConnectionErrorReason,
+				Reason:  WebhookServingCertNotSignedByCABundleReason,
 				Message: `mw10: (?:.*)?x509: certificate signed by unknown authority`,
 			},
 		},
@@ -0,0 +1,288 @@
+package webhooksupportabilitycontroller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// controlPlaneResource is a resource the control plane cannot operate without writing.
+type controlPlaneResource struct {
+	schema.GroupVersionResource
+	namespaced bool
+	operations []admissionregistrationv1.OperationType
+}
+
+var controlPlaneResources = []controlPlaneResource{
+	// leader election
+	{
+		GroupVersionResource: schema.GroupVersionResource{Group: "coordination.k8s.io", Version: "v1", Resource: "leases"},
+		namespaced:           true,
+		operations:           []admissionregistrationv1.OperationType{admissionregistrationv1.Create, admissionregistrationv1.Update},
+	},
+	// service endpoints, including those of webhooks and aggregated apiservers
+	{
+		GroupVersionResource: schema.GroupVersionResource{Group: "", Version: "v1", Resource: "endpoints"},
+		namespaced:           true,
+		operations:           []admissionregistrationv1.OperationType{admissionregistrationv1.Create, admissionregistrationv1.Update},
+	},
+	{
+		GroupVersionResource: schema.GroupVersionResource{Group: "discovery.k8s.io", Version: "v1", Resource: "endpointslices"},
+		namespaced:           true,
+		operations:           []admissionregistrationv1.OperationType{admissionregistrationv1.Create, admissionregistrationv1.Update},
+	},
+	// authentication of every component using a bearer token
+	{
+		GroupVersionResource: schema.GroupVersionResource{Group: "authentication.k8s.io", Version: "v1", Resource: "tokenreviews"},
+		operations:           []admissionregistrationv1.OperationType{admissionregistrationv1.Create},
+	},
+}
+
+// podsResource is checked in the namespace of the webhook service, a webhook failing closed on the creation
+// of its own pods cannot recover once all of them are gone.
+var podsResource = controlPlaneResource{
+	GroupVersionResource: schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"},
+	namespaced:           true,
+	operations:           []admissionregistrationv1.OperationType{admissionregistrationv1.Create},
+}
+
+// admissionWebhook holds the fields shared by mutating and validating webhooks.
+type admissionWebhook struct {
+	kind              string
+	name              string
+	failurePolicy     *admissionregistrationv1.FailurePolicyType
+	matchPolicy       *admissionregistrationv1.MatchPolicyType
+	namespaceSelector *metav1.LabelSelector
+	rules             []admissionregistrationv1.RuleWithOperations
+	service           *admissionregistrationv1.ServiceReference
+}
+
+func (c *webhookSupportabilityController) updateAdmissionWebhookMayBlockControlPlaneDegraded(ctx context.Context) v1helpers.UpdateStatusFunc {
+	condition := operatorv1.OperatorCondition{
+		Type:   AdmissionWebhookMayBlockControlPlaneDegradedType,
+		Status: operatorv1.ConditionUnknown,
+	}
+	var webhooks []admissionWebhook
+	mutatingWebhookConfigurations, err := c.mutatingWebhookLister.List(labels.Everything())
+	if err != nil {
+		condition.Message = err.Error()
+		return v1helpers.UpdateConditionFn(condition)
+	}
+	for _, config := range mutatingWebhookConfigurations {
+		for _, webhook := range config.Webhooks {
+			webhooks = append(webhooks, admissionWebhook{
+				kind:              "Mutating",
+				name:              webhook.Name,
+				failurePolicy:     webhook.FailurePolicy,
+				matchPolicy:       webhook.MatchPolicy,
+				namespaceSelector: webhook.NamespaceSelector,
+				rules:             webhook.Rules,
+				service:           webhook.ClientConfig.Service,
+			})
+		}
+	}
+	validatingWebhookConfigurations, err := c.validatingWebhookLister.List(labels.Everything())
+	if err != nil {
+		condition.Message = err.Error()
+		return v1helpers.UpdateConditionFn(condition)
+	}
+	for _, config := range validatingWebhookConfigurations {
+		for _, webhook := range config.Webhooks {
+			webhooks = append(webhooks, admissionWebhook{
+				kind:              "Validating",
+				name:              webhook.Name,
+				failurePolicy:     webhook.FailurePolicy,
+				matchPolicy:       webhook.MatchPolicy,
+				namespaceSelector: webhook.NamespaceSelector,
+				rules:             webhook.Rules,
+				service:           webhook.ClientConfig.Service,
+			})
+		}
+	}
+	namespaces, err := c.namespaceLister.List(labels.Everything())
+	if err != nil {
+		condition.Message = err.Error()
+		return v1helpers.UpdateConditionFn(condition)
+	}
+	var controlPlaneNamespaces []*corev1.Namespace
+	for _, namespace := range namespaces {
+		if strings.HasPrefix(namespace.Name, "openshift-") || strings.HasPrefix(namespace.Name, "kube-") {
+			controlPlaneNamespaces = append(controlPlaneNamespaces, namespace)
+		}
+	}
+
+	var msgs []string
+	for _, webhook := range webhooks {
+		if webhook.failurePolicy != nil && *webhook.failurePolicy == admissionregistrationv1.Ignore {
+			continue
+		}
+		msgs = append(msgs, webhookControlPlaneRisks(webhook, controlPlaneNamespaces, namespaces)...)
+	}
+
+	if len(msgs) > 0 {
+		sort.Strings(msgs)
+		condition.Message = strings.Join(msgs, "\n")
+		condition.Reason = AdmissionWebhookMayBlockControlPlaneReason
+		condition.Status = operatorv1.ConditionTrue
+	} else {
+		condition.Status = operatorv1.ConditionFalse
+	}
+
+	return v1helpers.UpdateConditionFn(condition)
+}
+
+// webhookControlPlaneRisks returns a message for each control plane resource the failing closed webhook
+// intercepts. Object selectors are ignored, they usually cannot be evaluated ahead of time.
+func webhookControlPlaneRisks(webhook admissionWebhook, controlPlaneNamespaces, namespaces []*corev1.Namespace) []string {
+	selector := labels.Everything()
+	if webhook.namespaceSelector != nil {
+		var err error
+		selector, err = metav1.LabelSelectorAsSelector(webhook.namespaceSelector)
+		if err != nil {
+			// kube-apiserver refuses to create webhooks with an invalid selector
+			return nil
+		}
+	}
+	var msgs []string
+	for _, resource := range controlPlaneResources {
+		rule, ok := webhookRuleMatching(webhook, resource)
+		if !ok {
+			continue
+		}
+		if !resource.namespaced {
+			msgs = append(msgs, fmt.Sprintf("%s webhook %s fails closed on %s with rule %s.", webhook.kind, webhook.name, resourceName(resource), formatRule(rule)))
+			continue
+		}
+		matched := selectedNamespaces(selector, controlPlaneNamespaces)
+		if len(matched) == 0 {
+			continue
+		}
+		msgs = append(msgs, fmt.Sprintf("%s webhook %s fails closed on %s in %s with rule %s.", webhook.kind, webhook.name, resourceName(resource), formatNamespaces(matched), formatRule(rule)))
+	}
+	if webhook.service != nil {
+		if rule, ok := webhookRuleMatching(webhook, podsResource); ok {
+			for _, namespace := range namespaces {
+				if namespace.Name == webhook.service.Namespace && selector.Matches(labels.Set(namespace.Labels)) {
+					msgs = append(msgs, fmt.Sprintf("%s webhook %s fails closed on the pods of its own namespace %s with rule %s.", webhook.kind, webhook.name, namespace.Name, formatRule(rule)))
+				}
+			}
+		}
+	}
+	return msgs
+}
+
+// webhookRuleMatching returns the first rule of the webhook intercepting writes to the resource.
+func webhookRuleMatching(webhook admissionWebhook, resource controlPlaneResource) (admissionregistrationv1.RuleWithOperations, bool) {
+	// requests are converted to a version the webhook handles unless the match policy is Exact
+	exact := webhook.matchPolicy != nil && *webhook.matchPolicy == admissionregistrationv1.Exact
+	for _, rule := range webhook.rules {
+		if !ruleMatchesScope(rule, resource.namespaced) || !ruleMatchesOperations(rule, resource.operations) {
+			continue
+		}
+		if ruleMatchesWrites(rule, resource.GroupVersionResource, exact) {
+			return rule, true
+		}
+	}
+	return admissionregistrationv1.RuleWithOperations{}, false
+}
+
+// ruleMatchesWrites is like ruleMatchesResource but ignores rules that only match subresources, and
+// versions unless exact is set.
+func ruleMatchesWrites(rule admissionregistrationv1.RuleWithOperations, gvr schema.GroupVersionResource, exact bool) bool {
+	var group, version, resource bool
+	for _, g := range rule.APIGroups {
+		if g == "*" || g == gvr.Group {
+			group = true
+			break
+		}
+	}
+	version = !exact
+	for _, v := range rule.APIVersions {
+		if v == "*" || v == gvr.Version {
+			version = true
+			break
+		}
+	}
+	for _, rr := range rule.Resources {
+		switch rr {
+		case "*", "*/*", gvr.Resource, gvr.Resource + "/*":
+			resource = true
+		}
+	}
+	return group && version && resource
+}
+
+func ruleMatchesScope(rule admissionregistrationv1.RuleWithOperations, namespaced bool) bool {
+	if rule.Scope == nil {
+		return true
+	}
+	switch *rule.Scope {
+	case admissionregistrationv1.NamespacedScope:
+		return namespaced
+	case admissionregistrationv1.ClusterScope:
+		return !namespaced
+	}
+	return true
+}
+
+func ruleMatchesOperations(rule admissionregistrationv1.RuleWithOperations, operations []admissionregistrationv1.OperationType) bool {
+	for _, ruleOperation := range rule.Operations {
+		if ruleOperation == admissionregistrationv1.OperationAll {
+			return true
+		}
+		for _, operation := range operations {
+			if ruleOperation == operation {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func selectedNamespaces(selector labels.Selector, namespaces []*corev1.Namespace) []string {
+	var selected []string
+	for _, namespace := range namespaces {
+		if selector.Matches(labels.Set(namespace.Labels)) {
+			selected = append(selected, namespace.Name)
+		}
+	}
+	sort.Strings(selected)
+	return selected
+}
+
+func resourceName(resource controlPlaneResource) string {
+	return resource.GroupResource().String()
+}
+
+// formatNamespaces lists the first few namespaces to keep the condition message short.
+func formatNamespaces(namespaces []string) string {
+	const max = 3
+	switch {
+	case len(namespaces) == 1:
+		return "namespace " + namespaces[0]
+	case len(namespaces) <= max:
+		return "namespaces " + strings.Join(namespaces, ", ")
+	}
+	return fmt.Sprintf("namespaces %s and %d more", strings.Join(namespaces[:max], ", "), len(namespaces)-max)
+}
+
+func formatRule(rule admissionregistrationv1.RuleWithOperations) string {
+	var operations []string
+	for _, operation := range rule.Operations {
+		operations = append(operations, string(operation))
+	}
+	return fmt.Sprintf("operations=%s apiGroups=%s resources=%s",
+		strings.Join(operations, ","),
+		strings.Join(rule.APIGroups, ","),
+		strings.Join(rule.Resources, ","),
+	)
+}
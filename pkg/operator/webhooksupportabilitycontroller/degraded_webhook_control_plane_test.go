@@ -0,0 +1,203 @@
+package webhooksupportabilitycontroller
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	admissionregistrationv1listers "k8s.io/client-go/listers/admissionregistration/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestUpdateAdmissionWebhookMayBlockControlPlaneDegraded(t *testing.T) {
+	namespaces := []*corev1.Namespace{
+		namespace("default"),
+		namespace("kube-system"),
+		namespace("openshift-etcd"),
+		namespace("openshift-kube-apiserver"),
+		namespace("openshift-monitoring"),
+		namespace("openshift-sdn"),
+		namespace("third-party", "webhook", "enabled"),
+	}
+	ignore := admissionregistrationv1.Ignore
+	exact := admissionregistrationv1.Exact
+	clusterScope := admissionregistrationv1.ClusterScope
+
+	testCases := []struct {
+		name                     string
+		mutatingWebhookConfigs   []*admissionregistrationv1.MutatingWebhookConfiguration
+		validatingWebhookConfigs []*admissionregistrationv1.ValidatingWebhookConfiguration
+		expected                 operatorv1.OperatorCondition
+	}{
+		{
+			name: "NoHooks",
+			expected: operatorv1.OperatorCondition{
+				Type:   AdmissionWebhookMayBlockControlPlaneDegradedType,
+				Status: operatorv1.ConditionFalse,
+			},
+		},
+		{
+			name: "MatchAll",
+			mutatingWebhookConfigs: []*admissionregistrationv1.MutatingWebhookConfiguration{
+				mutatingWebhookConfiguration("mwc10",
+					withMutatingWebhook("mw10",
+						withMutatingRule(all, all, all),
+						withMutatingOperations(admissionregistrationv1.OperationAll),
+						withMutatingServiceReference("third-party", "webhook"),
+					),
+				),
+			},
+			expected: operatorv1.OperatorCondition{
+				Type:   AdmissionWebhookMayBlockControlPlaneDegradedType,
+				Status: operatorv1.ConditionTrue,
+				Reason: AdmissionWebhookMayBlockControlPlaneReason,
+				Message: regexp.QuoteMeta("Mutating webhook mw10 fails closed on endpoints in namespaces kube-system, openshift-etcd, openshift-kube-apiserver and 2 more with rule operations=* apiGroups=* resources=*.\n" +
+					"Mutating webhook mw10 fails closed on endpointslices.discovery.k8s.io in namespaces kube-system, openshift-etcd, openshift-kube-apiserver and 2 more with rule operations=* apiGroups=* resources=*.\n" +
+					"Mutating webhook mw10 fails closed on leases.coordination.k8s.io in namespaces kube-system, openshift-etcd, openshift-kube-apiserver and 2 more with rule operations=* apiGroups=* resources=*.\n" +
+					"Mutating webhook mw10 fails closed on the pods of its own namespace third-party with rule operations=* apiGroups=* resources=*.\n" +
+					"Mutating webhook mw10 fails closed on tokenreviews.authentication.k8s.io with rule operations=* apiGroups=* resources=*."),
+			},
+		},
+		{
+			name: "NamespaceSelector",
+			validatingWebhookConfigs: []*admissionregistrationv1.ValidatingWebhookConfiguration{
+				validatingWebhookConfiguration("vwc10",
+					withValidatingWebhook("excluded",
+						withValidatingRule("coordination.k8s.io", "v1", "leases"),
+						withValidatingOperations(admissionregistrationv1.Update),
+						withValidatingNamespaceSelector(metav1.LabelSelector{MatchLabels: map[string]string{"webhook": "enabled"}}),
+					),
+					withValidatingWebhook("included",
+						withValidatingRule("coordination.k8s.io", "v1", "leases"),
+						withValidatingOperations(admissionregistrationv1.Update),
+						withValidatingNamespaceSelector(metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+							{Key: "webhook", Operator: metav1.LabelSelectorOpDoesNotExist},
+						}}),
+					),
+				),
+			},
+			expected: operatorv1.OperatorCondition{
+				Type:    AdmissionWebhookMayBlockControlPlaneDegradedType,
+				Status:  operatorv1.ConditionTrue,
+				Reason:  AdmissionWebhookMayBlockControlPlaneReason,
+				Message: regexp.QuoteMeta("Validating webhook included fails closed on leases.coordination.k8s.io in namespaces kube-system, openshift-etcd, openshift-kube-apiserver and 2 more with rule operations=UPDATE apiGroups=coordination.k8s.io resources=leases."),
+			},
+		},
+		{
+			name: "NotRisky",
+			mutatingWebhookConfigs: []*admissionregistrationv1.MutatingWebhookConfiguration{
+				mutatingWebhookConfiguration("mwc10",
+					withMutatingWebhook("ignore",
+						withMutatingRule(all, all, all),
+						withMutatingOperations(admissionregistrationv1.OperationAll),
+						func(w *admissionregistrationv1.MutatingWebhook) { w.FailurePolicy = &ignore },
+					),
+					withMutatingWebhook("delete-only",
+						withMutatingRule(all, all, all),
+						withMutatingOperations(admissionregistrationv1.Delete),
+					),
+					withMutatingWebhook("subresource",
+						withMutatingRule("", "v1", "pods/exec,endpoints/status"),
+						withMutatingOperations(admissionregistrationv1.OperationAll),
+						withMutatingServiceReference("third-party", "webhook"),
+					),
+					withMutatingWebhook("exact-version",
+						withMutatingRule("coordination.k8s.io", "v1beta1", "leases"),
+						withMutatingOperations(admissionregistrationv1.OperationAll),
+						func(w *admissionregistrationv1.MutatingWebhook) { w.MatchPolicy = &exact },
+					),
+					withMutatingWebhook("cluster-scoped",
+						withMutatingRule(all, all, all),
+						withMutatingOperations(admissionregistrationv1.OperationAll),
+						func(w *admissionregistrationv1.MutatingWebhook) { w.Rules[0].Scope = &clusterScope },
+					),
+				),
+			},
+			expected: operatorv1.OperatorCondition{
+				Type:    AdmissionWebhookMayBlockControlPlaneDegradedType,
+				Status:  operatorv1.ConditionTrue,
+				Reason:  AdmissionWebhookMayBlockControlPlaneReason,
+				Message: regexp.QuoteMeta("Mutating webhook cluster-scoped fails closed on tokenreviews.authentication.k8s.io with rule operations=* apiGroups=* resources=*."),
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := webhookSupportabilityController{}
+
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			for _, o := range tc.validatingWebhookConfigs {
+				if err := indexer.Add(o); err != nil {
+					t.Fatal(err)
+				}
+			}
+			c.validatingWebhookLister = admissionregistrationv1listers.NewValidatingWebhookConfigurationLister(indexer)
+
+			indexer = cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			for _, o := range tc.mutatingWebhookConfigs {
+				if err := indexer.Add(o); err != nil {
+					t.Fatal(err)
+				}
+			}
+			c.mutatingWebhookLister = admissionregistrationv1listers.NewMutatingWebhookConfigurationLister(indexer)
+
+			indexer = cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			for _, o := range namespaces {
+				if err := indexer.Add(o); err != nil {
+					t.Fatal(err)
+				}
+			}
+			c.namespaceLister = corev1listers.NewNamespaceLister(indexer)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			result := c.updateAdmissionWebhookMayBlockControlPlaneDegraded(ctx)
+			status := &operatorv1.OperatorStatus{}
+			err := result(status)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(status.Conditions) != 1 {
+				t.Log(status)
+				t.Fatal("expected exactly one condition")
+			}
+			requireCondition(t, tc.expected, status.Conditions[0])
+		})
+	}
+}
+
+func namespace(n string, labels ...string) *corev1.Namespace {
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: n, Labels: map[string]string{}}}
+	for i := 0; i+1 < len(labels); i += 2 {
+		ns.Labels[labels[i]] = labels[i+1]
+	}
+	return ns
+}
+
+func withMutatingOperations(operations ...admissionregistrationv1.OperationType) func(*admissionregistrationv1.MutatingWebhook) {
+	return func(w *admissionregistrationv1.MutatingWebhook) {
+		for i := range w.Rules {
+			w.Rules[i].Operations = operations
+		}
+	}
+}
+
+func withValidatingOperations(operations ...admissionregistrationv1.OperationType) func(*admissionregistrationv1.ValidatingWebhook) {
+	return func(w *admissionregistrationv1.ValidatingWebhook) {
+		for i := range w.Rules {
+			w.Rules[i].Operations = operations
+		}
+	}
+}
+
+func withValidatingNamespaceSelector(selector metav1.LabelSelector) func(*admissionregistrationv1.ValidatingWebhook) {
+	return func(w *admissionregistrationv1.ValidatingWebhook) {
+		w.NamespaceSelector = &selector
+	}
+}
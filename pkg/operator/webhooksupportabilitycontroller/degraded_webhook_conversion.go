@@ -26,17 +26,22 @@ func (c *webhookSupportabilityController) updateCRDConversionWebhookConfiguratio
 			continue
 		}
 		clientConfig := conversion.Webhook.ClientConfig
-		if clientConfig == nil || clientConfig.Service == nil {
+		if clientConfig == nil {
 			continue
 		}
 		info := webhookInfo{
 			Name:     crd.Name,
 			CABundle: clientConfig.CABundle,
-			Service: &serviceReference{
+		}
+		if clientConfig.Service != nil {
+			info.Service = &serviceReference{
 				Namespace: clientConfig.Service.Namespace,
 				Name:      clientConfig.Service.Name,
 				Port:      clientConfig.Service.Port,
-			},
+			}
+		}
+		if clientConfig.URL != nil {
+			info.URL = *clientConfig.URL
 		}
 		webhookInfos = append(webhookInfos, info)
 	}
@@ -143,7 +143,7 @@ func TestUpdateCRDConversionWebhookConfigurationDegraded(t *testing.T) {
 			expected: operatorv1.OperatorCondition{
 				Type:    CRDConversionWebhookConfigurationDegradedType,
 				Status:  operatorv1.ConditionTrue,
-				Reason:  WebhookServiceConnectionErrorReason,
+				Reason:  WebhookServingCertNotSignedByCABundleReason,
 				Message: `crd10: (?:.*)?x509: certificate signed by unknown authority`,
 			},
 		},
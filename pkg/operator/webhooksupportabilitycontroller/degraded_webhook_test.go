@@ -3,13 +3,18 @@ package webhooksupportabilitycontroller
 import (
 	"context"
 	"crypto/tls"
+	"io"
+	"log"
 	"net"
 	"net/http"
 	"regexp"
 	"strconv"
 	"testing"
+	"time"
 
+	"github.com/foxcpp/go-mockdns"
 	"github.com/google/go-cmp/cmp"
+	"github.com/miekg/dns"
 	operatorv1 "github.com/openshift/api/operator/v1"
 	"github.com/openshift/library-go/pkg/crypto"
 	corev1 "k8s.io/api/core/v1"
@@ -63,6 +68,123 @@ func TestAssertService(t *testing.T) {
 	}
 }
 
+func TestAssertWebhook(t *testing.T) {
+	testCases := []struct {
+		name           string
+		host           string
+		options        []func(*mockWebhookServer)
+		expectedReason string
+		expectedErr    string
+	}{
+		{
+			name: "Happy",
+		},
+		{
+			name:    "Loopback",
+			host:    "127.0.0.1",
+			options: []func(*mockWebhookServer){doNotStart()},
+		},
+		{
+			name:    "LinkLocal",
+			host:    "fe80::1",
+			options: []func(*mockWebhookServer){doNotStart()},
+		},
+		{
+			name:           "LoopbackCABundleExpired",
+			host:           "localhost",
+			options:        []func(*mockWebhookServer){doNotStart(), withCALifetime(-time.Hour)},
+			expectedReason: WebhookCABundleExpiredReason,
+			expectedErr:    `caBundle expired at .+`,
+		},
+		{
+			name:           "NotReachable",
+			options:        []func(*mockWebhookServer){doNotStart()},
+			expectedReason: WebhookServiceConnectionErrorReason,
+			expectedErr:    `dial tcp 127.0.0.1:[0-9]+: connect: connection refused`,
+		},
+		{
+			name:           "CABundleExpired",
+			options:        []func(*mockWebhookServer){withCALifetime(-time.Hour)},
+			expectedReason: WebhookCABundleExpiredReason,
+			expectedErr:    `caBundle expired at .+`,
+		},
+		{
+			name:           "CABundleExpiring",
+			options:        []func(*mockWebhookServer){withCALifetime(10 * 24 * time.Hour)},
+			expectedReason: WebhookCABundleExpiringReason,
+			expectedErr:    `caBundle expires at .+`,
+		},
+		{
+			name:           "NotSignedByCABundle",
+			options:        []func(*mockWebhookServer){withWrongCABundle(t)},
+			expectedReason: WebhookServingCertNotSignedByCABundleReason,
+			expectedErr:    `.*x509: certificate signed by unknown authority.*`,
+		},
+		{
+			name:           "HostnameMismatch",
+			options:        []func(*mockWebhookServer){withServerCertHostnames("10.0.0.1")},
+			expectedReason: WebhookServingCertHostnameMismatchReason,
+			expectedErr:    `.*x509: certificate is valid for 10.0.0.1, not test.test.svc`,
+		},
+		{
+			name:           "ServingCertExpired",
+			options:        []func(*mockWebhookServer){withServerCertLifetime(-time.Hour)},
+			expectedReason: WebhookServingCertExpiredReason,
+			expectedErr:    `.*x509: certificate has expired or is not yet valid.*`,
+		},
+		{
+			name:           "ServingCertExpiring",
+			options:        []func(*mockWebhookServer){withServerCertLifetime(24 * time.Hour)},
+			expectedReason: WebhookServingCertExpiringReason,
+			expectedErr:    `serving certificate expires at .+`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			server := webhookServer("", "test", "test", tc.options...)
+			server.Run(t, ctx)
+
+			// resolve the hostname of the webhook server, unless the test targets a literal host
+			host := tc.host
+			if len(host) == 0 {
+				host = server.Hostname
+				dnsServer, err := mockdns.NewServerWithLogger(map[string]mockdns.Zone{dns.Fqdn(host): {A: []string{"127.0.0.1"}}}, log.New(io.Discard, "", log.LstdFlags), false)
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer dnsServer.Close()
+				dnsServer.PatchNet(net.DefaultResolver)
+				defer mockdns.UnpatchNet(net.DefaultResolver)
+			}
+
+			c := &webhookSupportabilityController{}
+			err := c.assertWebhook(ctx, webhookInfo{
+				Name:     "url",
+				URL:      "https://" + net.JoinHostPort(host, strconv.Itoa(int(*server.Port))) + "/validate",
+				CABundle: server.CABundle,
+			})
+			if len(tc.expectedReason) == 0 {
+				if err != nil {
+					t.Fatalf("error not expected: %s", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("error expected")
+			}
+			if err.reason != tc.expectedReason {
+				t.Errorf("expected reason %s, got %s: %v", tc.expectedReason, err.reason, err)
+			}
+			if matched, _ := regexp.MatchString(`^`+tc.expectedErr+`$`, err.Error()); !matched {
+				t.Errorf("expected error matching %q, got %q", tc.expectedErr, err.Error())
+			}
+		})
+	}
+}
+
 func requireCondition(t *testing.T, expected, actual operatorv1.OperatorCondition) {
 	matched, err := regexp.MatchString(`^`+expected.Message+`$`, actual.Message)
 	if err != nil {
@@ -109,19 +231,44 @@ func withWrongCABundle(t *testing.T) func(*mockWebhookServer) {
 	}
 }
 
+func withCALifetime(lifetime time.Duration) func(*mockWebhookServer) {
+	return func(s *mockWebhookServer) {
+		s.caLifetime = lifetime
+	}
+}
+
+func withServerCertLifetime(lifetime time.Duration) func(*mockWebhookServer) {
+	return func(s *mockWebhookServer) {
+		s.serverCertLifetime = lifetime
+	}
+}
+
+func withServerCertHostnames(hostnames ...string) func(*mockWebhookServer) {
+	return func(s *mockWebhookServer) {
+		s.serverCertHostnames = hostnames
+	}
+}
+
 type mockWebhookServer struct {
-	Config     string
-	Service    serviceReference
-	Hostname   string
-	Port       *int32
-	CABundle   []byte
-	doNotStart bool
+	Config              string
+	Service             serviceReference
+	Hostname            string
+	Port                *int32
+	CABundle            []byte
+	doNotStart          bool
+	caLifetime          time.Duration
+	serverCertLifetime  time.Duration
+	serverCertHostnames []string
 }
 
 // Run starts the mock server. Port and CABundle are available after this method returns.
 func (s *mockWebhookServer) Run(t *testing.T, ctx context.Context) {
 	// CA certs
-	rootCACertCfg, err := crypto.MakeSelfSignedCAConfig(t.Name()+"RootCA", 10)
+	caLifetime := 365 * 24 * time.Hour
+	if s.caLifetime != 0 {
+		caLifetime = s.caLifetime
+	}
+	rootCACertCfg, err := crypto.MakeSelfSignedCAConfigForDuration(t.Name()+"RootCA", caLifetime)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -130,7 +277,15 @@ func (s *mockWebhookServer) Run(t *testing.T, ctx context.Context) {
 		s.CABundle, _, err = rootCA.Config.GetPEMBytes()
 	}
 	// server certs
-	serverCertCfg, err := rootCA.MakeServerCert(sets.NewString(s.Hostname, "127.0.0.1"), 10)
+	serverCertLifetime := 10 * 24 * time.Hour
+	if s.serverCertLifetime != 0 {
+		serverCertLifetime = s.serverCertLifetime
+	}
+	hostnames := sets.NewString(s.Hostname, "127.0.0.1")
+	if len(s.serverCertHostnames) > 0 {
+		hostnames = sets.NewString(s.serverCertHostnames...)
+	}
+	serverCertCfg, err := rootCA.MakeServerCertForDuration(hostnames, serverCertLifetime)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -19,11 +19,12 @@ type webhookSupportabilityController struct {
 	mutatingWebhookLister   admissionregistrationlistersv1.MutatingWebhookConfigurationLister
 	validatingWebhookLister admissionregistrationlistersv1.ValidatingWebhookConfigurationLister
 	serviceLister           corev1listers.ServiceLister
+	namespaceLister         corev1listers.NamespaceLister
 	crdLister               apiextensionslistersv1.CustomResourceDefinitionLister
 }
 
 // NewWebhookSupportabilityController sets Degraded=True conditions when a webhook service either cannot
-// be found, or a tls connection cannot be established.
+// be found, a tls connection cannot be established, or the webhook certificates are invalid or about to expire.
 func NewWebhookSupportabilityController(
 	operatorClient v1helpers.StaticPodOperatorClient,
 	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
@@ -36,6 +37,7 @@ func NewWebhookSupportabilityController(
 		mutatingWebhookLister:   kubeInformersForAllNamespaces.Admissionregistration().V1().MutatingWebhookConfigurations().Lister(),
 		validatingWebhookLister: kubeInformersForAllNamespaces.Admissionregistration().V1().ValidatingWebhookConfigurations().Lister(),
 		serviceLister:           kubeInformersForAllNamespaces.Core().V1().Services().Lister(),
+		namespaceLister:         kubeInformersForAllNamespaces.Core().V1().Namespaces().Lister(),
 		crdLister:               apiExtensionsInformers.Apiextensions().V1().CustomResourceDefinitions().Lister(),
 	}
 	c.Controller = factory.New().
@@ -43,6 +45,7 @@ func NewWebhookSupportabilityController(
 			kubeInformersForAllNamespaces.Admissionregistration().V1().MutatingWebhookConfigurations().Informer(),
 			kubeInformersForAllNamespaces.Admissionregistration().V1().ValidatingWebhookConfigurations().Informer(),
 			kubeInformersForAllNamespaces.Core().V1().Services().Informer(),
+			kubeInformersForAllNamespaces.Core().V1().Namespaces().Informer(),
 			apiExtensionsInformers.Apiextensions().V1().CustomResourceDefinitions().Informer(),
 		).
 		WithSync(c.sync).
@@ -64,6 +67,7 @@ func (c *webhookSupportabilityController) sync(ctx context.Context, controllerCo
 	updates = append(updates, c.updateValidatingAdmissionWebhookConfigurationDegradedStatus(ctx))
 	updates = append(updates, c.updateCRDConversionWebhookConfigurationDegraded(ctx))
 	updates = append(updates, c.updateVirtualResourceAdmissionDegraded(ctx))
+	updates = append(updates, c.updateAdmissionWebhookMayBlockControlPlaneDegraded(ctx))
 
 	_, _, err = v1helpers.UpdateStatus(ctx, c.operatorClient, updates...)
 	return err
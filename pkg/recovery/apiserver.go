@@ -2,6 +2,7 @@ package recovery
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -16,9 +17,11 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apiserver/pkg/authentication/user"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapiv1 "k8s.io/client-go/tools/clientcmd/api/v1"
 	"k8s.io/klog/v2"
 
@@ -33,6 +36,7 @@ const (
 	RecoveryCofigFileName           = "config.yaml"
 	RecoveryEncryptionCofigFileName = "encryption-config"
 	AdminKubeconfigFileName         = "admin.kubeconfig"
+	RecoveryResourcesDirName        = "recovery-kube-apiserver-pod"
 
 	RecoveryPodAsset              = "assets/kube-apiserver/recovery-pod.yaml"
 	RecoveryConfigAsset           = "assets/kube-apiserver/recovery-config.yaml"
@@ -51,9 +55,20 @@ type Apiserver struct {
 }
 
 func (s *Apiserver) GetRecoveryResourcesDir() string {
+	if len(s.recoveryResourcesDir) == 0 {
+		return filepath.Join(s.StaticPodResourcesDir, RecoveryResourcesDirName)
+	}
 	return s.recoveryResourcesDir
 }
 
+func (s *Apiserver) RecoveryPodManifestPath() string {
+	return filepath.Join(s.PodManifestDir, RecoveryPodFileName)
+}
+
+func (s *Apiserver) AdminKubeconfigPath() string {
+	return filepath.Join(s.GetRecoveryResourcesDir(), AdminKubeconfigFileName)
+}
+
 func (s *Apiserver) GetKubeApiserverStaticPod() *corev1.Pod {
 	return s.kubeApiserverStaticPod
 }
@@ -177,9 +192,9 @@ func (s *Apiserver) recoveryPod() (*corev1.Pod, error) {
 	return recoveryPod, nil
 }
 
-func (s *Apiserver) Create() error {
+// Create writes the recovery apiserver manifest and its resources. On failure it removes what it has written.
+func (s *Apiserver) Create() (err error) {
 	kubeApiserverManifestPath := s.KubeApiserverManifestPath()
-	var err error
 	s.kubeApiserverStaticPod, err = ReadManifestToV1Pod(kubeApiserverManifestPath)
 	if err != nil {
 		return fmt.Errorf("failed to read kube-apiserver pod manifest at %q: %v", kubeApiserverManifestPath, err)
@@ -190,7 +205,7 @@ func (s *Apiserver) Create() error {
 		return fmt.Errorf("failed to find resource-dir: %v", err)
 	}
 
-	s.recoveryResourcesDir = filepath.Join(s.StaticPodResourcesDir, "recovery-kube-apiserver-pod")
+	s.recoveryResourcesDir = filepath.Join(s.StaticPodResourcesDir, RecoveryResourcesDirName)
 	err = os.Mkdir(s.recoveryResourcesDir, 755)
 	if err != nil {
 		if os.IsExist(err) {
@@ -198,6 +213,11 @@ func (s *Apiserver) Create() error {
 		}
 		return fmt.Errorf("failed to create recovery dir %q: %v", s.recoveryResourcesDir, err)
 	}
+	defer func() {
+		if err != nil {
+			s.removeResources()
+		}
+	}()
 
 	// Copy certs for accessing etcd
 	for src, dest := range map[string]string{
@@ -274,7 +294,7 @@ func (s *Apiserver) Create() error {
 		return fmt.Errorf("failed to marshal recovery pod: %v", err)
 	}
 
-	recoveryPodManifestPath := filepath.Join(s.PodManifestDir, RecoveryPodFileName)
+	recoveryPodManifestPath := s.RecoveryPodManifestPath()
 	err = ioutil.WriteFile(recoveryPodManifestPath, recoveryPodBytes, 644)
 	if err != nil {
 		return fmt.Errorf("failed to write recovery pod manifest %q: %v", recoveryPodManifestPath, err)
@@ -302,6 +322,9 @@ func (s *Apiserver) Create() error {
 	}
 
 	clientCertBytes, clientKeyBytes, err := clientCert.GetPEMBytes()
+	if err != nil {
+		return fmt.Errorf("failed to encode client certificate: %v", err)
+	}
 
 	s.restConfig = &rest.Config{
 		Host: "https://localhost:7443",
@@ -322,7 +345,7 @@ func (s *Apiserver) Create() error {
 		return fmt.Errorf("failed to marshal kubeconfig: %v", err)
 	}
 
-	kubeconfigPath := filepath.Join(s.recoveryResourcesDir, AdminKubeconfigFileName)
+	kubeconfigPath := s.AdminKubeconfigPath()
 	err = ioutil.WriteFile(kubeconfigPath, kubeconfigBytes, 600)
 	if err != nil {
 		return fmt.Errorf("failed to write kubeconfig %q: %v", kubeconfigPath, err)
@@ -331,8 +354,79 @@ func (s *Apiserver) Create() error {
 	return nil
 }
 
+// Exists reports whether a recovery apiserver manifest is present in the pod manifest dir.
+func (s *Apiserver) Exists() (bool, error) {
+	return fileExists(s.RecoveryPodManifestPath())
+}
+
+// LoadKubeConfig reads the admin kubeconfig written by Create so that an already running
+// recovery apiserver can be reached from a new process.
+func (s *Apiserver) LoadKubeConfig() error {
+	kubeconfigPath := s.AdminKubeconfigPath()
+	restConfig, err := clientcmd.BuildConfigFromFlags("", kubeconfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to load kubeconfig %q: %v", kubeconfigPath, err)
+	}
+
+	s.restConfig = restConfig
+	s.kubeClientSet = nil
+
+	return nil
+}
+
+// Healthz checks the /healthz endpoint of the recovery apiserver once.
+func (s *Apiserver) Healthz(ctx context.Context) error {
+	kubeClientset, err := s.GetKubeClientset()
+	if err != nil {
+		return err
+	}
+
+	body, err := kubeClientset.Discovery().RESTClient().Get().AbsPath("/healthz").DoRaw(ctx)
+	if err != nil {
+		return fmt.Errorf("recovery apiserver is not healthy: %v", err)
+	}
+	if string(body) != "ok" {
+		return fmt.Errorf("recovery apiserver is not healthy: %s", string(body))
+	}
+
+	return nil
+}
+
+// WaitForHealthz polls the recovery apiserver until it reports healthy or the context is done.
+func (s *Apiserver) WaitForHealthz(ctx context.Context) error {
+	var lastErr error
+	err := wait.PollImmediateUntil(2*time.Second, func() (bool, error) {
+		lastErr = s.Healthz(ctx)
+		if lastErr != nil {
+			klog.V(2).Infof("Waiting for recovery apiserver to become healthy: %v", lastErr)
+			return false, nil
+		}
+		return true, nil
+	}, ctx.Done())
+	if err != nil {
+		if lastErr != nil {
+			return fmt.Errorf("timed out waiting for recovery apiserver to become healthy: %v", lastErr)
+		}
+		return fmt.Errorf("timed out waiting for recovery apiserver to become healthy: %v", err)
+	}
+
+	return nil
+}
+
+// removeResources removes the recovery pod manifest and resource dir written by a failed Create.
+func (s *Apiserver) removeResources() {
+	klog.Infof("Deleting recovery pod manifest %q", s.RecoveryPodManifestPath())
+	if err := os.Remove(s.RecoveryPodManifestPath()); err != nil && !os.IsNotExist(err) {
+		klog.Errorf("Failed to remove recovery pod manifest %q: %v", s.RecoveryPodManifestPath(), err)
+	}
+	klog.Infof("Deleting resource-dir %q", s.recoveryResourcesDir)
+	if err := os.RemoveAll(s.recoveryResourcesDir); err != nil {
+		klog.Errorf("Failed to remove resource-dir %q: %v", s.recoveryResourcesDir, err)
+	}
+}
+
 func (s *Apiserver) Destroy() error {
-	recoveryPodManifestPath := filepath.Join(s.PodManifestDir, RecoveryPodFileName)
+	recoveryPodManifestPath := s.RecoveryPodManifestPath()
 
 	recoveryPod, err := ReadManifestToV1Pod(recoveryPodManifestPath)
 	if err != nil {
@@ -1,15 +1,25 @@
 package recovery
 
 import (
+	"context"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/davecgh/go-spew/spew"
+	"github.com/ghodss/yaml"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/diff"
+	"k8s.io/client-go/rest"
 )
 
 func int64Ptr(v int64) *int64 {
@@ -137,3 +147,158 @@ func TestApiserverRecoveryPod(t *testing.T) {
 		})
 	}
 }
+
+func TestApiserverLoadKubeConfigAndHealthz(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/healthz" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "recovery-apiserver")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	caFile := filepath.Join(dir, "serving-ca.crt")
+	caBytes := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := ioutil.WriteFile(caFile, caBytes, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	apiserver := &Apiserver{
+		PodManifestDir:        filepath.Join(dir, "manifests"),
+		StaticPodResourcesDir: dir,
+	}
+	if err := os.MkdirAll(apiserver.GetRecoveryResourcesDir(), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	exists, err := apiserver.Exists()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exists {
+		t.Fatalf("expected recovery apiserver not to exist")
+	}
+
+	apiserver.restConfig = &rest.Config{
+		Host:            server.URL,
+		TLSClientConfig: rest.TLSClientConfig{CAFile: caFile},
+	}
+	kubeconfig, err := apiserver.KubeConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	kubeconfigBytes, err := yaml.Marshal(kubeconfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(apiserver.AdminKubeconfigPath(), kubeconfigBytes, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := &Apiserver{
+		PodManifestDir:        apiserver.PodManifestDir,
+		StaticPodResourcesDir: apiserver.StaticPodResourcesDir,
+	}
+	if err := loaded.LoadKubeConfig(); err != nil {
+		t.Fatal(err)
+	}
+	if loaded.restConfig.Host != server.URL {
+		t.Errorf("expected host %q, got %q", server.URL, loaded.restConfig.Host)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := loaded.WaitForHealthz(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestApiserverCreateAndDestroy(t *testing.T) {
+	dir, err := ioutil.TempDir("", "recovery-apiserver")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	apiserver, err := WriteStaticPodFixture(dir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := apiserver.Create(); err != nil {
+		t.Fatal(err)
+	}
+	exists, err := apiserver.Exists()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !exists {
+		t.Fatalf("expected the recovery pod manifest at %q", apiserver.RecoveryPodManifestPath())
+	}
+	for _, name := range []string{"etcd-client.key", "etcd-client.crt", "etcd-serving-ca-bundle.crt", "serving-ca.crt", RecoveryCofigFileName, RecoveryEncryptionCofigFileName, AdminKubeconfigFileName} {
+		if _, err := os.Stat(filepath.Join(apiserver.GetRecoveryResourcesDir(), name)); err != nil {
+			t.Errorf("expected recovery resource %q: %v", name, err)
+		}
+	}
+	recoveryPod, err := ReadManifestToV1Pod(apiserver.RecoveryPodManifestPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resourceDir, err := GetVolumeHostPathPath("resource-dir", recoveryPod.Spec.Volumes); err != nil || resourceDir != apiserver.GetRecoveryResourcesDir() {
+		t.Errorf("expected the recovery pod to mount %q, got %q: %v", apiserver.GetRecoveryResourcesDir(), resourceDir, err)
+	}
+
+	if err := apiserver.Destroy(); err != nil {
+		t.Fatal(err)
+	}
+	exists, err = apiserver.Exists()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exists {
+		t.Errorf("expected the recovery pod manifest to be removed")
+	}
+	if _, err := os.Stat(apiserver.GetRecoveryResourcesDir()); !os.IsNotExist(err) {
+		t.Errorf("expected the recovery resources to be removed, got %v", err)
+	}
+	if _, err := os.Stat(apiserver.KubeApiserverManifestPath()); err != nil {
+		t.Errorf("expected the kube-apiserver manifest to be kept: %v", err)
+	}
+}
+
+func TestApiserverCreateCleansUpOnFailure(t *testing.T) {
+	dir, err := ioutil.TempDir("", "recovery-apiserver")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	apiserver, err := WriteStaticPodFixture(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := apiserver.Create(); err == nil {
+		t.Fatalf("expected an error for the missing etcd serving CA")
+	}
+	if _, err := os.Stat(apiserver.GetRecoveryResourcesDir()); !os.IsNotExist(err) {
+		t.Errorf("expected the recovery resources to be removed, got %v", err)
+	}
+	if _, err := os.Stat(apiserver.RecoveryPodManifestPath()); !os.IsNotExist(err) {
+		t.Errorf("expected no recovery pod manifest, got %v", err)
+	}
+
+	// a failed create does not block the next one
+	apiserver, err = WriteStaticPodFixture(dir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := apiserver.Create(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,55 @@
+package recovery
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// WriteStaticPodFixture writes a kube-apiserver static pod manifest and the etcd client files of its resource dir
+// into the given dir and returns the Apiserver using it. It is meant for tests of the recovery apiserver and its
+// commands; the etcd serving CA is left out unless withEtcdServingCA is set, which makes Create fail.
+func WriteStaticPodFixture(dir string, withEtcdServingCA bool) (*Apiserver, error) {
+	resourceDir := filepath.Join(dir, "static-pod-resources", "kube-apiserver-pod-1")
+	files := map[string]string{
+		"secrets/etcd-client/tls.key": "key",
+		"secrets/etcd-client/tls.crt": "cert",
+	}
+	if withEtcdServingCA {
+		files["configmaps/etcd-serving-ca/ca-bundle.crt"] = "ca"
+	}
+	for name, content := range files {
+		path := filepath.Join(resourceDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			return nil, err
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+			return nil, err
+		}
+	}
+
+	apiserver := &Apiserver{
+		PodManifestDir:        filepath.Join(dir, "manifests"),
+		StaticPodResourcesDir: filepath.Join(dir, "static-pod-resources"),
+	}
+	if err := os.MkdirAll(apiserver.PodManifestDir, 0700); err != nil {
+		return nil, err
+	}
+	manifest := `apiVersion: v1
+kind: Pod
+metadata:
+  name: kube-apiserver
+  namespace: openshift-kube-apiserver
+spec:
+  containers:
+  - name: kube-apiserver
+    image: hyperkube:latest
+  volumes:
+  - name: resource-dir
+    hostPath:
+      path: ` + resourceDir + "\n"
+	if err := ioutil.WriteFile(apiserver.KubeApiserverManifestPath(), []byte(manifest), 0600); err != nil {
+		return nil, err
+	}
+	return apiserver, nil
+}
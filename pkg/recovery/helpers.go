@@ -76,7 +76,7 @@ func EnsureFileContent(filePath string, data []byte) error {
 	klog.V(1).Infof("Reconciling file %q", filePath)
 
 	exists := true
-	mode := os.FileMode(600) // default mode
+	mode := os.FileMode(0600) // default mode
 
 	fileInfo, err := os.Stat(filePath)
 	if err != nil {
@@ -122,3 +122,12 @@ func EnsureFileContent(filePath string, data []byte) error {
 
 	return nil
 }
+
+func EnsureDir(dirPath string) error {
+	err := os.MkdirAll(dirPath, 0755)
+	if err != nil {
+		return fmt.Errorf("failed to create directory %q: %v", dirPath, err)
+	}
+
+	return nil
+}